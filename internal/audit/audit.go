@@ -0,0 +1,199 @@
+// Package audit keeps an append-only log of every change clsm makes to
+// Claude Code's files, so a session that goes missing can be told apart
+// from one Claude Code removed. The log is JSON lines in audit.jsonl in
+// clsm's config directory; entries are only ever appended.
+//
+// Stores record their own changes, so the CLI and the TUIs are covered
+// alike. Recording never fails a change: the first error is kept for Err
+// and reported once the command is done.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/meta"
+)
+
+// Operations recorded in the log.
+const (
+	OpDelete  = "delete"  // a session, memory or plan was deleted
+	OpPrune   = "prune"   // an empty session was deleted by prune
+	OpRename  = "rename"  // a session was given a new title
+	OpCopy    = "copy"    // a session was copied to another data directory
+	OpMove    = "move"    // a session was moved to another data directory
+	OpImport  = "import"  // a session was imported from a bundle
+	OpRedact  = "redact"  // text was redacted in a file
+	OpSync    = "sync"    // a file was changed by syncing with a mirror
+	OpEdit    = "edit"    // a file was changed in $EDITOR from clsm
+	OpRestore = "restore" // an index file was rolled back to a backup
+	OpSweep   = "sweep"   // an artifact of a session that no longer exists was removed
+)
+
+// Kinds of item an entry is about.
+const (
+	KindSession  = "session"
+	KindMemory   = "memory"
+	KindPlan     = "plan"
+	KindIndex    = "index"
+	KindArtifact = "artifact" // todos, file history and the like kept for a session
+)
+
+// Entry is one change.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Op      string    `json:"op"`
+	Kind    string    `json:"kind"`
+	ID      string    `json:"id,omitempty"`      // session ID, or memory or plan file name
+	Paths   []string  `json:"paths"`             // files created, changed or removed
+	Before  string    `json:"before,omitempty"`  // the old value, such as a title or path
+	After   string    `json:"after,omitempty"`   // the new value
+	Backup  string    `json:"backup,omitempty"`  // where the old contents were copied
+	Command string    `json:"command,omitempty"` // the clsm command that made the change
+}
+
+var (
+	mu       sync.Mutex
+	command  string
+	firstErr error
+)
+
+// SetCommand sets the clsm command recorded with every entry, such as
+// "clsm dupes". Arguments are left out, since they may hold the very text
+// being redacted.
+func SetCommand(c string) {
+	mu.Lock()
+	defer mu.Unlock()
+	command = c
+}
+
+// Err returns the first error met while recording, if any.
+func Err() error {
+	mu.Lock()
+	defer mu.Unlock()
+	return firstErr
+}
+
+// Path returns the log file.
+func Path() (string, error) {
+	dir, err := meta.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit.jsonl"), nil
+}
+
+// Record appends entries to the log, stamping each with the time and the
+// current command.
+func Record(entries ...Entry) {
+	if len(entries) == 0 {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if err := write(entries); err != nil && firstErr == nil {
+		firstErr = fmt.Errorf("recording in the audit log: %w", err)
+	}
+}
+
+func write(entries []Entry) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	now := time.Now()
+	for _, e := range entries {
+		if e.Time.IsZero() {
+			e.Time = now
+		}
+		if e.Command == "" {
+			e.Command = command
+		}
+		if e.Paths == nil {
+			e.Paths = []string{}
+		}
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// One write per call, so lines from clsm processes running at once
+	// don't interleave.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns every entry in the log, oldest first. Lines that don't
+// parse, such as one cut short by a crash, are skipped.
+func Read() ([]Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var e Entry
+		if json.Unmarshal(sc.Bytes(), &e) == nil && e.Op != "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries, sc.Err()
+}
+
+// TrackEdit notes a file's contents before it is opened in an editor. The
+// returned function, called when the editor exits, records an edit if
+// the file changed.
+func TrackEdit(kind, id, path string) func() {
+	before := fileSum(path)
+	return func() {
+		if after := fileSum(path); after != before {
+			Record(Entry{Op: OpEdit, Kind: kind, ID: id, Paths: []string{path}})
+		}
+	}
+}
+
+// fileSum returns a hash of a file's contents, or "" if it can't be read.
+func fileSum(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
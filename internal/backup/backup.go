@@ -0,0 +1,190 @@
+// Package backup keeps rolling copies of the index files clsm rewrites,
+// sessions-index.json and MEMORY.md, so a bad delete, import or sync can
+// be rolled back. Each index is copied under backups/index/ in clsm's
+// config directory, at its full path, before every change; the newest
+// Keep copies of each are kept.
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/atomicfile"
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/meta"
+)
+
+// Keep is how many backups of each index file are kept.
+const Keep = 20
+
+// timeFormat names backup files. It sorts in time order and is fine
+// grained enough for one backup per change.
+const timeFormat = "20060102-150405.000000000"
+
+// Index is an index file that has backups.
+type Index struct {
+	Path    string   // the index file
+	Backups []Backup // newest first
+}
+
+// Backup is one copy of an index file.
+type Backup struct {
+	Path string // the copy under clsm's config directory
+	Time time.Time
+	Size int64
+}
+
+// IsIndex reports whether path is an index file clsm keeps backups of.
+func IsIndex(path string) bool {
+	switch filepath.Base(path) {
+	case "sessions-index.json", "MEMORY.md":
+		return true
+	}
+	return false
+}
+
+func dir() (string, error) {
+	d, err := meta.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, "backups", "index"), nil
+}
+
+// backupDir returns the directory the backups of the index at path go in.
+func backupDir(path string) (string, error) {
+	d, err := dir()
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, strings.TrimPrefix(abs, string(filepath.Separator))), nil
+}
+
+// Save copies the index at path, as root has it, into a new backup and
+// drops the oldest ones beyond Keep. An index that doesn't exist yet, or
+// is the same as its newest backup, isn't copied.
+func Save(root *claude.Root, path string) error {
+	data, err := root.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	d, err := backupDir(path)
+	if err != nil {
+		return err
+	}
+	backups, err := list(d)
+	if err != nil {
+		return err
+	}
+	if len(backups) > 0 {
+		if last, err := os.ReadFile(backups[0].Path); err == nil && bytes.Equal(last, data) {
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(d, 0o700); err != nil {
+		return fmt.Errorf("backing up %s: %w", path, err)
+	}
+	name := filepath.Join(d, time.Now().Format(timeFormat))
+	if err := atomicfile.WriteFile(name, data, 0o600); err != nil {
+		return fmt.Errorf("backing up %s: %w", path, err)
+	}
+	for i := Keep - 1; i < len(backups); i++ {
+		os.Remove(backups[i].Path)
+	}
+	return nil
+}
+
+// List returns the index files that have backups, sorted by path.
+func List() ([]Index, error) {
+	root, err := dir()
+	if err != nil {
+		return nil, err
+	}
+	var out []Index
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == root {
+			return fs.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || !IsIndex(p) {
+			return nil
+		}
+		backups, err := list(p)
+		if err != nil {
+			return err
+		}
+		if len(backups) > 0 {
+			rel, _ := filepath.Rel(root, p)
+			out = append(out, Index{Path: string(filepath.Separator) + rel, Backups: backups})
+		}
+		return fs.SkipDir
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out, nil
+}
+
+// list returns the backups in d, newest first.
+func list(d string) ([]Backup, error) {
+	entries, err := os.ReadDir(d)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []Backup
+	for _, e := range entries {
+		t, err := time.ParseInLocation(timeFormat, e.Name(), time.Local)
+		if err != nil || e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		out = append(out, Backup{Path: filepath.Join(d, e.Name()), Time: t, Size: info.Size()})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Time.After(out[j].Time) })
+	return out, nil
+}
+
+// Restore puts a backup back in place of the index at path. The index as
+// it is now is backed up first, so a restore can itself be undone.
+func Restore(root *claude.Root, path string, b Backup) error {
+	data, err := os.ReadFile(b.Path)
+	if err != nil {
+		return err
+	}
+	if err := Save(root, path); err != nil {
+		return err
+	}
+	if err := root.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := root.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	audit.Record(audit.Entry{Op: audit.OpRestore, Kind: audit.KindIndex, Paths: []string{path}, After: "backup of " + b.Time.Format(time.RFC3339)})
+	return nil
+}
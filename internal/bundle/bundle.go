@@ -0,0 +1,250 @@
+// Package bundle packs sessions into a self-contained bundle that can be
+// shared or carried to another machine: a directory, or a gzipped tar file
+// of the same directory. A bundle holds each session's transcript, its
+// subagent transcripts, its sessions-index.json entry and its clsm tags
+// and note, described by manifest.json.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/atomicfile"
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/meta"
+	"github.com/baz-sh/clsm/internal/redact"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+// Version is the current version of the bundle format.
+const Version = 1
+
+// ManifestName is the name of the manifest inside a bundle.
+const ManifestName = "manifest.json"
+
+// Manifest describes the contents of a bundle.
+type Manifest struct {
+	Version   int       `json:"version"`
+	Created   string    `json:"created"`
+	Sanitized bool      `json:"sanitized"`
+	Sessions  []Session `json:"sessions"`
+}
+
+// Session is one session in a bundle.
+type Session struct {
+	ID          string          `json:"id"`
+	ProjectPath string          `json:"projectPath"`
+	Title       string          `json:"title"`
+	File        string          `json:"file"` // transcript, relative to the bundle root
+	Subagents   []File          `json:"subagents,omitempty"`
+	Index       json.RawMessage `json:"index,omitempty"` // the sessions-index.json entry as Claude Code wrote it
+	Tags        []string        `json:"tags,omitempty"`
+	Pinned      bool            `json:"pinned,omitempty"`
+	Note        string          `json:"note,omitempty"`
+}
+
+// File is a file of a session other than its transcript.
+type File struct {
+	Path string `json:"path"` // relative to the bundle root
+	Rel  string `json:"rel"`  // relative to the session's project directory
+}
+
+// IsArchive reports whether a bundle path names a gzipped tar file rather
+// than a directory.
+func IsArchive(p string) bool {
+	return strings.HasSuffix(p, ".tar.gz") || strings.HasSuffix(p, ".tgz")
+}
+
+// Export writes the sessions to a new bundle at dest. When r is not nil it
+// is applied to every transcript, index entry, title, tag and note on the
+// way out; the originals are not changed.
+func Export(dest string, sessions []session.Session, r redact.Replacer) (Manifest, error) {
+	if _, err := os.Stat(dest); err == nil {
+		return Manifest{}, fmt.Errorf("%s already exists", dest)
+	}
+	w, err := newWriter(dest)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	m := Manifest{Version: Version, Created: time.Now().UTC().Format(time.RFC3339), Sanitized: r != nil}
+	text := func(s string) string {
+		if r != nil {
+			s, _ = r(s)
+		}
+		return s
+	}
+	for _, s := range sessions {
+		bs, err := exportSession(w, s, r, text)
+		if err != nil {
+			w.abort()
+			return Manifest{}, fmt.Errorf("%s: %w", s.SessionID, err)
+		}
+		m.Sessions = append(m.Sessions, bs)
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err == nil {
+		err = w.write(ManifestName, append(data, '\n'))
+	}
+	if err == nil {
+		err = w.close()
+	}
+	if err != nil {
+		w.abort()
+		return Manifest{}, err
+	}
+	return m, nil
+}
+
+func exportSession(w writer, s session.Session, r redact.Replacer, text func(string) string) (Session, error) {
+	bs := Session{
+		ID:          s.SessionID,
+		ProjectPath: text(s.ProjectPath),
+		Title:       text(s.Title()),
+		File:        path.Join("sessions", s.SessionID+".jsonl"),
+		Pinned:      s.Pinned,
+	}
+	for _, t := range s.Tags {
+		bs.Tags = append(bs.Tags, text(t))
+	}
+
+	if err := copyJSONL(w, bs.File, s.FullPath, r); err != nil {
+		return Session{}, err
+	}
+	projDir := filepath.Dir(s.FullPath)
+	for _, a := range s.Subagents {
+		rel, err := filepath.Rel(projDir, a.FullPath)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			rel = filepath.Base(a.FullPath)
+		}
+		rel = filepath.ToSlash(rel)
+		f := File{Path: path.Join("sessions", s.SessionID, "files", rel), Rel: rel}
+		if err := copyJSONL(w, f.Path, a.FullPath, r); err != nil {
+			return Session{}, err
+		}
+		bs.Subagents = append(bs.Subagents, f)
+	}
+
+	if entry := indexEntry(filepath.Join(projDir, "sessions-index.json"), s.SessionID); entry != nil {
+		if r != nil {
+			entry, _ = redact.RewriteJSON(entry, r)
+			entry, _ = redact.RewriteFields(entry, redact.PathKeys, r)
+		}
+		bs.Index = entry
+	}
+	if note, err := meta.ReadNote(meta.NoteSession, s.SessionID); err == nil {
+		bs.Note = text(note)
+	}
+	return bs, nil
+}
+
+// copyJSONL copies a transcript into the bundle, rewriting it with r if r
+// is not nil, paths included.
+func copyJSONL(w writer, name, src string, r redact.Replacer) error {
+	data, err := claude.RootOf(src).ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if r != nil {
+		data, _ = redact.RewriteJSONL(data, r)
+		data, _ = redact.RewriteFieldsJSONL(data, redact.PathKeys, r)
+	}
+	return w.write(name, data)
+}
+
+// indexEntry returns the raw sessions-index.json entry for a session, or
+// nil if the index has none.
+func indexEntry(idxPath, sessionID string) json.RawMessage {
+	data, err := claude.RootOf(idxPath).ReadFile(idxPath)
+	if err != nil {
+		return nil
+	}
+	var idx struct {
+		Entries []json.RawMessage `json:"entries"`
+	}
+	if json.Unmarshal(data, &idx) != nil {
+		return nil
+	}
+	for _, e := range idx.Entries {
+		var id struct {
+			SessionID string `json:"sessionId"`
+		}
+		if json.Unmarshal(e, &id) == nil && id.SessionID == sessionID {
+			return e
+		}
+	}
+	return nil
+}
+
+// writer adds files to a bundle under construction.
+type writer interface {
+	write(name string, data []byte) error
+	close() error
+	abort() // remove whatever was written
+}
+
+func newWriter(dest string) (writer, error) {
+	if IsArchive(dest) {
+		f, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		gz := gzip.NewWriter(f)
+		return &tarWriter{f: f, gz: gz, tw: tar.NewWriter(gz)}, nil
+	}
+	if err := os.Mkdir(dest, 0o700); err != nil {
+		return nil, err
+	}
+	return dirWriter(dest), nil
+}
+
+type dirWriter string
+
+func (d dirWriter) write(name string, data []byte) error {
+	p := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(p, data, 0o600)
+}
+
+func (d dirWriter) close() error { return nil }
+func (d dirWriter) abort()       { os.RemoveAll(string(d)) }
+
+type tarWriter struct {
+	f  *os.File
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func (t *tarWriter) write(name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := t.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := t.tw.Write(data)
+	return err
+}
+
+func (t *tarWriter) close() error {
+	return errors.Join(t.tw.Close(), t.gz.Close(), t.f.Close())
+}
+
+func (t *tarWriter) abort() {
+	t.f.Close()
+	os.Remove(t.f.Name())
+}
@@ -0,0 +1,511 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/meta"
+	"github.com/baz-sh/clsm/internal/redact"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+// Import statuses.
+const (
+	StatusImported = "imported" // written under its own ID
+	StatusRenamed  = "renamed"  // written under a new ID because its ID was taken
+	StatusUpdated  = "updated"  // an earlier copy was extended with newer messages
+	StatusExists   = "exists"   // already present with the same or newer messages
+	StatusConflict = "conflict" // its ID is taken by a different session
+	StatusLive     = "live"     // the earlier copy is one Claude Code is writing; left alone
+	StatusFailed   = "failed"
+)
+
+// Bundle is an opened bundle.
+type Bundle struct {
+	Manifest
+	files source
+}
+
+// source reads the files of a bundle.
+type source interface {
+	read(name string) ([]byte, error)
+}
+
+type dirSource string
+
+func (d dirSource) read(name string) ([]byte, error) {
+	if err := checkPath(name); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(string(d), filepath.FromSlash(name)))
+}
+
+type archiveSource map[string][]byte
+
+func (a archiveSource) read(name string) ([]byte, error) {
+	data, ok := a[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+	}
+	return data, nil
+}
+
+// Open reads a bundle written by Export, as an archive or a directory. A
+// directory without a manifest is read as Claude Code project files, such
+// as a copy of another machine's ~/.claude/projects or of one project in
+// it.
+func Open(src string) (*Bundle, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+	var files source
+	if info.IsDir() {
+		files = dirSource(src)
+	} else {
+		if files, err = readArchive(src); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", src, err)
+		}
+	}
+
+	data, err := files.read(ManifestName)
+	if errors.Is(err, fs.ErrNotExist) && info.IsDir() {
+		m, err := scanProjects(src)
+		if err != nil {
+			return nil, err
+		}
+		return &Bundle{Manifest: m, files: files}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ManifestName, err)
+	}
+	if m.Version > Version {
+		return nil, fmt.Errorf("bundle has version %d; this clsm only understands up to %d", m.Version, Version)
+	}
+	return &Bundle{Manifest: m, files: files}, nil
+}
+
+func readArchive(src string) (archiveSource, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	files := make(archiveSource)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Clean(hdr.Name)] = data
+	}
+}
+
+// scanProjects builds a manifest for a directory of Claude Code project
+// files: every session transcript below root, with its subagents and
+// index entry.
+func scanProjects(root string) (Manifest, error) {
+	m := Manifest{Version: Version}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() || !strings.HasSuffix(name, ".jsonl") ||
+			strings.HasPrefix(name, "agent-") || filepath.Base(filepath.Dir(p)) == "subagents" {
+			return nil
+		}
+		s, err := scanSession(root, p)
+		if err != nil {
+			return err
+		}
+		m.Sessions = append(m.Sessions, s)
+		return nil
+	})
+	if err != nil {
+		return Manifest{}, err
+	}
+	if len(m.Sessions) == 0 {
+		return Manifest{}, fmt.Errorf("no %s and no session transcripts in %s", ManifestName, root)
+	}
+	return m, nil
+}
+
+func scanSession(root, p string) (Session, error) {
+	dir := filepath.Dir(p)
+	id := strings.TrimSuffix(filepath.Base(p), ".jsonl")
+	rel := func(f string) string {
+		r, _ := filepath.Rel(root, f)
+		return filepath.ToSlash(r)
+	}
+	s := Session{ID: id, File: rel(p), Title: id}
+
+	var entry struct {
+		ProjectPath string `json:"projectPath"`
+		Summary     string `json:"summary"`
+		FirstPrompt string `json:"firstPrompt"`
+	}
+	if raw := indexEntry(filepath.Join(dir, session.IndexFileName), id); raw != nil {
+		s.Index = raw
+		json.Unmarshal(raw, &entry)
+	}
+	s.ProjectPath = entry.ProjectPath
+	if s.ProjectPath == "" {
+		s.ProjectPath = firstField(p, "cwd")
+	}
+	switch {
+	case entry.Summary != "":
+		s.Title = entry.Summary
+	case entry.FirstPrompt != "":
+		s.Title = entry.FirstPrompt
+	}
+
+	agents, _ := filepath.Glob(filepath.Join(dir, "agent-*.jsonl"))
+	for _, a := range agents {
+		if firstField(a, "sessionId") == id {
+			s.Subagents = append(s.Subagents, File{Path: rel(a), Rel: filepath.Base(a)})
+		}
+	}
+	nested, _ := filepath.Glob(filepath.Join(dir, id, "subagents", "*.jsonl"))
+	for _, a := range nested {
+		r, _ := filepath.Rel(dir, a)
+		s.Subagents = append(s.Subagents, File{Path: rel(a), Rel: filepath.ToSlash(r)})
+	}
+	return s, nil
+}
+
+// firstField returns the first non-empty value of a top-level string field
+// in a transcript.
+func firstField(p, key string) string {
+	f, err := os.Open(p)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var entry map[string]any
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			continue
+		}
+		if v, ok := entry[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Mapping rewrites paths that start with From to start with To instead.
+type Mapping struct {
+	From, To string
+}
+
+// ParseMapping parses a --map value of the form from=to.
+func ParseMapping(s string) (Mapping, error) {
+	from, to, ok := strings.Cut(s, "=")
+	from, to = strings.TrimRight(from, "/"), strings.TrimRight(to, "/")
+	if !ok || from == "" || to == "" {
+		return Mapping{}, fmt.Errorf("invalid mapping %q: want from=to, such as /Users/alice=/home/alice", s)
+	}
+	return Mapping{From: from, To: to}, nil
+}
+
+// MapPath rewrites p with the mapping whose From is the longest prefix of
+// it, matching whole path elements. A path that still starts with ~, as
+// sanitized bundles do, is then taken to be in the home directory.
+func MapPath(p string, maps []Mapping) string {
+	best := -1
+	for i, m := range maps {
+		if (p == m.From || strings.HasPrefix(p, m.From+"/")) && (best < 0 || len(m.From) > len(maps[best].From)) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		p = maps[best].To + p[len(maps[best].From):]
+	}
+	return claude.ExpandHome(p)
+}
+
+// ImportOptions controls Import.
+type ImportOptions struct {
+	Maps   []Mapping
+	Rename bool // import sessions whose ID is taken under a new ID
+	DryRun bool // work out what would happen without writing
+	Force  bool // also update sessions Claude Code is writing
+}
+
+// Result is the outcome of importing one session.
+type Result struct {
+	ID          string `json:"id"`
+	NewID       string `json:"newId,omitempty"` // set when the session was renamed
+	ProjectPath string `json:"projectPath"`
+	Title       string `json:"title"`
+	Path        string `json:"path,omitempty"` // the transcript's path after import
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Import copies the bundle's sessions into Claude Code's projects
+// directory, under the project directory of each session's mapped path.
+// The cwd of every transcript entry and the projectPath and fullPath of
+// each index entry are rewritten to match, index entries are merged into
+// the project's sessions-index.json, and clsm tags, pins and notes are
+// restored.
+func (b *Bundle) Import(opts ImportOptions) []Result {
+	results := make([]Result, 0, len(b.Sessions))
+	for _, s := range b.Sessions {
+		r := Result{ID: s.ID, Title: s.Title, ProjectPath: MapPath(s.ProjectPath, opts.Maps)}
+		if err := b.importSession(s, &r, opts); err != nil {
+			r.Status, r.Error = StatusFailed, err.Error()
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func (b *Bundle) importSession(s Session, r *Result, opts ImportOptions) (err error) {
+	if err := checkSession(s); err != nil {
+		return err
+	}
+	if !filepath.IsAbs(r.ProjectPath) {
+		return fmt.Errorf("project path %q is not absolute; add a --map for it", r.ProjectPath)
+	}
+	mapper := func(text string) (string, int) {
+		if mapped := MapPath(text, opts.Maps); mapped != text {
+			return mapped, 1
+		}
+		return text, 0
+	}
+	data, err := b.files.read(s.File)
+	if err != nil {
+		return err
+	}
+	data, _ = redact.RewriteFieldsJSONL(data, []string{"cwd"}, mapper)
+
+	id := s.ID
+	root := claude.Current()
+	projDir := filepath.Join(root.ProjectsDir(), session.EncodeDirName(r.ProjectPath))
+	dest := filepath.Join(projDir, id+".jsonl")
+	r.Status = StatusImported
+	if existing := findTranscript(id); existing != "" {
+		old, err := root.ReadFile(existing)
+		if err != nil {
+			return err
+		}
+		switch {
+		case bytes.HasPrefix(old, data):
+			r.Status, r.Path = StatusExists, existing
+			return nil
+		case bytes.HasPrefix(data, old):
+			if !opts.Force {
+				if err := session.CheckLive(session.Session{SessionID: id, FullPath: existing, Profile: root.Name}); err != nil {
+					r.Status, r.Path, r.Error = StatusLive, existing, err.Error()+"; use --force to update it anyway"
+					return nil
+				}
+			}
+			r.Status, dest = StatusUpdated, existing
+			projDir = filepath.Dir(existing)
+		case !opts.Rename:
+			r.Status, r.Path = StatusConflict, existing
+			return nil
+		default:
+			id = newSessionID()
+			r.Status, r.NewID = StatusRenamed, id
+			dest = filepath.Join(projDir, id+".jsonl")
+			data, _ = redact.RewriteFieldsJSONL(data, []string{"sessionId"}, replaceValue(s.ID, id))
+		}
+	}
+	r.Path = dest
+	if s.Index != nil {
+		if err := session.CheckIndex(root, filepath.Join(projDir, session.IndexFileName)); err != nil {
+			return err
+		}
+	}
+	if opts.DryRun {
+		return nil
+	}
+
+	if err := root.MkdirAll(projDir, 0o755); err != nil {
+		return err
+	}
+	if err := root.WriteFile(dest, data, 0o644); err != nil {
+		return err
+	}
+	written := []string{dest}
+	// Recorded once the outcome is known, so a write that fails after the
+	// transcript's is logged as a failed import.
+	defer func() {
+		e := audit.Entry{Op: audit.OpImport, Kind: audit.KindSession, ID: id, Paths: written, After: r.Status}
+		if err != nil {
+			e.After = StatusFailed
+		}
+		if id != s.ID {
+			e.Before = s.ID
+		}
+		audit.Record(e)
+	}()
+	for _, f := range s.Subagents {
+		sub, err := b.files.read(f.Path)
+		if err != nil {
+			return err
+		}
+		sub, _ = redact.RewriteFieldsJSONL(sub, []string{"cwd"}, mapper)
+		rel := f.Rel
+		if id != s.ID {
+			sub, _ = redact.RewriteFieldsJSONL(sub, []string{"sessionId"}, replaceValue(s.ID, id))
+			if strings.HasPrefix(rel, s.ID+"/") {
+				rel = id + rel[len(s.ID):]
+			}
+		}
+		p := filepath.Join(projDir, filepath.FromSlash(rel))
+		if !strings.HasPrefix(p, projDir+string(filepath.Separator)) {
+			return fmt.Errorf("subagent path %q leaves the project directory", f.Rel)
+		}
+		if err := root.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		if err := root.WriteFile(p, sub, 0o644); err != nil {
+			return err
+		}
+		written = append(written, p)
+	}
+
+	if s.Index != nil {
+		entry, _ := redact.RewriteFields(s.Index, []string{"projectPath"}, mapper)
+		entry, _ = redact.RewriteFields(entry, []string{"fullPath"}, func(string) (string, int) { return dest, 1 })
+		if id != s.ID {
+			entry, _ = redact.RewriteFields(entry, []string{"sessionId"}, replaceValue(s.ID, id))
+		}
+		if err := session.MergeIndexEntry(root, filepath.Join(projDir, session.IndexFileName), entry, r.ProjectPath); err != nil {
+			return fmt.Errorf("updating index: %w", err)
+		}
+		var times struct {
+			Modified string `json:"modified"`
+		}
+		if json.Unmarshal(entry, &times) == nil {
+			if t, err := time.Parse(time.RFC3339Nano, times.Modified); err == nil {
+				root.Chtimes(dest, t, t)
+			}
+		}
+	}
+	return restoreMeta(s, id)
+}
+
+// checkSession refuses a manifest entry that could write or read outside
+// where it should: an ID that isn't a session ID, as it names the files
+// written, or a path that leaves the bundle.
+func checkSession(s Session) error {
+	if !session.IsSessionID(s.ID) {
+		return fmt.Errorf("%q is not a session ID", s.ID)
+	}
+	if err := checkPath(s.File); err != nil {
+		return err
+	}
+	for _, f := range s.Subagents {
+		if err := checkPath(f.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPath refuses a path in a bundle that is absolute or leaves it.
+func checkPath(name string) error {
+	clean := path.Clean(filepath.ToSlash(name))
+	if path.IsAbs(clean) || filepath.IsAbs(name) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("path %q leaves the bundle", name)
+	}
+	return nil
+}
+
+// restoreMeta adds the bundle's tags, pin and note to the imported
+// session. A note already present is kept.
+func restoreMeta(s Session, id string) error {
+	if len(s.Tags) > 0 || s.Pinned {
+		err := meta.Update(func(st *meta.Store) error {
+			st.Tag(id, s.Tags...)
+			if s.Pinned {
+				st.SetPinned(id, true)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if s.Note != "" {
+		if existing, _ := meta.ReadNote(meta.NoteSession, id); existing == "" {
+			return meta.WriteNote(meta.NoteSession, id, s.Note)
+		}
+	}
+	return nil
+}
+
+// findTranscript returns the path of the session's transcript in any
+// project, or "".
+func findTranscript(id string) string {
+	root := claude.Current()
+	matches, _ := root.Glob(filepath.Join(root.ProjectsDir(), "*", id+".jsonl"))
+	sort.Strings(matches)
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[0]
+}
+
+// replaceValue returns a Replacer that turns a whole value of old into
+// new.
+func replaceValue(old, new string) redact.Replacer {
+	return func(text string) (string, int) {
+		if text == old {
+			return new, 1
+		}
+		return text, 0
+	}
+}
+
+// newSessionID returns a random version 4 UUID, the form Claude Code uses
+// for session IDs.
+func newSessionID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,125 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+func TestMapPath(t *testing.T) {
+	t.Setenv("HOME", "/home/bob")
+	maps := []Mapping{
+		{From: "/Users/alice", To: "/home/alice"},
+		{From: "/Users/alice/work", To: "/srv/work"},
+	}
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"exact", "/Users/alice", "/home/alice"},
+		{"below", "/Users/alice/src/app", "/home/alice/src/app"},
+		{"longest prefix wins", "/Users/alice/work/app", "/srv/work/app"},
+		{"whole elements only", "/Users/alicex/app", "/Users/alicex/app"},
+		{"unmapped", "/opt/app", "/opt/app"},
+		{"home", "~/src/app", "/home/bob/src/app"},
+		{"bare home", "~", "/home/bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MapPath(tt.in, maps); got != tt.want {
+				t.Errorf("MapPath(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Mapping
+		wantErr bool
+	}{
+		{"/Users/alice=/home/alice", Mapping{"/Users/alice", "/home/alice"}, false},
+		{"/Users/alice/=/home/alice/", Mapping{"/Users/alice", "/home/alice"}, false},
+		{"/Users/alice", Mapping{}, true},
+		{"=/home/alice", Mapping{}, true},
+		{"/Users/alice=", Mapping{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMapping(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMapping(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckSession(t *testing.T) {
+	const id = "3f2a8c1e-0b4d-4e5f-9a6b-7c8d9e0f1a2b"
+	tests := []struct {
+		name    string
+		s       Session
+		wantErr bool
+	}{
+		{"valid", Session{ID: id, File: "sessions/" + id + ".jsonl", Subagents: []File{{Path: "sessions/" + id + "/subagents/agent-1.jsonl"}}}, false},
+		{"dot segments inside", Session{ID: id, File: "sessions/./x/../" + id + ".jsonl"}, false},
+		{"id with path", Session{ID: "../../evil", File: "sessions/a.jsonl"}, true},
+		{"id uppercase", Session{ID: "3F2A8C1E-0B4D-4E5F-9A6B-7C8D9E0F1A2B", File: "sessions/a.jsonl"}, true},
+		{"empty id", Session{File: "sessions/a.jsonl"}, true},
+		{"absolute file", Session{ID: id, File: "/etc/passwd"}, true},
+		{"file leaves bundle", Session{ID: id, File: "../../etc/passwd"}, true},
+		{"file leaves after descending", Session{ID: id, File: "sessions/../../x.jsonl"}, true},
+		{"parent dir", Session{ID: id, File: ".."}, true},
+		{"subagent leaves bundle", Session{ID: id, File: "sessions/a.jsonl", Subagents: []File{{Path: "../agent.jsonl"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkSession(tt.s); (err != nil) != tt.wantErr {
+				t.Errorf("checkSession: got %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportRecordsOutcome(t *testing.T) {
+	const id = "3f2a8c1e-0b4d-4e5f-9a6b-7c8d9e0f1a2b"
+	files := archiveSource{
+		"sessions/" + id + ".jsonl":                   []byte("{\"type\":\"user\"}\n"),
+		"sessions/" + id + "/subagents/agent-1.jsonl": []byte("{\"type\":\"user\"}\n"),
+	}
+	tests := []struct {
+		name     string
+		subagent string // bundle path of the subagent transcript
+		want     string
+	}{
+		{"imported", "sessions/" + id + "/subagents/agent-1.jsonl", StatusImported},
+		{"subagent missing", "sessions/" + id + "/subagents/agent-2.jsonl", StatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claude.Use(claude.New(t.TempDir()))
+			t.Cleanup(func() { claude.UseAll(nil) })
+			t.Setenv("CLSM_CONFIG_DIR", t.TempDir())
+
+			b := &Bundle{files: files}
+			b.Sessions = []Session{{
+				ID:          id,
+				ProjectPath: "/p",
+				File:        "sessions/" + id + ".jsonl",
+				Subagents:   []File{{Path: tt.subagent, Rel: id + "/subagents/agent-1.jsonl"}},
+			}}
+			results := b.Import(ImportOptions{})
+			if got := results[0].Status; got != tt.want {
+				t.Errorf("status %s (%s), want %s", got, results[0].Error, tt.want)
+			}
+			entries, err := audit.Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 || entries[0].After != tt.want {
+				t.Errorf("audit log %+v, want one entry after %s", entries, tt.want)
+			}
+		})
+	}
+}
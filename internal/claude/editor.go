@@ -0,0 +1,12 @@
+package claude
+
+import "os"
+
+// Editor returns the user's $EDITOR, or vi, for opening transcripts, plans
+// and notes.
+func Editor() string {
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	return "vi"
+}
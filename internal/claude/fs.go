@@ -0,0 +1,150 @@
+package claude
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/atomicfile"
+)
+
+// Open opens a file for reading.
+func (r *Root) Open(path string) (fs.File, error) {
+	f, err := r.fsys.Open(name(path))
+	return f, pathErr(err, path)
+}
+
+// ReadFile reads a whole file.
+func (r *Root) ReadFile(path string) ([]byte, error) {
+	data, err := fs.ReadFile(r.fsys, name(path))
+	return data, pathErr(err, path)
+}
+
+// ReadDir reads a directory, sorted by name.
+func (r *Root) ReadDir(path string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(r.fsys, name(path))
+	return entries, pathErr(err, path)
+}
+
+// Stat describes a file.
+func (r *Root) Stat(path string) (fs.FileInfo, error) {
+	info, err := fs.Stat(r.fsys, name(path))
+	return info, pathErr(err, path)
+}
+
+// Glob returns the paths matching pattern, as filepath.Glob does.
+func (r *Root) Glob(pattern string) ([]string, error) {
+	matches, err := fs.Glob(r.fsys, name(pattern))
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range matches {
+		matches[i] = osPath(m)
+	}
+	return matches, nil
+}
+
+// WriteFile replaces a whole file, creating it with perm if needed. On
+// the local file system the file is replaced atomically, so a crash
+// leaves either the old contents or the new.
+func (r *Root) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	return pathErr(r.w.WriteFile(name(path), data, perm), path)
+}
+
+// AppendFile appends data to an existing file.
+func (r *Root) AppendFile(path string, data []byte) error {
+	return pathErr(r.w.AppendFile(name(path), data), path)
+}
+
+// Remove removes a file or an empty directory.
+func (r *Root) Remove(path string) error {
+	return pathErr(r.w.Remove(name(path)), path)
+}
+
+// Rename moves a file, replacing any file at newpath.
+func (r *Root) Rename(oldpath, newpath string) error {
+	return linkErr(r.w.Rename(name(oldpath), name(newpath)), oldpath, newpath)
+}
+
+// MkdirAll creates a directory and any missing parents.
+func (r *Root) MkdirAll(path string, perm fs.FileMode) error {
+	return pathErr(r.w.MkdirAll(name(path), perm), path)
+}
+
+// Chtimes sets a file's access and modification times.
+func (r *Root) Chtimes(path string, atime, mtime time.Time) error {
+	return pathErr(r.w.Chtimes(name(path), atime, mtime), path)
+}
+
+// name turns an OS path into a name relative to the file system root.
+func name(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	n := strings.TrimPrefix(filepath.ToSlash(path), "/")
+	if n == "" {
+		return "."
+	}
+	return n
+}
+
+// osPath turns a name relative to the file system root into an OS path.
+func osPath(name string) string {
+	return filepath.FromSlash("/" + name)
+}
+
+// pathErr puts the OS path back into errors about a file.
+func pathErr(err error, path string) error {
+	var pe *fs.PathError
+	if errors.As(err, &pe) {
+		pe.Path = path
+	}
+	return err
+}
+
+// linkErr is pathErr for an error about two paths.
+func linkErr(err error, oldpath, newpath string) error {
+	var le *os.LinkError
+	if errors.As(err, &le) {
+		le.Old, le.New = oldpath, newpath
+	}
+	return pathErr(err, oldpath)
+}
+
+// osWriter writes to the local file system.
+type osWriter struct{}
+
+func (osWriter) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return atomicfile.WriteFile(osPath(name), data, perm)
+}
+
+func (osWriter) AppendFile(name string, data []byte) error {
+	f, err := os.OpenFile(osPath(name), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (osWriter) Remove(name string) error {
+	return os.Remove(osPath(name))
+}
+
+func (osWriter) Rename(oldname, newname string) error {
+	return os.Rename(osPath(oldname), osPath(newname))
+}
+
+func (osWriter) MkdirAll(name string, perm fs.FileMode) error {
+	return os.MkdirAll(osPath(name), perm)
+}
+
+func (osWriter) Chtimes(name string, atime, mtime time.Time) error {
+	return os.Chtimes(osPath(name), atime, mtime)
+}
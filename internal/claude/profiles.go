@@ -0,0 +1,227 @@
+package claude
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/baz-sh/clsm/internal/atomicfile"
+	"github.com/baz-sh/clsm/internal/meta"
+)
+
+// profilesVersion is the current version of the profiles file format.
+const profilesVersion = 1
+
+// Profile is a named Claude Code data directory, or a glob such as
+// /home/*/.claude that names one for each directory it matches.
+type Profile struct {
+	Name string `json:"name,omitempty"`
+	Dir  string `json:"dir"` // kept as given, so ~ follows the user
+}
+
+type profilesFile struct {
+	Version  int       `json:"version"`
+	Profiles []Profile `json:"profiles"`
+}
+
+// ParseProfile parses name=dir, or a bare dir to be named after the
+// directory.
+func ParseProfile(s string) (Profile, error) {
+	p := Profile{Dir: s}
+	if name, dir, ok := strings.Cut(s, "="); ok && !strings.ContainsRune(name, filepath.Separator) {
+		p = Profile{Name: name, Dir: dir}
+	}
+	if p.Dir == "" {
+		return Profile{}, fmt.Errorf("profile %q has no directory", s)
+	}
+	if p.Name != "" && !validName(p.Name) {
+		return Profile{}, fmt.Errorf("profile name %q may only contain letters, digits, '.', '-' and '_'", p.Name)
+	}
+	if _, err := filepath.Match(p.Dir, ""); err != nil {
+		return Profile{}, fmt.Errorf("profile %q: %w", s, err)
+	}
+	return p, nil
+}
+
+// String returns the profile as ParseProfile reads it.
+func (p Profile) String() string {
+	if p.Name == "" {
+		return p.Dir
+	}
+	return p.Name + "=" + p.Dir
+}
+
+// IsGlob reports whether the profile's directory is a pattern.
+func (p Profile) IsGlob() bool {
+	return strings.ContainsAny(p.Dir, "*?[")
+}
+
+func validName(name string) bool {
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '.', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
+
+func profilesPath() (string, error) {
+	dir, err := meta.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profiles.json"), nil
+}
+
+// LoadProfiles reads the registered profiles. A missing file is no
+// profiles.
+func LoadProfiles() ([]Profile, error) {
+	path, err := profilesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var f profilesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if f.Version > profilesVersion {
+		return nil, fmt.Errorf("%s has version %d; this clsm only understands up to %d", path, f.Version, profilesVersion)
+	}
+	return f.Profiles, nil
+}
+
+// SaveProfiles replaces the registered profiles.
+func SaveProfiles(profiles []Profile) error {
+	path, err := profilesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	data, err := json.MarshalIndent(profilesFile{Version: profilesVersion, Profiles: profiles}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// Resolve returns a Root for each directory the profiles name, in order.
+// A glob gives one Root per matching directory, named after the parts the
+// wildcards matched, so /home/*/.claude gives alice, bob and so on, or
+// build:alice if the glob is named build. An unnamed directory is named
+// after its base name without a leading dot. Directories that don't exist
+// are skipped, and a directory named twice keeps its first name.
+func Resolve(profiles []Profile) ([]*Root, error) {
+	var roots []*Root
+	seen := make(map[string]bool)
+	names := make(map[string]string)
+	for _, p := range profiles {
+		for _, m := range p.matches() {
+			if seen[m.dir] {
+				continue
+			}
+			seen[m.dir] = true
+			if dir, ok := names[m.name]; ok {
+				return nil, fmt.Errorf("profiles %s and %s are both named %q", dir, m.dir, m.name)
+			}
+			names[m.name] = m.dir
+			r := New(m.dir)
+			r.Name = m.name
+			roots = append(roots, r)
+		}
+	}
+	return roots, nil
+}
+
+type match struct{ name, dir string }
+
+// matches returns the existing directories the profile names.
+func (p Profile) matches() []match {
+	pattern := filepath.Clean(ExpandHome(p.Dir))
+	if !p.IsGlob() {
+		if !isDir(pattern) {
+			return nil
+		}
+		name := p.Name
+		if name == "" {
+			name = strings.TrimPrefix(filepath.Base(pattern), ".")
+		}
+		return []match{{name: name, dir: pattern}}
+	}
+
+	dirs, _ := filepath.Glob(pattern)
+	sort.Strings(dirs)
+	elems := strings.Split(pattern, string(filepath.Separator))
+	var out []match
+	for _, dir := range dirs {
+		if !isDir(dir) {
+			continue
+		}
+		var parts []string
+		for i, e := range strings.Split(dir, string(filepath.Separator)) {
+			if i < len(elems) && strings.ContainsAny(elems[i], "*?[") {
+				parts = append(parts, strings.TrimPrefix(e, "."))
+			}
+		}
+		name := strings.Join(parts, "-")
+		if p.Name != "" {
+			name = p.Name + ":" + name
+		}
+		out = append(out, match{name: name, dir: dir})
+	}
+	return out
+}
+
+// Select returns the roots with the given names. A name also selects the
+// roots a glob profile of that name matched, so build selects build:alice
+// and build:bob.
+func Select(roots []*Root, names []string) ([]*Root, error) {
+	var out []*Root
+	for _, n := range names {
+		found := false
+		for _, r := range roots {
+			if r.Name == n || strings.HasPrefix(r.Name, n+":") {
+				found = true
+				if !containsRoot(out, r) {
+					out = append(out, r)
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no profile named %q", n)
+		}
+	}
+	return out, nil
+}
+
+func containsRoot(roots []*Root, r *Root) bool {
+	for _, x := range roots {
+		if x == r {
+			return true
+		}
+	}
+	return false
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,219 @@
+// Package claude locates Claude Code's data directories and reads and
+// writes the files in them. Every store goes through the active Roots, so
+// clsm can be pointed at another data directory, such as a backup, a
+// mounted volume or a fixture, with --claude-dir or CLAUDE_CONFIG_DIR, or
+// at several at once through named profiles.
+package claude
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Root is a Claude Code data directory and the file system it is on.
+//
+// Its methods take OS paths, like the os package, so paths can be kept in
+// sessions and handed to an editor. Underneath, reads go through an fs.FS
+// and writes through a Writer, both with names relative to the file
+// system root.
+type Root struct {
+	Name  string // the profile name, empty outside profiles
+	Dir   string // the data directory, normally ~/.claude
+	Plans string // where plans are kept, normally Dir/plans
+
+	fsys fs.FS
+	w    Writer
+}
+
+// Writer changes files on a Root's file system. Names are relative to the
+// file system root, as for fs.FS.
+type Writer interface {
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	AppendFile(name string, data []byte) error
+	Remove(name string) error
+	Rename(oldname, newname string) error
+	MkdirAll(name string, perm fs.FileMode) error
+	Chtimes(name string, atime, mtime time.Time) error
+}
+
+var (
+	mu    sync.Mutex
+	roots []*Root
+)
+
+// Current returns the Root the stores use for anything not tied to a
+// listed item, the first of the active Roots. Until Use or UseAll is
+// called it is the default data directory on the local file system.
+func Current() *Root {
+	return Roots()[0]
+}
+
+// Roots returns the active Roots, the ones the stores list from.
+func Roots() []*Root {
+	mu.Lock()
+	defer mu.Unlock()
+	if len(roots) == 0 {
+		roots = []*Root{New(DefaultDir())}
+	}
+	return append([]*Root(nil), roots...)
+}
+
+// Lookup returns the active Root with the given profile name, or Current
+// for an empty name, as items listed outside profiles have. It returns nil
+// if no active Root has the name.
+func Lookup(name string) *Root {
+	rs := Roots()
+	if name == "" {
+		return rs[0]
+	}
+	for _, r := range rs {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// For returns the active Root with the given profile name, as Lookup
+// does, or Current if no active Root has it, for an item listed before the
+// profiles in use changed.
+func For(profile string) *Root {
+	if r := Lookup(profile); r != nil {
+		return r
+	}
+	return Current()
+}
+
+// RootOf returns the active Root whose data or plans directory holds
+// path, for files known only by their path, or Current if none does.
+func RootOf(path string) *Root {
+	rs := Roots()
+	best, n := rs[0], -1
+	for _, r := range rs {
+		for _, dir := range []string{r.Dir, r.Plans} {
+			if len(dir) > n && (path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))) {
+				best, n = r, len(dir)
+			}
+		}
+	}
+	return best
+}
+
+// Use makes r the only Root the stores use.
+func Use(r *Root) {
+	UseAll([]*Root{r})
+}
+
+// UseAll makes rs the Roots the stores use. The first is Current.
+func UseAll(rs []*Root) {
+	mu.Lock()
+	defer mu.Unlock()
+	roots = append([]*Root(nil), rs...)
+}
+
+// DefaultDir returns Claude Code's data directory: CLAUDE_CONFIG_DIR if
+// set, as Claude Code itself honors it, else ~/.claude.
+func DefaultDir() string {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return ExpandHome(dir)
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".claude")
+}
+
+// New returns a Root for dir on the local file system.
+func New(dir string) *Root {
+	return NewFS(dir, os.DirFS("/"), osWriter{})
+}
+
+// NewFS returns a Root for dir on another file system. fsys and w see the
+// file system root as ".", so dir /data/.claude is data/.claude to them.
+func NewFS(dir string, fsys fs.FS, w Writer) *Root {
+	r := &Root{Dir: filepath.Clean(dir), fsys: fsys, w: w}
+	r.Plans = r.plansDir()
+	return r
+}
+
+// ProjectsDir returns the directory Claude Code keeps a directory per
+// project in, holding sessions, subagent transcripts and memories.
+func (r *Root) ProjectsDir() string {
+	return filepath.Join(r.Dir, "projects")
+}
+
+// ProjectDir is a project directory in one of the active Roots.
+type ProjectDir struct {
+	Root *Root
+	Name string // the encoded directory name
+}
+
+// ProjectDirs returns the project directories of every active Root. With
+// several Roots, one that has no projects directory yet is skipped.
+func ProjectDirs() ([]ProjectDir, error) {
+	rs := Roots()
+	var dirs []ProjectDir
+	for _, r := range rs {
+		entries, err := r.ReadDir(r.ProjectsDir())
+		if err != nil {
+			if len(rs) > 1 && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("reading projects dir: %w", err)
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				dirs = append(dirs, ProjectDir{Root: r, Name: e.Name()})
+			}
+		}
+	}
+	return dirs, nil
+}
+
+// plansDir returns the plans directory, honoring a plansDirectory setting
+// in the user's settings or, as Claude Code does, the settings of the
+// project in the current directory. A relative setting is relative to
+// that project.
+func (r *Root) plansDir() string {
+	dir := filepath.Join(r.Dir, "plans")
+	cwd, _ := os.Getwd()
+	files := []string{filepath.Join(r.Dir, "settings.json")}
+	if cwd != "" {
+		files = append(files,
+			filepath.Join(cwd, ".claude", "settings.json"),
+			filepath.Join(cwd, ".claude", "settings.local.json"))
+	}
+	for _, f := range files {
+		data, err := r.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		var settings struct {
+			PlansDirectory string `json:"plansDirectory"`
+		}
+		if json.Unmarshal(data, &settings) != nil || settings.PlansDirectory == "" {
+			continue
+		}
+		p := ExpandHome(settings.PlansDirectory)
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(cwd, p)
+		}
+		dir = filepath.Clean(p)
+	}
+	return dir
+}
+
+// ExpandHome replaces a leading ~ in p with the home directory.
+func ExpandHome(p string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return home + p[1:]
+		}
+	}
+	return p
+}
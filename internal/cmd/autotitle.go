@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+var (
+	autotitleProject string
+	autotitleRetitle bool
+	autotitleDryRun  bool
+	autotitleYes     bool
+	autotitleJSON    bool
+	autotitleForce   bool
+)
+
+var autotitleCmd = &cobra.Command{
+	Use:   "autotitle [session-id|prefix...]",
+	Short: "Give untitled sessions a title derived from their transcript",
+	Long: `Derive titles for sessions without a custom title and set them, the
+same way rename does.
+
+A title comes from the last summary Claude Code wrote for the session, or
+else from the first prompt a person typed, skipping slash command output
+and caveats. The names of the files the session edited most are added.
+
+Name sessions by ID or prefix, or use --project to title every untitled
+session in matching projects. The suggestions are shown as a numbered
+table first: answer with the ones to apply (all, none, 1,3-5), or e2 to
+edit suggestion 2 before applying. Sessions Claude Code is writing right
+now are skipped unless --force is given.`,
+	Example: `  clsm autotitle 3f2a
+  clsm autotitle -p myapp
+  clsm autotitle -p myapp --dry-run
+  clsm autotitle -p myapp --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !cmd.Flags().Changed("project") {
+			return errors.New("provide session IDs or --project")
+		}
+		sessions, err := autotitleTargets(args)
+		if err != nil {
+			return err
+		}
+		return runAutotitle(sessions)
+	},
+}
+
+func init() {
+	flags := autotitleCmd.Flags()
+	flags.StringVarP(&autotitleProject, "project", "p", "", "title the sessions in projects whose path contains this term")
+	flags.BoolVar(&autotitleRetitle, "retitle", false, "also suggest titles for sessions that already have a custom title")
+	flags.BoolVarP(&autotitleDryRun, "dry-run", "n", false, "show the suggestions without applying them")
+	flags.BoolVarP(&autotitleYes, "yes", "y", false, "apply every suggestion without asking")
+	flags.BoolVar(&autotitleJSON, "json", false, "print the suggestions as JSON without applying them")
+	flags.BoolVar(&autotitleForce, "force", false, "also rename sessions Claude Code is writing right now")
+}
+
+// autotitleTargets returns the named sessions, or the sessions of the
+// --project projects, leaving out titled ones unless --retitle is set.
+func autotitleTargets(ids []string) ([]session.Session, error) {
+	var sessions []session.Session
+	if len(ids) > 0 {
+		for _, id := range ids {
+			s, err := session.Find(id, autotitleProject)
+			if err != nil {
+				return nil, err
+			}
+			sessions = append(sessions, s)
+		}
+		sessions = dedupe(sessions, session.Session.Key)
+	} else {
+		all, err := session.ListAllSessions()
+		if err != nil {
+			return nil, err
+		}
+		sessions = filterItems(all, func(s session.Session) bool {
+			return matchesProject(s.Project, s.ProjectPath, autotitleProject)
+		})
+	}
+	if !autotitleRetitle {
+		sessions = filterItems(sessions, func(s session.Session) bool { return s.CustomTitle == "" })
+	}
+	return sessions, nil
+}
+
+// titleSuggestion is a derived title for one session.
+type titleSuggestion struct {
+	ID      string `json:"id"`
+	Current string `json:"current"`
+	Title   string `json:"title"`
+	Source  string `json:"source"` // see session.TitleFrom*
+	session session.Session
+}
+
+func runAutotitle(sessions []session.Session) error {
+	var suggestions []titleSuggestion
+	for _, s := range sessions {
+		title, source := session.SuggestTitle(s)
+		if title == "" || title == s.CustomTitle {
+			continue
+		}
+		suggestions = append(suggestions, titleSuggestion{
+			ID: s.SessionID, Current: s.Title(), Title: title, Source: source, session: s,
+		})
+	}
+
+	if autotitleJSON {
+		if suggestions == nil {
+			suggestions = []titleSuggestion{}
+		}
+		return writeJSON(suggestions)
+	}
+	if len(suggestions) == 0 {
+		fmt.Println("No sessions to title.")
+		return nil
+	}
+	return applySuggestions(suggestions, autotitleDryRun, autotitleYes, autotitleForce)
+}
+
+// applySuggestions shows the numbered preview table, asks which titles to
+// apply (unless dryRun or yes) and renames those sessions. Sessions Claude
+// Code is writing by then are skipped unless force is set.
+func applySuggestions(suggestions []titleSuggestion, dryRun, yes, force bool) error {
+	printSuggestions(os.Stdout, suggestions)
+	var chosen []int
+	switch {
+	case dryRun:
+		fmt.Printf("\nDry run: %d title(s) would be set.\n", len(suggestions))
+		return nil
+	case yes:
+		chosen = allIndices(len(suggestions))
+	default:
+		chosen = promptTitles(os.Stdout, suggestions)
+	}
+	if len(chosen) == 0 {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	var failed int
+	for _, i := range chosen {
+		sg := suggestions[i]
+		if !force {
+			if err := session.CheckLive(sg.session); err != nil {
+				fmt.Printf("  Skipped: %v; use --force to rename it anyway\n", err)
+				failed++
+				continue
+			}
+		}
+		if err := session.Rename(sg.session, sg.Title); err != nil {
+			fmt.Printf("  Failed:  %s — %v\n", sg.ID, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  Renamed: %s → %s\n", sg.ID, sg.Title)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d session(s) failed to rename", failed)
+	}
+	return nil
+}
+
+// printSuggestions writes the numbered preview table.
+func printSuggestions(out io.Writer, suggestions []titleSuggestion) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tSESSION\tCURRENT\tNEW TITLE\tFROM")
+	for i, sg := range suggestions {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", i+1, shortID(sg.ID), truncateRunes(sg.Current, 40), sg.Title, sg.Source)
+	}
+	w.Flush()
+}
+
+// promptTitles asks which suggestions to apply, letting the user edit any
+// of them first with e<N>. It returns the zero-based indices to apply.
+func promptTitles(out io.Writer, suggestions []titleSuggestion) []int {
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprint(out, "\nApply which titles? [all/none/1,3-5, e<N> to edit] (default none): ")
+		answer, err := reader.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+		if err != nil && answer == "" {
+			fmt.Fprintln(out)
+			return nil // EOF: treat as "none"
+		}
+
+		if rest, ok := strings.CutPrefix(strings.ToLower(answer), "e"); ok && rest != "" {
+			n, perr := strconv.Atoi(strings.TrimSpace(rest))
+			if perr != nil || n < 1 || n > len(suggestions) {
+				fmt.Fprintf(out, "no suggestion %q\n", rest)
+				continue
+			}
+			fmt.Fprintf(out, "Title for %d [%s]: ", n, suggestions[n-1].Title)
+			title, _ := reader.ReadString('\n')
+			if title = strings.TrimSpace(title); title != "" {
+				suggestions[n-1].Title = title
+				suggestions[n-1].Source = "edited"
+			}
+			fmt.Fprintln(out)
+			printSuggestions(out, suggestions)
+			continue
+		}
+
+		chosen, perr := parseSelection(strings.ToLower(answer), len(suggestions))
+		if perr == nil {
+			return chosen
+		}
+		fmt.Fprintln(out, perr)
+		if err != nil {
+			return nil
+		}
+	}
+}
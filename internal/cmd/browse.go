@@ -4,19 +4,63 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"github.com/spf13/cobra"
 
+	"github.com/baz-sh/clsm/internal/session"
 	"github.com/baz-sh/clsm/internal/tui/browse"
 )
 
+var (
+	browseSort        string
+	browseProjectSort string
+	browseReverse     bool
+)
+
 var browseCmd = &cobra.Command{
 	Use:   "browse",
 	Short: "Browse Claude Code projects and sessions",
 	Long: `Browse all Claude Code projects and their sessions interactively.
 
 Navigate the project list, drill into a project to see its sessions,
-and filter results with /.`,
+and filter results with /. Press s to cycle the sort order and S to
+reverse it.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		p := tea.NewProgram(browse.New(browse.ModeProjects))
-		_, err := p.Run()
+		order, err := browseSortState()
+		if err != nil {
+			return err
+		}
+		p := tea.NewProgram(browse.New(browse.ModeProjects).WithSort(order))
+		_, err = p.Run()
 		return err
 	},
 }
+
+func init() {
+	addSortFlags(browseCmd)
+}
+
+// addSortFlags registers the TUI sort flags on cmd. They are local flags so
+// they don't clash with ls's column --sort.
+func addSortFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&browseSort, "sort", string(browse.DefaultSort.Sessions),
+		"session sort: "+session.JoinKeys(session.SortKeys))
+	cmd.Flags().StringVar(&browseProjectSort, "project-sort", string(browse.DefaultSort.Projects),
+		"project sort: "+session.JoinKeys(session.ProjectSortKeys))
+	cmd.Flags().BoolVar(&browseReverse, "reverse", false, "reverse the sort order")
+}
+
+// browseSortState builds the initial TUI sort order from the flags.
+func browseSortState() (browse.SortState, error) {
+	sessKey, err := session.ParseSortKey(browseSort)
+	if err != nil {
+		return browse.SortState{}, err
+	}
+	projKey, err := session.ParseProjectSortKey(browseProjectSort)
+	if err != nil {
+		return browse.SortState{}, err
+	}
+	return browse.SortState{
+		Sessions:        sessKey,
+		SessionsReverse: browseReverse,
+		Projects:        projKey,
+		ProjectsReverse: browseReverse,
+	}, nil
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+var (
+	copyTo      string
+	copyProject string
+	moveForce   bool
+)
+
+var copyCmd = &cobra.Command{
+	Use:   "copy <session-id|prefix>... --to <profile>",
+	Short: "Copy sessions to another profile",
+	Long: `Copy sessions into the same project in another profile's data
+directory, so Claude Code run with that directory can resume them.
+
+The transcript, subagent transcripts, index entry, and the todos, file
+history and the like Claude Code keeps for the session are copied with
+the session ID and modification times kept. A copy already in the profile is
+brought up to date if it is behind, and left alone if it is the same or
+further on. --to may also be a data directory that isn't registered.`,
+	Example: `  clsm copy 3f2a --to personal
+  clsm --profile work copy 3f2a 9c1d --to ~/.claude-backup`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCopy(args, session.Copy, "Copied")
+	},
+}
+
+var moveCmd = &cobra.Command{
+	Use:   "move <session-id|prefix>... --to <profile>",
+	Short: "Move sessions to another profile",
+	Long: `Move sessions into the same project in another profile's data
+directory. Each session is copied as clsm copy does and then deleted from
+the profile it was in; if the copy fails, the original is left alone.
+Sessions Claude Code is writing right now are skipped unless --force is
+given, since the rest of the conversation would be written to a
+transcript that is no longer there.`,
+	Example: `  clsm move 3f2a --to personal`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		move := func(s session.Session, dst *claude.Root) (session.Session, string, error) {
+			if !moveForce {
+				if err := session.CheckLive(s); err != nil {
+					return session.Session{}, "", fmt.Errorf("%w; use --force to move it anyway", err)
+				}
+			}
+			return session.Move(s, dst)
+		}
+		return runCopy(args, move, "Moved")
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{copyCmd, moveCmd} {
+		c.Flags().StringVar(&copyTo, "to", "", "profile name or data directory to put the sessions in")
+		c.Flags().StringVarP(&copyProject, "project", "p", "", "only consider sessions in projects whose path contains this term")
+		c.MarkFlagRequired("to")
+	}
+	moveCmd.Flags().BoolVar(&moveForce, "force", false, "also move sessions Claude Code is writing right now")
+}
+
+// runCopy finds every session first, so a bad ID stops the run before
+// anything is copied, then copies or moves them with fn.
+func runCopy(ids []string, fn func(session.Session, *claude.Root) (session.Session, string, error), verb string) error {
+	dst, err := targetRoot(copyTo)
+	if err != nil {
+		return err
+	}
+	// Sessions already in the destination aren't the ones meant.
+	var sources []*claude.Root
+	for _, r := range claude.Roots() {
+		if r.Dir != dst.Dir {
+			sources = append(sources, r)
+		}
+	}
+	if len(sources) == 0 {
+		return fmt.Errorf("%s is the only data directory in use, so there is nothing to copy from", copyTo)
+	}
+
+	var sessions []session.Session
+	for _, id := range ids {
+		s, err := session.FindIn(sources, id, copyProject)
+		if err != nil {
+			return err
+		}
+		sessions = append(sessions, s)
+	}
+
+	var failed int
+	for _, s := range sessions {
+		_, status, err := fn(s, dst)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", shortID(s.SessionID), err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s %s (%s) to %s", verb, shortID(s.SessionID), s.Title(), copyTo)
+		switch status {
+		case session.CopyUpdated:
+			fmt.Print(", bringing its older copy there up to date")
+		case session.CopyExists:
+			fmt.Print(", which already had it")
+		}
+		fmt.Println()
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d session(s) failed", failed, len(sessions))
+	}
+	return nil
+}
+
+// targetRoot returns the registered profile with the given name, whether
+// or not --profile selected it, or else the data directory at that path.
+func targetRoot(to string) (*claude.Root, error) {
+	registered, err := claude.LoadProfiles()
+	if err != nil {
+		return nil, err
+	}
+	roots, err := claude.Resolve(registered)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range roots {
+		if r.Name == to {
+			return r, nil
+		}
+	}
+	dir := claude.ExpandHome(to)
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return claude.New(dir), nil
+	}
+	return nil, fmt.Errorf("%s is neither a profile nor a directory", to)
+}
@@ -2,76 +2,545 @@ package cmd
 
 import (
 	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/baz-sh/clsm/internal/memory"
+	"github.com/baz-sh/clsm/internal/plan"
 	"github.com/baz-sh/clsm/internal/session"
 )
 
+// deleteOptions holds the flags shared by the delete commands.
+type deleteOptions struct {
+	ids    []string
+	dryRun bool
+	yes    bool
+	json   bool
+	force  bool
+}
+
+var deleteOpts deleteOptions
+
 var deleteCmd = &cobra.Command{
-	Use:   "delete <search-term>",
+	Use:   "delete [search-term]",
 	Short: "Delete Claude Code sessions",
-	Long: `Delete Claude Code sessions matching a search term.
+	Long: `Delete Claude Code sessions matching a search term or session ID.
+
+Finds matches, shows them as a numbered list, and asks which ones to
+delete: "all", "none", or a selection such as 1,3-5. Use --yes to delete
+every match without asking, or --dry-run to only show what would go.
+
+Along with the transcript go its subagent transcripts and what Claude
+Code keeps for the session elsewhere: todo lists, file history,
+session-env directories, debug logs, tool results and shell snapshots.
+"clsm sweep" removes these for sessions deleted some other way.
+
+Tagged and pinned sessions are skipped when matching a search term; name
+them with --id to delete them anyway. Sessions Claude Code is writing
+right now are always skipped unless --force is given.
+
+Use "delete memory" and "delete plan" to remove memories and plans.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && len(deleteOpts.ids) == 0 {
+			return errors.New("provide a search term or at least one --id")
+		}
+		return runDeleteSessions(strings.Join(args, " "), deleteOpts.ids)
+	},
+}
+
+var deleteMemoryCmd = &cobra.Command{
+	Use:   "memory [search-term]",
+	Short: "Delete Claude Code memory files",
+	Long: `Delete memory files matching a search term or file name.
 
-Finds matches, shows them, and prompts for confirmation before deleting.`,
-	Args: cobra.MinimumNArgs(1),
+The term matches name, description, type and file name, like / in the
+TUI. --id takes a memory file name (with or without .md) or full path;
+a name found in more than one project must be given as a full path.
+Deleted memories are also removed from the project's MEMORY.md index.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runCLI(strings.Join(args, " "))
+		if len(args) == 0 && len(deleteOpts.ids) == 0 {
+			return errors.New("provide a search term or at least one --id")
+		}
+		return runDeleteMemories(strings.Join(args, " "), deleteOpts.ids)
 	},
 }
 
-func runCLI(term string) error {
-	sessions, err := session.Search(term)
+var deletePlanCmd = &cobra.Command{
+	Use:   "plan [search-term]",
+	Short: "Delete Claude Code plan files",
+	Long: `Delete plan files matching a search term or file name.
+
+The term matches title, context, project hint and file name, like / in
+the TUI. --id takes a plan file name (with or without .md) or full path.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && len(deleteOpts.ids) == 0 {
+			return errors.New("provide a search term or at least one --id")
+		}
+		return runDeletePlans(strings.Join(args, " "), deleteOpts.ids)
+	},
+}
+
+func init() {
+	flags := deleteCmd.PersistentFlags()
+	flags.StringArrayVar(&deleteOpts.ids, "id", nil, "delete this session ID (or unique prefix) or file name (repeatable)")
+	flags.BoolVarP(&deleteOpts.dryRun, "dry-run", "n", false, "show what would be deleted without deleting")
+	flags.BoolVarP(&deleteOpts.yes, "yes", "y", false, "delete all matches without asking")
+	flags.BoolVar(&deleteOpts.json, "json", false, "print results as JSON")
+	deleteCmd.Flags().BoolVar(&deleteOpts.force, "force", false, "also delete sessions Claude Code is writing right now")
+
+	deleteCmd.AddCommand(deleteMemoryCmd)
+	deleteCmd.AddCommand(deletePlanCmd)
+}
+
+// deleteItem is the kind-independent description of something to delete.
+type deleteItem struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Path    string   `json:"path"`
+	details []string // extra lines shown in the numbered list
+	key     string   // what remove keys its errors by; ID if empty
+}
+
+// deleteOutcome is the JSON record printed for each item with --json.
+type deleteOutcome struct {
+	deleteItem
+	Status string `json:"status"` // "deleted", "failed", "skipped" or "would-delete"
+	Error  string `json:"error,omitempty"`
+}
+
+func runDeleteSessions(term string, ids []string) error {
+	var sessions []session.Session
+	if term != "" {
+		found, err := session.Search(term)
+		if err != nil {
+			return fmt.Errorf("search failed: %w", err)
+		}
+		// Tagged and pinned sessions are never deleted by a search term;
+		// naming them with --id still works.
+		deletable, protected, err := session.SplitProtected(found)
+		if err != nil {
+			return err
+		}
+		if len(protected) > 0 {
+			out := io.Writer(os.Stdout)
+			if deleteOpts.json {
+				out = os.Stderr
+			}
+			fmt.Fprintf(out, "Skipping %d tagged or pinned session(s); use --id to delete them:\n", len(protected))
+			for _, s := range protected {
+				fmt.Fprintf(out, "  %s  %s\n", s.SessionID, s.Title())
+			}
+			fmt.Fprintln(out)
+		}
+		sessions = deletable
+	}
+	for _, id := range ids {
+		s, err := session.Find(id, "")
+		if err != nil {
+			return err
+		}
+		sessions = append(sessions, s)
+	}
+	sessions = dedupe(sessions, session.Session.Key)
+	if !deleteOpts.force {
+		out := io.Writer(os.Stdout)
+		if deleteOpts.json {
+			out = os.Stderr
+		}
+		sessions = skipLive(out, sessions, "delete")
+	}
+
+	describe := func(s session.Session) deleteItem {
+		details := []string{"Project: " + s.ProjectPath}
+		if s.MatchValue != "" {
+			details = append(details, fmt.Sprintf("Match:   %s (%s)", s.MatchValue, s.MatchSource))
+		}
+		details = append(details, fmt.Sprintf("Created: %s  Messages: %d", s.Created, s.MsgCount))
+		if s.Protected() {
+			details = append(details, "Tags:    "+formatTags(s))
+		}
+		if n := len(s.Subagents); n > 0 {
+			details = append(details, fmt.Sprintf("Agents:  %d subagent transcript(s), deleted with the session", n))
+		}
+		if kinds, size := session.DescribeArtifacts(session.Artifacts(s)); kinds != "" {
+			details = append(details, fmt.Sprintf("Also:    %s, %s", kinds, formatBytes(size)))
+		}
+		if s.Live != "" {
+			details = append(details, "Live:    "+s.Live)
+		}
+		return deleteItem{ID: s.SessionID, Title: s.Title(), Path: s.FullPath, details: details, key: s.Key()}
+	}
+	remove := func(selected []session.Session) map[string]string {
+		errs := make(map[string]string)
+		// Look again: a session may have been resumed while we asked.
+		if !deleteOpts.force {
+			var live []session.Session
+			selected, live = session.SplitLive(selected)
+			for _, s := range live {
+				errs[s.Key()] = "in use by Claude Code (" + s.Live + "); use --force to delete it anyway"
+			}
+		}
+		for _, r := range session.Delete(selected) {
+			if !r.Success {
+				errs[r.Key()] = r.Error
+			}
+		}
+		return errs
+	}
+	return confirmAndDelete("session", term, sessions, describe, remove)
+}
+
+// skipLive leaves out the sessions Claude Code is writing, listing them on
+// out.
+func skipLive(out io.Writer, sessions []session.Session, verb string) []session.Session {
+	idle, live := session.SplitLive(sessions)
+	if len(live) > 0 {
+		fmt.Fprintf(out, "Skipping %d session(s) Claude Code is writing; use --force to %s them anyway:\n", len(live), verb)
+		for _, s := range live {
+			fmt.Fprintf(out, "  %s  %s (%s)\n", s.SessionID, s.Title(), s.Live)
+		}
+		fmt.Fprintln(out)
+	}
+	return idle
+}
+
+func runDeleteMemories(term string, ids []string) error {
+	projects, err := memory.ListProjects()
 	if err != nil {
-		return fmt.Errorf("search failed: %w", err)
+		return fmt.Errorf("listing memory projects: %w", err)
 	}
 
-	if len(sessions) == 0 {
-		fmt.Println("No sessions found matching:", term)
-		return nil
+	var memories []memory.Memory
+	for _, p := range projects {
+		mems, err := memory.ListMemories(p.Profile, p.DirName)
+		if err != nil {
+			continue
+		}
+		for _, m := range mems {
+			if m.Type == "index" {
+				continue // MEMORY.md is maintained by Delete, never removed itself
+			}
+			if (term != "" && m.Matches(term)) || matchesFileID(m.FileName, m.FullPath, ids) {
+				memories = append(memories, m)
+			}
+		}
+	}
+	if err := checkFileIDs(ids, memories, func(m memory.Memory) (string, string) { return m.FileName, m.FullPath }); err != nil {
+		return err
 	}
 
-	fmt.Printf("Found %d session(s) matching %q:\n\n", len(sessions), term)
-	for i, s := range sessions {
-		title := s.CustomTitle
-		if title == "" {
-			title = s.Summary
+	describe := func(m memory.Memory) deleteItem {
+		details := []string{"Project: " + m.ProjectPath}
+		if m.Description != "" {
+			details = append(details, "About:   "+m.Description)
 		}
-		if title == "" {
-			title = s.FirstPrompt
+		return deleteItem{ID: m.FileName, Title: m.Name, Path: m.FullPath, details: details, key: m.FullPath}
+	}
+	remove := func(selected []memory.Memory) map[string]string {
+		errs := make(map[string]string)
+		for _, r := range memory.Delete(selected) {
+			if !r.Success {
+				errs[r.FullPath] = r.Error
+			}
 		}
-		fmt.Printf("  %d. %s\n", i+1, title)
-		fmt.Printf("     Project: %s\n", s.ProjectPath)
-		fmt.Printf("     Match:   %s (%s)\n", s.MatchValue, s.MatchSource)
-		fmt.Printf("     Created: %s  Messages: %d\n\n", s.Created, s.MsgCount)
+		return errs
 	}
+	return confirmAndDelete("memory", term, memories, describe, remove)
+}
 
-	fmt.Print("Delete these sessions? [y/N] ")
-	reader := bufio.NewReader(os.Stdin)
-	answer, _ := reader.ReadString('\n')
-	answer = strings.TrimSpace(strings.ToLower(answer))
+func runDeletePlans(term string, ids []string) error {
+	all, err := plan.ListPlans()
+	if err != nil {
+		return err
+	}
+
+	var plans []plan.Plan
+	for _, p := range all {
+		if (term != "" && p.Matches(term)) || matchesFileID(p.FileName, p.FullPath, ids) {
+			plans = append(plans, p)
+		}
+	}
+	if err := checkFileIDs(ids, plans, func(p plan.Plan) (string, string) { return p.FileName, p.FullPath }); err != nil {
+		return err
+	}
+
+	describe := func(p plan.Plan) deleteItem {
+		var details []string
+		if p.ProjectHint != "" {
+			details = append(details, "Project: "+p.ProjectHint)
+		}
+		details = append(details, "File:    "+p.FileName)
+		return deleteItem{ID: p.FileName, Title: p.Title, Path: p.FullPath, details: details, key: p.FullPath}
+	}
+	remove := func(selected []plan.Plan) map[string]string {
+		errs := make(map[string]string)
+		for _, r := range plan.Delete(selected) {
+			if !r.Success {
+				errs[r.FullPath] = r.Error
+			}
+		}
+		return errs
+	}
+	return confirmAndDelete("plan", term, plans, describe, remove)
+}
 
-	if answer != "y" && answer != "yes" {
-		fmt.Println("Aborted.")
+// confirmAndDelete lists the candidates, asks which to delete (unless
+// --yes or --dry-run), deletes them and reports the outcome. remove returns
+// error messages for the items that failed, keyed as deleteItem.key says.
+func confirmAndDelete[T any](kind, term string, items []T, describe func(T) deleteItem, remove func([]T) map[string]string) error {
+	// With --json, stdout carries only the JSON result; everything meant
+	// for a human goes to stderr.
+	var out io.Writer = os.Stdout
+	if deleteOpts.json {
+		out = os.Stderr
+	}
+
+	if len(items) == 0 {
+		if deleteOpts.json {
+			return writeJSON([]deleteOutcome{})
+		}
+		if term != "" {
+			fmt.Fprintf(out, "No %ss found matching: %s\n", kind, term)
+		} else {
+			fmt.Fprintf(out, "No %ss found.\n", kind)
+		}
+		return nil
+	}
+
+	described := make([]deleteItem, len(items))
+	for i, item := range items {
+		described[i] = describe(item)
+	}
+
+	if term != "" {
+		fmt.Fprintf(out, "Found %d %s(s) matching %q:\n\n", len(items), kind, term)
+	} else {
+		fmt.Fprintf(out, "Found %d %s(s):\n\n", len(items), kind)
+	}
+	for i, d := range described {
+		fmt.Fprintf(out, "  %d. %s\n", i+1, d.Title)
+		for _, line := range d.details {
+			fmt.Fprintf(out, "     %s\n", line)
+		}
+		fmt.Fprintln(out)
+	}
+
+	var chosen []int
+	switch {
+	case deleteOpts.dryRun:
+		outcomes := make([]deleteOutcome, len(described))
+		for i, d := range described {
+			outcomes[i] = deleteOutcome{deleteItem: d, Status: "would-delete"}
+		}
+		if deleteOpts.json {
+			return writeJSON(outcomes)
+		}
+		fmt.Fprintf(out, "Dry run: %d %s(s) would be deleted.\n", len(items), kind)
+		return nil
+	case deleteOpts.yes:
+		chosen = allIndices(len(items))
+	default:
+		var err error
+		chosen, err = promptSelection(out, "Delete", kind, len(items))
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(chosen) == 0 {
+		if deleteOpts.json {
+			outcomes := make([]deleteOutcome, len(described))
+			for i, d := range described {
+				outcomes[i] = deleteOutcome{deleteItem: d, Status: "skipped"}
+			}
+			return writeJSON(outcomes)
+		}
+		fmt.Fprintln(out, "Aborted.")
 		return nil
 	}
 
-	results := session.Delete(sessions)
+	selected := make([]T, len(chosen))
+	isChosen := make(map[int]bool, len(chosen))
+	for i, idx := range chosen {
+		selected[i] = items[idx]
+		isChosen[idx] = true
+	}
+	errs := remove(selected)
+
+	outcomes := make([]deleteOutcome, 0, len(described))
 	var failed int
-	for _, r := range results {
-		if r.Success {
-			fmt.Printf("  Deleted: %s\n", r.SessionID)
-		} else {
-			fmt.Printf("  Failed:  %s — %s\n", r.SessionID, r.Error)
-			failed++
+	for i, d := range described {
+		o := deleteOutcome{deleteItem: d, Status: "skipped"}
+		if isChosen[i] {
+			key := d.key
+			if key == "" {
+				key = d.ID
+			}
+			if msg, ok := errs[key]; ok {
+				o.Status = "failed"
+				o.Error = msg
+				failed++
+			} else {
+				o.Status = "deleted"
+			}
+		}
+		outcomes = append(outcomes, o)
+	}
+
+	if deleteOpts.json {
+		if err := writeJSON(outcomes); err != nil {
+			return err
+		}
+	} else {
+		for _, o := range outcomes {
+			switch o.Status {
+			case "deleted":
+				fmt.Printf("  Deleted: %s\n", o.ID)
+			case "failed":
+				fmt.Printf("  Failed:  %s — %s\n", o.ID, o.Error)
+			}
 		}
 	}
 
 	if failed > 0 {
-		return fmt.Errorf("%d session(s) failed to delete", failed)
+		return fmt.Errorf("%d %s(s) failed to delete", failed, kind)
+	}
+	return nil
+}
+
+// promptSelection asks which of n numbered items to act on and returns
+// their zero-based indices. An empty answer selects nothing.
+func promptSelection(out io.Writer, verb, kind string, n int) ([]int, error) {
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprintf(out, "%s which %ss? [all/none/1,3-5] (default none): ", verb, kind)
+		answer, err := reader.ReadString('\n')
+		answer = strings.TrimSpace(strings.ToLower(answer))
+		if err != nil && answer == "" {
+			fmt.Fprintln(out)
+			return nil, nil // EOF: treat as "none"
+		}
+
+		chosen, perr := parseSelection(answer, n)
+		if perr == nil {
+			return chosen, nil
+		}
+		fmt.Fprintln(out, perr)
+		if err != nil {
+			return nil, nil
+		}
+	}
+}
+
+// parseSelection parses answers such as "all", "none", "3" or "1,3-5" into
+// sorted zero-based indices for a list of n items.
+func parseSelection(answer string, n int) ([]int, error) {
+	switch answer {
+	case "", "n", "no", "none":
+		return nil, nil
+	case "a", "all", "y", "yes":
+		return allIndices(n), nil
+	}
+
+	picked := make(map[int]bool)
+	for _, part := range strings.Split(answer, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection %q", part)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil {
+				return nil, fmt.Errorf("invalid selection %q", part)
+			}
+		}
+		if first < 1 || last > n || first > last {
+			return nil, fmt.Errorf("selection %q is out of range 1-%d", part, n)
+		}
+		for i := first; i <= last; i++ {
+			picked[i-1] = true
+		}
+	}
+
+	var chosen []int
+	for i := 0; i < n; i++ {
+		if picked[i] {
+			chosen = append(chosen, i)
+		}
+	}
+	return chosen, nil
+}
+
+// matchesFileID reports whether a memory or plan file is named by one of
+// the --id values: its file name with or without .md, or its full path.
+func matchesFileID(fileName, fullPath string, ids []string) bool {
+	for _, id := range ids {
+		if id == fileName || id == fullPath || id+".md" == fileName {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFileIDs returns an error naming the first --id that matched none of
+// the found items, or a file name that matched files in more than one
+// place, listing their paths so one can be chosen.
+func checkFileIDs[T any](ids []string, items []T, file func(T) (string, string)) error {
+	for _, id := range ids {
+		var paths []string
+		for _, item := range items {
+			name, path := file(item)
+			if matchesFileID(name, path, []string{id}) {
+				paths = append(paths, path)
+			}
+		}
+		switch {
+		case len(paths) == 0:
+			return fmt.Errorf("no file named %s", id)
+		case len(paths) > 1:
+			return fmt.Errorf("%s names %d files; give the full path of one:\n  %s", id, len(paths), strings.Join(paths, "\n  "))
+		}
 	}
 	return nil
 }
+
+func dedupe[T any](items []T, key func(T) string) []T {
+	seen := make(map[string]bool, len(items))
+	out := items[:0]
+	for _, item := range items {
+		k := key(item)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		out = append(out, item)
+	}
+	return out
+}
+
+func allIndices(n int) []int {
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	return idx
+}
+
+func writeJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		answer  string
+		want    []int
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"none", nil, false},
+		{"n", nil, false},
+		{"all", []int{0, 1, 2, 3, 4}, false},
+		{"y", []int{0, 1, 2, 3, 4}, false},
+		{"3", []int{2}, false},
+		{"1,3-5", []int{0, 2, 3, 4}, false},
+		{"5, 1 , 2-3", []int{0, 1, 2, 4}, false},
+		{"2-3,3-4", []int{1, 2, 3}, false},
+		{"1,,2,", []int{0, 1}, false},
+		{"4 - 5", []int{3, 4}, false},
+		{"0", nil, true},
+		{"6", nil, true},
+		{"4-6", nil, true},
+		{"3-2", nil, true},
+		{"-2", nil, true},
+		{"x", nil, true},
+		{"1-x", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.answer, func(t *testing.T) {
+			got, err := parseSelection(tt.answer, 5)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSelection(%q): error %v, want error %v", tt.answer, err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseSelection(%q) = %v, want %v", tt.answer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFileIDs(t *testing.T) {
+	type file struct{ name, path string }
+	files := []file{
+		{"notes.md", "/p/-a/memory/notes.md"},
+		{"notes.md", "/p/-b/memory/notes.md"},
+		{"todo.md", "/p/-a/memory/todo.md"},
+	}
+	tests := []struct {
+		name    string
+		ids     []string
+		wantErr string
+	}{
+		{"unique name", []string{"todo"}, ""},
+		{"unique name with .md", []string{"todo.md"}, ""},
+		{"full path of a shared name", []string{"/p/-b/memory/notes.md"}, ""},
+		{"shared name", []string{"notes"}, "/p/-a/memory/notes.md\n  /p/-b/memory/notes.md"},
+		{"missing", []string{"todo", "gone"}, "no file named gone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFileIDs(tt.ids, files, func(f file) (string, string) { return f.name, f.path })
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("checkFileIDs: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("checkFileIDs: got %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
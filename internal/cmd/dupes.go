@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/dupes"
+	"github.com/baz-sh/clsm/internal/session"
+	dupestui "github.com/baz-sh/clsm/internal/tui/dupes"
+)
+
+var (
+	dupesProject     string
+	dupesThreshold   float64
+	dupesDelete      bool
+	dupesInteractive bool
+)
+
+var dupesCmd = &cobra.Command{
+	Use:   "dupes",
+	Short: "Find duplicate and near-duplicate sessions",
+	Long: `Group sessions that repeat each other: retries, restarts and sessions
+that started with the same prompt.
+
+Sessions are grouped when their first prompts match after ignoring case,
+punctuation and spacing, or when their transcripts are similar (MinHash
+over word shingles, computed locally). Each group marks the longest and
+the newest session; the longest is the one suggested for keeping.
+
+Use --delete to keep the longest session of each group and delete the
+rest. Tagged and pinned sessions are never deleted this way.`,
+	Example: `  clsm dupes
+  clsm dupes -p myapp --threshold 0.6
+  clsm dupes --delete --dry-run
+  clsm dupes -i`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dupesThreshold <= 0 || dupesThreshold > 1 {
+			return errors.New("--threshold must be between 0 and 1")
+		}
+		if dupesInteractive {
+			_, err := tea.NewProgram(dupestui.New(dupesProject, dupesThreshold)).Run()
+			return err
+		}
+
+		sessions, err := session.ListAllSessions()
+		if err != nil {
+			return err
+		}
+		sessions = filterItems(sessions, func(s session.Session) bool {
+			return matchesProject(s.Project, s.ProjectPath, dupesProject)
+		})
+		groups := dupes.Find(sessions, dupesThreshold)
+
+		if dupesDelete {
+			return deleteDupes(groups)
+		}
+		if deleteOpts.json {
+			return writeJSON(newDupeGroups(groups))
+		}
+		if len(groups) == 0 {
+			fmt.Println("No duplicate sessions found.")
+			return nil
+		}
+		printDupes(os.Stdout, groups)
+		return nil
+	},
+}
+
+func init() {
+	flags := dupesCmd.Flags()
+	flags.StringVarP(&dupesProject, "project", "p", "", "only include sessions in projects whose path contains this term")
+	flags.Float64Var(&dupesThreshold, "threshold", dupes.DefaultThreshold, "transcript similarity (0-1) at which sessions count as near-duplicates")
+	flags.BoolVar(&dupesDelete, "delete", false, "keep the longest session of each group and delete the rest")
+	flags.BoolVarP(&dupesInteractive, "interactive", "i", false, "review the groups in the TUI")
+	flags.BoolVarP(&deleteOpts.dryRun, "dry-run", "n", false, "with --delete, show what would be deleted without deleting")
+	flags.BoolVarP(&deleteOpts.yes, "yes", "y", false, "with --delete, delete without asking")
+	flags.BoolVar(&deleteOpts.json, "json", false, "print as JSON")
+	flags.BoolVar(&deleteOpts.force, "force", false, "with --delete, also delete sessions Claude Code is writing right now")
+}
+
+// printDupes writes one block per group, marking the suggested keeper with
+// "*".
+func printDupes(out io.Writer, groups []dupes.Group) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for i, g := range groups {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Group %d: %d sessions, %s\n", i+1, len(g.Sessions), strings.Join(g.Reasons, " and "))
+		for j, s := range g.Sessions {
+			mark := " "
+			if j == g.Keep() {
+				mark = "*"
+			}
+			fmt.Fprintf(w, "  %s %s\t%s\t%d msgs\t%s\t%s",
+				mark, shortID(s.SessionID), formatTimestamp(s.Modified), s.MsgCount, formatBytes(s.Size),
+				truncateRunes(s.Title(), 50))
+			if labels := dupeLabels(g, j); labels != "" {
+				fmt.Fprintf(w, "\t%s", labels)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	w.Flush()
+}
+
+// dupeLabels names what is notable about session i of the group.
+func dupeLabels(g dupes.Group, i int) string {
+	var labels []string
+	if i == g.Longest {
+		labels = append(labels, "longest")
+	}
+	if i == g.Newest {
+		labels = append(labels, "newest")
+	}
+	if g.Sessions[i].Protected() {
+		labels = append(labels, "protected")
+	}
+	return strings.Join(labels, ", ")
+}
+
+// shortID returns the first block of a session ID, which is enough to
+// pass to the other commands as a prefix.
+func shortID(id string) string {
+	if i := strings.IndexByte(id, '-'); i > 0 {
+		return id[:i]
+	}
+	return id
+}
+
+// deleteDupes deletes every session but the keeper of each group, through
+// the same confirmation as clsm delete.
+func deleteDupes(groups []dupes.Group) error {
+	var extra []session.Session
+	var protected int
+	for _, g := range groups {
+		for i, s := range g.Sessions {
+			switch {
+			case i == g.Keep():
+			case s.Protected():
+				protected++
+			default:
+				extra = append(extra, s)
+			}
+		}
+	}
+	if protected > 0 {
+		out := io.Writer(os.Stdout)
+		if deleteOpts.json {
+			out = os.Stderr
+		}
+		fmt.Fprintf(out, "Keeping %d tagged or pinned duplicate(s).\n\n", protected)
+	}
+	if !deleteOpts.force {
+		out := io.Writer(os.Stdout)
+		if deleteOpts.json {
+			out = os.Stderr
+		}
+		extra = skipLive(out, extra, "delete")
+	}
+
+	describe := func(s session.Session) deleteItem {
+		return deleteItem{ID: s.SessionID, Title: s.Title(), Path: s.FullPath, key: s.Key(), details: []string{
+			"Project: " + s.ProjectPath,
+			fmt.Sprintf("Created: %s  Messages: %d", s.Created, s.MsgCount),
+		}}
+	}
+	remove := func(selected []session.Session) map[string]string {
+		errs := make(map[string]string)
+		if !deleteOpts.force {
+			var live []session.Session
+			selected, live = session.SplitLive(selected)
+			for _, s := range live {
+				errs[s.Key()] = "in use by Claude Code (" + s.Live + "); use --force to delete it anyway"
+			}
+		}
+		for _, r := range session.Delete(selected) {
+			if !r.Success {
+				errs[r.Key()] = r.Error
+			}
+		}
+		return errs
+	}
+	return confirmAndDelete("duplicate session", "", extra, describe, remove)
+}
+
+// dupeGroup is the JSON form of a duplicate group.
+type dupeGroup struct {
+	Reasons  []string         `json:"reasons"`
+	Keep     string           `json:"keep"`
+	Longest  string           `json:"longest"`
+	Newest   string           `json:"newest"`
+	Sessions []dupeGroupEntry `json:"sessions"`
+}
+
+type dupeGroupEntry struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	ProjectPath string `json:"projectPath"`
+	Modified    string `json:"modified"`
+	Messages    int    `json:"messages"`
+	Size        int64  `json:"size"`
+	Protected   bool   `json:"protected"`
+}
+
+func newDupeGroups(groups []dupes.Group) []dupeGroup {
+	out := make([]dupeGroup, 0, len(groups))
+	for _, g := range groups {
+		dg := dupeGroup{
+			Reasons: g.Reasons,
+			Keep:    g.Sessions[g.Keep()].SessionID,
+			Longest: g.Sessions[g.Longest].SessionID,
+			Newest:  g.Sessions[g.Newest].SessionID,
+		}
+		for _, s := range g.Sessions {
+			dg.Sessions = append(dg.Sessions, dupeGroupEntry{
+				ID:          s.SessionID,
+				Title:       s.Title(),
+				ProjectPath: s.ProjectPath,
+				Modified:    s.Modified,
+				Messages:    s.MsgCount,
+				Size:        s.Size,
+				Protected:   s.Protected(),
+			})
+		}
+		out = append(out, dg)
+	}
+	return out
+}
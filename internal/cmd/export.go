@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/bundle"
+	"github.com/baz-sh/clsm/internal/redact"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+var (
+	exportProject  string
+	exportOutput   string
+	exportSanitize bool
+	exportTerms    []string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [session-id|prefix...]",
+	Short: "Pack sessions into a bundle to share or move",
+	Long: `Write sessions to a bundle: their transcripts, subagent transcripts,
+sessions-index.json entries, and clsm tags and notes, described by a
+manifest.json. The bundle is a gzipped tar file when the output ends in
+.tar.gz or .tgz, and a directory otherwise.
+
+Name sessions by ID or prefix, or use --project alone to export every
+session of the matching projects.
+
+--sanitize prepares a bundle for sharing outside the team. The home
+directory becomes ~, the user name and host name become "user" and
+"host", every secret that clsm secrets scan finds becomes [REDACTED], and
+so do configured terms. Terms are read from sanitize.txt in clsm's config
+directory, one per line, optionally as term=placeholder, and can be added
+with --term. Your own files are never changed.`,
+	Example: `  clsm export 3f2a 9c1d -o handover.tar.gz
+  clsm export -p myapp -o myapp-sessions
+  clsm export 3f2a --sanitize --term "Acme Corp=[CUSTOMER]" -o share.tgz`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sessions, err := exportTargets(args)
+		if err != nil {
+			return err
+		}
+		if exportOutput == "" {
+			exportOutput = "clsm-export-" + time.Now().Format("20060102-150405") + ".tar.gz"
+		}
+
+		var r redact.Replacer
+		if exportSanitize {
+			terms, err := redact.LoadTerms()
+			if err != nil {
+				return err
+			}
+			for _, t := range exportTerms {
+				terms = append(terms, redact.ParseTerm(t))
+			}
+			r = redact.Chain(redact.Secrets(redact.DefaultReplacement), redact.Sanitizer(terms))
+		} else if len(exportTerms) > 0 {
+			return errors.New("--term needs --sanitize")
+		}
+
+		m, err := bundle.Export(exportOutput, sessions, r)
+		if err != nil {
+			return err
+		}
+		note := ""
+		if m.Sanitized {
+			note = " (sanitized)"
+		}
+		fmt.Printf("Exported %d session(s) to %s%s\n", len(m.Sessions), exportOutput, note)
+		return nil
+	},
+}
+
+func init() {
+	flags := exportCmd.Flags()
+	flags.StringVarP(&exportProject, "project", "p", "", "limit to projects whose path contains this term; with no sessions named, export all of them")
+	flags.StringVarP(&exportOutput, "output", "o", "", "bundle to write: a .tar.gz or .tgz file, or a new directory (default clsm-export-<time>.tar.gz)")
+	flags.BoolVar(&exportSanitize, "sanitize", false, "replace home paths, user and host names, secrets and configured terms with placeholders")
+	flags.StringArrayVar(&exportTerms, "term", nil, "with --sanitize, also replace this text, as term or term=placeholder (repeatable)")
+}
+
+// exportTargets returns the sessions named by args, or with no args every
+// session in the projects matching --project.
+func exportTargets(args []string) ([]session.Session, error) {
+	if len(args) == 0 {
+		if exportProject == "" {
+			return nil, errors.New("name sessions to export, or use --project")
+		}
+		sessions, err := session.ListAllSessions()
+		if err != nil {
+			return nil, err
+		}
+		sessions = filterItems(sessions, func(s session.Session) bool {
+			return matchesProject(s.Project, s.ProjectPath, exportProject)
+		})
+		if len(sessions) == 0 {
+			return nil, fmt.Errorf("no sessions in projects matching %q", exportProject)
+		}
+		return sessions, nil
+	}
+
+	var sessions []session.Session
+	for _, id := range args {
+		s, err := session.Find(id, exportProject)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return dedupe(sessions, session.Session.Key), nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/bundle"
+)
+
+var (
+	importMaps   []string
+	importRename bool
+	importDryRun bool
+	importJSON   bool
+	importForce  bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <bundle-or-dir>",
+	Short: "Import sessions from a bundle or another machine",
+	Long: `Copy sessions into Claude Code's projects directory so that they can be
+resumed here. The source is a bundle written by clsm export, or a copy of
+another machine's ~/.claude/projects (or one project directory in it).
+
+Each session goes into the project directory for its project path. Use
+--map to translate paths from the other machine: the cwd of every
+transcript entry and the projectPath and fullPath of its sessions-index.json
+entry are rewritten, and a path starting with ~, as in sanitized bundles,
+is taken to be in your home directory. Index entries are merged into the
+project's sessions-index.json, and clsm tags, pins and notes are restored.
+
+A session whose ID is already here is skipped when it has the same
+messages or fewer, and brought up to date when the copy here is an earlier
+part of it, unless Claude Code is writing it right now and --force is not
+given. A different session with the same ID is a conflict: it is
+skipped, or with --rename imported under a new ID.`,
+	Example: `  clsm import handover.tar.gz
+  clsm import laptop-projects/ --map /Users/alice=/home/alice
+  clsm import share.tgz --rename -n`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var maps []bundle.Mapping
+		for _, s := range importMaps {
+			m, err := bundle.ParseMapping(s)
+			if err != nil {
+				return err
+			}
+			maps = append(maps, m)
+		}
+		b, err := bundle.Open(args[0])
+		if err != nil {
+			return err
+		}
+
+		results := b.Import(bundle.ImportOptions{Maps: maps, Rename: importRename, DryRun: importDryRun, Force: importForce})
+		if importJSON {
+			return writeJSON(results)
+		}
+		printImport(os.Stdout, results)
+
+		counts := map[string]int{}
+		for _, r := range results {
+			counts[r.Status]++
+		}
+		verb := "Imported"
+		if importDryRun {
+			verb = "Would import"
+		}
+		fmt.Printf("\n%s %d session(s)", verb, counts[bundle.StatusImported]+counts[bundle.StatusRenamed])
+		if n := counts[bundle.StatusUpdated]; n > 0 {
+			fmt.Printf(", update %d", n)
+		}
+		fmt.Printf("; %d already here, %d conflicting, %d in use, %d failed.\n",
+			counts[bundle.StatusExists], counts[bundle.StatusConflict], counts[bundle.StatusLive], counts[bundle.StatusFailed])
+		if counts[bundle.StatusConflict] > 0 && !importRename {
+			fmt.Println("Use --rename to import conflicting sessions under new IDs.")
+		}
+		if counts[bundle.StatusLive] > 0 {
+			fmt.Println("Use --force to update sessions Claude Code is writing.")
+		}
+		if counts[bundle.StatusFailed] > 0 {
+			return fmt.Errorf("%d session(s) failed to import", counts[bundle.StatusFailed])
+		}
+		return nil
+	},
+}
+
+func init() {
+	flags := importCmd.Flags()
+	flags.StringArrayVar(&importMaps, "map", nil, "rewrite paths starting with FROM to start with TO, as FROM=TO (repeatable)")
+	flags.BoolVar(&importRename, "rename", false, "import sessions whose ID is taken by a different session under a new ID")
+	flags.BoolVarP(&importDryRun, "dry-run", "n", false, "show what would be imported without writing anything")
+	flags.BoolVar(&importJSON, "json", false, "output results as JSON")
+	flags.BoolVar(&importForce, "force", false, "also update sessions Claude Code is writing right now")
+}
+
+func printImport(out io.Writer, results []bundle.Result) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tID\tPROJECT\tTITLE")
+	for _, r := range results {
+		id := shortID(r.ID)
+		if r.NewID != "" {
+			id += " → " + shortID(r.NewID)
+		}
+		title := truncateRunes(r.Title, 40)
+		if r.Error != "" {
+			title = r.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Status, id, r.ProjectPath, title)
+	}
+	w.Flush()
+}
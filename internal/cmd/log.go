@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/timeline"
+)
+
+var (
+	logOps   []string
+	logKinds []string
+	logSince string
+	logLimit int
+	logJSON  bool
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log [term]",
+	Short: "Show what clsm has changed",
+	Long: `Show the audit log of every change clsm has made to Claude Code's
+files, newest first: deletes, prunes, renames, copies and moves, imports,
+redactions, syncs, index restores, sweeps and files edited from the TUI.
+
+Each entry has the files it touched and, where there is one, the value
+before and after, such as the old title. A session that has gone missing
+without a delete, prune or move here was not removed by clsm.
+
+A term matches session IDs and prefixes, memory and plan file names,
+paths and the before and after values. The log is audit.jsonl in clsm's
+config directory.`,
+	Example: `  clsm log 3f2a
+  clsm log --op delete,prune --since 7d
+  clsm log --kind memory --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := audit.Read()
+		if err != nil {
+			return err
+		}
+		var since time.Time
+		if logSince != "" {
+			if since, err = timeline.ParseSince(logSince, time.Now()); err != nil {
+				return err
+			}
+		}
+		var term string
+		if len(args) > 0 {
+			term = strings.ToLower(args[0])
+		}
+
+		// Newest first, as far as the limit.
+		out := []audit.Entry{}
+		for i := len(entries) - 1; i >= 0; i-- {
+			e := entries[i]
+			if e.Time.Before(since) || !logMatches(e, term) {
+				continue
+			}
+			if len(logOps) > 0 && !contains(logOps, e.Op) {
+				continue
+			}
+			if len(logKinds) > 0 && !contains(logKinds, e.Kind) {
+				continue
+			}
+			out = append(out, e)
+			if logLimit > 0 && len(out) == logLimit {
+				break
+			}
+		}
+
+		if logJSON {
+			return writeJSON(out)
+		}
+		if len(out) == 0 {
+			fmt.Println("No changes logged.")
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tOP\tKIND\tID\tCHANGE")
+		for _, e := range out {
+			id := e.ID
+			if e.Kind == audit.KindSession || e.Kind == audit.KindArtifact {
+				id = shortID(id)
+			}
+			if id == "" {
+				id = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Time.Local().Format("2006-01-02 15:04:05"), e.Op, e.Kind, id, logChange(e))
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	flags := logCmd.Flags()
+	flags.StringSliceVar(&logOps, "op", nil, "only these operations: delete, prune, rename, copy, move, import, redact, sync, edit, restore, sweep")
+	flags.StringSliceVar(&logKinds, "kind", nil, "only changes to these: session, memory, plan, index, artifact")
+	flags.StringVar(&logSince, "since", "", "only changes since: days or weeks (7d, 2w), a duration (36h), today, yesterday or a date (2006-01-02)")
+	flags.IntVarP(&logLimit, "limit", "n", 50, "show at most this many entries; 0 for all")
+	flags.BoolVar(&logJSON, "json", false, "print as JSON")
+}
+
+// logMatches reports whether an entry matches a lowercased search term.
+func logMatches(e audit.Entry, term string) bool {
+	if term == "" {
+		return true
+	}
+	fields := append([]string{e.ID, e.Before, e.After}, e.Paths...)
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), term) {
+			return true
+		}
+	}
+	return false
+}
+
+// logChange describes an entry in a line: the value before and after,
+// and the file it touched unless a title already says which item it was.
+func logChange(e audit.Entry) string {
+	var change string
+	switch {
+	case e.Before != "" && e.After != "":
+		change = e.Before + " → " + e.After
+	case e.Before != "":
+		change = e.Before
+	case e.After != "":
+		change = e.After
+	}
+	switch e.Op {
+	case audit.OpDelete, audit.OpPrune, audit.OpRename, audit.OpCopy, audit.OpMove:
+		if change != "" {
+			return truncateRunes(firstLine(change), 100)
+		}
+	}
+	if len(e.Paths) > 0 {
+		if change != "" {
+			change += ": "
+		}
+		change += e.Paths[0]
+		if len(e.Paths) > 1 {
+			change += fmt.Sprintf(" and %d more", len(e.Paths)-1)
+		}
+	}
+	return truncateRunes(firstLine(change), 100)
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
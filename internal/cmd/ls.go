@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/memory"
+	"github.com/baz-sh/clsm/internal/plan"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+// lsOptions holds the flags shared by all ls subcommands.
+type lsOptions struct {
+	format   string
+	fields   string
+	sortBy   string
+	reverse  bool
+	noHeader bool
+	filter   string
+	project  string
+	search   string
+	branch   string
+	gone     bool
+}
+
+var lsOpts lsOptions
+
+var lsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List projects, sessions, memories or plans",
+	Long: `List Claude Code data without opening the TUI.
+
+Output defaults to an aligned table on a terminal and to tab-separated
+values when piped. Use --format json for scripting with jq.`,
+}
+
+var lsProjectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "List projects that contain sessions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projects, err := session.ListProjects()
+		if err != nil {
+			return err
+		}
+		projects = filterItems(projects, func(p session.Project) bool {
+			return p.Matches(lsOpts.filter) && p.Matches(lsOpts.project)
+		})
+		return runList(projects, projectColumns, withProfile("path", "sessions", "modified"))
+	},
+}
+
+var lsSessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "List sessions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var sessions []session.Session
+		var err error
+		if lsOpts.search != "" {
+			sessions, err = session.Search(lsOpts.search)
+		} else {
+			sessions, err = session.ListAllSessions()
+		}
+		if err != nil {
+			return err
+		}
+		sessions = filterItems(sessions, func(s session.Session) bool {
+			return s.Matches(lsOpts.filter) && matchesProject(s.Project, s.ProjectPath, lsOpts.project) &&
+				(!cmd.Flags().Changed("branch") || s.GitBranch == lsOpts.branch)
+		})
+		// Checking branches runs git once per project, so only do it when
+		// the result is used.
+		if lsOpts.gone || strings.Contains(lsOpts.fields, "branchGone") || lsOpts.sortBy == "branchGone" {
+			session.MarkGoneBranches(sessions)
+		}
+		if lsOpts.gone {
+			sessions = filterItems(sessions, func(s session.Session) bool { return s.BranchGone })
+		}
+		return runList(sessions, sessionColumns, withProfile("id", "title", "projectPath", "branch", "modified", "messages"))
+	},
+}
+
+var lsMemoriesCmd = &cobra.Command{
+	Use:   "memories",
+	Short: "List memory files",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projects, err := memory.ListProjects()
+		if err != nil {
+			return err
+		}
+		var memories []memory.Memory
+		for _, p := range projects {
+			if !matchesProject(p.DirName, p.Path, lsOpts.project) {
+				continue
+			}
+			mems, err := memory.ListMemories(p.Profile, p.DirName)
+			if err != nil {
+				continue
+			}
+			memories = append(memories, mems...)
+		}
+		memories = filterItems(memories, func(m memory.Memory) bool {
+			return m.Matches(lsOpts.filter)
+		})
+		return runList(memories, memoryColumns, withProfile("name", "type", "projectPath", "modified"))
+	},
+}
+
+var lsPlansCmd = &cobra.Command{
+	Use:   "plans",
+	Short: "List plan files",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plans, err := plan.ListPlans()
+		if err != nil {
+			return err
+		}
+		plans = filterItems(plans, func(p plan.Plan) bool {
+			return p.Matches(lsOpts.filter) && strings.Contains(strings.ToLower(p.ProjectHint), strings.ToLower(lsOpts.project))
+		})
+		return runList(plans, planColumns, withProfile("file", "title", "project", "modified"))
+	},
+}
+
+func init() {
+	flags := lsCmd.PersistentFlags()
+	flags.StringVarP(&lsOpts.format, "format", "f", "", "output format: table, tsv, csv or json (default table on a terminal, tsv otherwise)")
+	flags.StringVar(&lsOpts.fields, "fields", "", "comma-separated list of fields to output")
+	flags.StringVar(&lsOpts.sortBy, "sort", "", "field to sort by")
+	flags.BoolVarP(&lsOpts.reverse, "reverse", "r", false, "reverse the sort order")
+	flags.BoolVar(&lsOpts.noHeader, "no-header", false, "omit the header row (table, tsv and csv)")
+	flags.StringVar(&lsOpts.filter, "filter", "", "only list items matching this term, like / in the TUI")
+	flags.StringVarP(&lsOpts.project, "project", "p", "", "only list items whose project path contains this term")
+	lsSessionsCmd.Flags().StringVarP(&lsOpts.search, "search", "s", "", "search sessions like the TUI search (title, summary, project)")
+	lsSessionsCmd.Flags().StringVar(&lsOpts.branch, "branch", "", "only list sessions on this git branch (empty for none)")
+	lsSessionsCmd.Flags().BoolVar(&lsOpts.gone, "gone", false, "only list sessions whose branch no longer exists in the local repo")
+
+	lsCmd.AddCommand(lsProjectsCmd)
+	lsCmd.AddCommand(lsSessionsCmd)
+	lsCmd.AddCommand(lsMemoriesCmd)
+	lsCmd.AddCommand(lsPlansCmd)
+}
+
+// runList sorts, selects fields and writes items to stdout.
+func runList[T any](items []T, all []column[T], defaults []string) error {
+	cols, err := selectColumns(all, defaults, lsOpts.fields)
+	if err != nil {
+		return err
+	}
+	if err := sortItems(items, all, lsOpts.sortBy, lsOpts.reverse); err != nil {
+		return err
+	}
+	format := lsOpts.format
+	if format == "" {
+		format = defaultFormat()
+	}
+	return writeItems(os.Stdout, format, cols, items, !lsOpts.noHeader)
+}
+
+// withProfile returns the default fields, led by the profile when more
+// than one profile is listed.
+func withProfile(fields ...string) []string {
+	if len(claude.Roots()) > 1 {
+		return append([]string{"profile"}, fields...)
+	}
+	return fields
+}
+
+func filterItems[T any](items []T, keep func(T) bool) []T {
+	out := items[:0]
+	for _, item := range items {
+		if keep(item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// matchesProject reports whether a project, given by its encoded directory
+// name and path, matches a --project term. An empty term matches everything.
+func matchesProject(dirName, path, term string) bool {
+	if term == "" || dirName == term {
+		return true
+	}
+	return strings.Contains(strings.ToLower(path), strings.ToLower(term))
+}
+
+var projectColumns = []column[session.Project]{
+	{"profile", func(p session.Project) any { return p.Profile }},
+	{"dir", func(p session.Project) any { return p.DirName }},
+	{"path", func(p session.Project) any { return p.Path }},
+	{"sessions", func(p session.Project) any { return p.SessionCount }},
+	{"size", func(p session.Project) any { return p.TotalSize }},
+	{"modified", func(p session.Project) any { return p.LastModified }},
+	{"lastPrompt", func(p session.Project) any { return p.LastPrompt }},
+}
+
+var sessionColumns = []column[session.Session]{
+	{"id", func(s session.Session) any { return s.SessionID }},
+	{"profile", func(s session.Session) any { return s.Profile }},
+	{"title", func(s session.Session) any { return s.Title() }},
+	{"customTitle", func(s session.Session) any { return s.CustomTitle }},
+	{"summary", func(s session.Session) any { return s.Summary }},
+	{"firstPrompt", func(s session.Session) any { return s.FirstPrompt }},
+	{"project", func(s session.Session) any { return s.Project }},
+	{"projectPath", func(s session.Session) any { return s.ProjectPath }},
+	{"branch", func(s session.Session) any { return s.GitBranch }},
+	{"branchGone", func(s session.Session) any { return s.BranchGone }},
+	{"created", func(s session.Session) any { return s.Created }},
+	{"modified", func(s session.Session) any { return s.Modified }},
+	{"messages", func(s session.Session) any { return s.MsgCount }},
+	{"size", func(s session.Session) any { return s.Size }},
+	{"subagents", func(s session.Session) any { return len(s.Subagents) }},
+	{"tags", func(s session.Session) any { return strings.Join(s.Tags, ",") }},
+	{"pinned", func(s session.Session) any { return s.Pinned }},
+	{"note", func(s session.Session) any { return s.Note }},
+	{"live", func(s session.Session) any { return s.Live }},
+	{"file", func(s session.Session) any { return s.FullPath }},
+}
+
+var memoryColumns = []column[memory.Memory]{
+	{"name", func(m memory.Memory) any { return m.Name }},
+	{"type", func(m memory.Memory) any { return m.Type }},
+	{"description", func(m memory.Memory) any { return m.Description }},
+	{"profile", func(m memory.Memory) any { return m.Profile }},
+	{"project", func(m memory.Memory) any { return m.ProjectDir }},
+	{"projectPath", func(m memory.Memory) any { return m.ProjectPath }},
+	{"modified", func(m memory.Memory) any { return m.ModTime }},
+	{"note", func(m memory.Memory) any { return m.Note }},
+	{"fileName", func(m memory.Memory) any { return m.FileName }},
+	{"file", func(m memory.Memory) any { return m.FullPath }},
+}
+
+var planColumns = []column[plan.Plan]{
+	{"file", func(p plan.Plan) any { return p.FileName }},
+	{"title", func(p plan.Plan) any { return p.Title }},
+	{"context", func(p plan.Plan) any { return p.Context }},
+	{"profile", func(p plan.Plan) any { return p.Profile }},
+	{"project", func(p plan.Plan) any { return p.ProjectHint }},
+	{"modified", func(p plan.Plan) any { return p.ModTime }},
+	{"size", func(p plan.Plan) any { return p.Size }},
+	{"note", func(p plan.Plan) any { return p.Note }},
+	{"path", func(p plan.Plan) any { return p.FullPath }},
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/memory"
+	"github.com/baz-sh/clsm/internal/meta"
+	"github.com/baz-sh/clsm/internal/plan"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+// noteOptions holds the flags shared by the note commands.
+type noteOptions struct {
+	show    bool
+	message string
+	clear   bool
+	project string
+}
+
+var noteOpts noteOptions
+
+var noteCmd = &cobra.Command{
+	Use:   "note <session-id|prefix>",
+	Short: "Write a note on a session, memory or plan",
+	Long: `Open a markdown note on a session in $EDITOR.
+
+Notes are stored in clsm's own data directory, never in Claude Code's
+files. They show up in "clsm show", in the TUI, in the / filter and
+search, and in "clsm ls" output.
+
+Use "note memory" and "note plan" to write notes on memories and plans.`,
+	Example: `  clsm note 3f2a
+  clsm note 3f2a -m "This is where we decided to drop Redis"
+  clsm note 3f2a --show
+  clsm note plan fluffy-coalescing-giraffe`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := session.Find(args[0], noteOpts.project)
+		if err != nil {
+			return err
+		}
+		return runNote(meta.NoteSession, s.SessionID)
+	},
+}
+
+var noteMemoryCmd = &cobra.Command{
+	Use:   "memory <file>",
+	Short: "Write a note on a memory file",
+	Long: `Open a markdown note on a memory file in $EDITOR. The file is given by
+name (with or without .md) or full path; use --project when several
+projects have a memory with the same name.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := findMemory(args[0], noteOpts.project)
+		if err != nil {
+			return err
+		}
+		return runNote(meta.NoteMemory, meta.MemoryNoteKey(m.ProjectDir, m.FileName))
+	},
+}
+
+var notePlanCmd = &cobra.Command{
+	Use:   "plan <file>",
+	Short: "Write a note on a plan file",
+	Long:  `Open a markdown note on a plan file in $EDITOR. The file is given by name (with or without .md) or full path.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plans, err := plan.ListPlans()
+		if err != nil {
+			return err
+		}
+		for _, p := range plans {
+			if matchesFileID(p.FileName, p.FullPath, args) {
+				return runNote(meta.NotePlan, p.FileName)
+			}
+		}
+		return fmt.Errorf("no plan named %s", args[0])
+	},
+}
+
+func init() {
+	flags := noteCmd.PersistentFlags()
+	flags.BoolVar(&noteOpts.show, "show", false, "print the note instead of editing it")
+	flags.StringVarP(&noteOpts.message, "message", "m", "", `set the note to this text without opening an editor ("-" reads stdin)`)
+	flags.BoolVar(&noteOpts.clear, "clear", false, "remove the note")
+	flags.StringVarP(&noteOpts.project, "project", "p", "", "only consider items in projects whose path contains this term")
+
+	noteCmd.AddCommand(noteMemoryCmd)
+	noteCmd.AddCommand(notePlanCmd)
+}
+
+// runNote shows, sets, clears or edits the note for key according to the
+// flags.
+func runNote(kind meta.NoteKind, key string) error {
+	switch {
+	case noteOpts.show:
+		note, err := meta.ReadNote(kind, key)
+		if err != nil {
+			return err
+		}
+		if note == "" {
+			fmt.Fprintln(os.Stderr, "No note.")
+			return nil
+		}
+		fmt.Println(note)
+		return nil
+	case noteOpts.clear:
+		return meta.WriteNote(kind, key, "")
+	case noteOpts.message != "":
+		text := noteOpts.message
+		if text == "-" {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return err
+			}
+			text = string(data)
+		}
+		return meta.WriteNote(kind, key, text)
+	}
+
+	path, err := meta.PrepareNote(kind, key)
+	if err != nil {
+		return err
+	}
+	editor := claude.Editor()
+	c := exec.Command(editor, path)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	runErr := c.Run()
+	if err := meta.TidyNote(path); err != nil {
+		return err
+	}
+	if runErr != nil {
+		return fmt.Errorf("running %s: %w", editor, runErr)
+	}
+	return nil
+}
+
+// findMemory returns the memory named by id (file name with or without .md,
+// or full path) in projects matching the project term.
+func findMemory(id, project string) (memory.Memory, error) {
+	projects, err := memory.ListProjects()
+	if err != nil {
+		return memory.Memory{}, err
+	}
+	var matches []memory.Memory
+	for _, p := range projects {
+		if !matchesProject(p.DirName, p.Path, project) {
+			continue
+		}
+		mems, err := memory.ListMemories(p.Profile, p.DirName)
+		if err != nil {
+			continue
+		}
+		for _, m := range mems {
+			if matchesFileID(m.FileName, m.FullPath, []string{id}) {
+				matches = append(matches, m)
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return memory.Memory{}, fmt.Errorf("no memory named %s", id)
+	case 1:
+		return matches[0], nil
+	}
+	paths := make([]string, len(matches))
+	for i, m := range matches {
+		paths[i] = m.ProjectPath
+	}
+	return memory.Memory{}, errors.New("memory " + id + " exists in several projects; use --project: " + strings.Join(paths, ", "))
+}
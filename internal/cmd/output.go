@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Output formats supported by the non-interactive commands.
+const (
+	formatTable = "table"
+	formatTSV   = "tsv"
+	formatCSV   = "csv"
+	formatJSON  = "json"
+)
+
+// column describes one selectable field of a listed item.
+type column[T any] struct {
+	name  string
+	value func(T) any
+}
+
+// defaultFormat returns the table format when stdout is a terminal and
+// tab-separated output otherwise, so piped output stays easy to parse.
+func defaultFormat() string {
+	if isTerminal(os.Stdout) {
+		return formatTable
+	}
+	return formatTSV
+}
+
+// isTerminal reports whether f is attached to a character device.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// selectColumns returns the columns named in fields (comma-separated), or
+// the defaults when fields is empty.
+func selectColumns[T any](all []column[T], defaults []string, fields string) ([]column[T], error) {
+	names := defaults
+	if strings.TrimSpace(fields) != "" {
+		names = nil
+		for _, f := range strings.Split(fields, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				names = append(names, f)
+			}
+		}
+	}
+
+	byName := make(map[string]column[T], len(all))
+	for _, c := range all {
+		byName[c.name] = c
+	}
+
+	cols := make([]column[T], 0, len(names))
+	for _, n := range names {
+		c, ok := byName[n]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q (available: %s)", n, columnNames(all))
+		}
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+func columnNames[T any](cols []column[T]) string {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = c.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// sortItems sorts items by the named column. Numbers compare numerically,
+// everything else as strings.
+func sortItems[T any](items []T, all []column[T], field string, reverse bool) error {
+	if field == "" {
+		if reverse {
+			for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+				items[i], items[j] = items[j], items[i]
+			}
+		}
+		return nil
+	}
+
+	var col *column[T]
+	for i := range all {
+		if all[i].name == field {
+			col = &all[i]
+			break
+		}
+	}
+	if col == nil {
+		return fmt.Errorf("unknown sort field %q (available: %s)", field, columnNames(all))
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := col.value(items[i]), col.value(items[j])
+		if reverse {
+			a, b = b, a
+		}
+		return lessValue(a, b)
+	})
+	return nil
+}
+
+func lessValue(a, b any) bool {
+	switch av := a.(type) {
+	case int:
+		bv, _ := b.(int)
+		return av < bv
+	case int64:
+		bv, _ := b.(int64)
+		return av < bv
+	case bool:
+		bv, _ := b.(bool)
+		return !av && bv
+	}
+	return strings.ToLower(fmt.Sprint(a)) < strings.ToLower(fmt.Sprint(b))
+}
+
+// writeItems renders items in the given format.
+func writeItems[T any](w io.Writer, format string, cols []column[T], items []T, header bool) error {
+	switch format {
+	case formatJSON:
+		records := make([]json.RawMessage, len(items))
+		for i, item := range items {
+			rec, err := orderedRecord(cols, item)
+			if err != nil {
+				return err
+			}
+			records[i] = rec
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(records)
+
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if header {
+			if err := cw.Write(headerRow(cols)); err != nil {
+				return err
+			}
+		}
+		for _, item := range items {
+			if err := cw.Write(plainRow(cols, item)); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+
+	case formatTSV:
+		if header {
+			fmt.Fprintln(w, strings.Join(headerRow(cols), "\t"))
+		}
+		for _, item := range items {
+			row := plainRow(cols, item)
+			for i, v := range row {
+				row[i] = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(v)
+			}
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return nil
+
+	case formatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		if header {
+			fmt.Fprintln(tw, strings.ToUpper(strings.Join(headerRow(cols), "\t")))
+		}
+		for _, item := range items {
+			row := make([]string, len(cols))
+			for i, c := range cols {
+				row[i] = tableCell(c.value(item))
+			}
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+
+	return fmt.Errorf("unknown format %q (available: table, tsv, csv, json)", format)
+}
+
+// orderedRecord encodes an item as a JSON object whose keys follow the
+// column order, which encoding/json does not preserve for maps.
+func orderedRecord[T any](cols []column[T], item T) (json.RawMessage, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, c := range cols {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, _ := marshalJSON(c.name)
+		v, err := marshalJSON(c.value(item))
+		if err != nil {
+			return nil, fmt.Errorf("encoding field %s: %w", c.name, err)
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return json.RawMessage(b.String()), nil
+}
+
+// marshalJSON is json.Marshal without HTML escaping, so prompts containing
+// <, > or & stay readable.
+func marshalJSON(v any) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
+
+func headerRow[T any](cols []column[T]) []string {
+	row := make([]string, len(cols))
+	for i, c := range cols {
+		row[i] = c.name
+	}
+	return row
+}
+
+func plainRow[T any](cols []column[T], item T) []string {
+	row := make([]string, len(cols))
+	for i, c := range cols {
+		row[i] = fmt.Sprint(c.value(item))
+	}
+	return row
+}
+
+// tableCell formats a value for human-readable table output: timestamps
+// in local time and long text cut to a single short line.
+func tableCell(v any) string {
+	s := fmt.Sprint(v)
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.Local().Format("2006-01-02 15:04")
+	}
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if r := []rune(s); len(r) > 60 {
+		s = string(r[:59]) + "…"
+	}
+	return s
+}
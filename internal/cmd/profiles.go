@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+var profilesJSON bool
+
+var profilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List, add and remove named Claude Code data directories",
+	Long: `List the registered profiles: named Claude Code data directories that
+clsm lists together, such as separate work and personal config dirs or
+the ~/.claude of every user on a shared machine.
+
+Once any profile is registered, every command uses all of them instead of
+the default data directory. Lists show which profile each item is in,
+and the global --profile flag narrows them to some. A glob such as
+/home/*/.claude registers a profile per matching directory, named after
+the part the wildcard matched. A directory that doesn't exist is listed
+but otherwise ignored.`,
+	Example: `  clsm profiles add work=~/.claude personal=~/.claude-personal
+  clsm profiles add 'build=/home/*/.claude'
+  clsm profiles rm personal
+  clsm ls sessions --profile work`,
+	Args: cobra.NoArgs,
+	// The registry is managed here, so it mustn't have to resolve first.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registered, err := claude.LoadProfiles()
+		if err != nil {
+			return err
+		}
+		if len(registered) == 0 && !profilesJSON {
+			fmt.Printf("No profiles registered; using %s.\n", claude.DefaultDir())
+			return nil
+		}
+		return printProfiles(registered)
+	},
+}
+
+var profilesAddCmd = &cobra.Command{
+	Use:   "add <[name=]dir>...",
+	Short: "Register data directories",
+	Long: `Register data directories as profiles. A directory without a name is
+named after its base name without a leading dot, so ~/.claude-personal
+becomes claude-personal. Quote globs so the shell leaves them alone.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registered, err := claude.LoadProfiles()
+		if err != nil {
+			return err
+		}
+		for _, arg := range args {
+			p, err := claude.ParseProfile(arg)
+			if err != nil {
+				return err
+			}
+			for _, q := range registered {
+				if q.Dir == p.Dir || (p.Name != "" && q.Name == p.Name) {
+					return fmt.Errorf("%s is already registered as %s", arg, q)
+				}
+			}
+			registered = append(registered, p)
+		}
+		// Resolving checks that no two directories end up with one name.
+		if _, err := claude.Resolve(registered); err != nil {
+			return err
+		}
+		if err := claude.SaveProfiles(registered); err != nil {
+			return err
+		}
+		return printProfiles(registered)
+	},
+}
+
+var profilesRmCmd = &cobra.Command{
+	Use:     "rm <name|dir>...",
+	Aliases: []string{"remove"},
+	Short:   "Unregister profiles",
+	Long: `Unregister profiles, given by name or by the directory they were
+registered with. The data directories themselves are left alone.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registered, err := claude.LoadProfiles()
+		if err != nil {
+			return err
+		}
+		for _, arg := range args {
+			kept := registered[:0]
+			for _, p := range registered {
+				if p.Name != arg && p.Dir != arg {
+					kept = append(kept, p)
+				}
+			}
+			if len(kept) == len(registered) {
+				return fmt.Errorf("no profile %q is registered", arg)
+			}
+			registered = kept
+		}
+		if err := claude.SaveProfiles(registered); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d profile(s).\n", len(args))
+		return nil
+	},
+}
+
+func init() {
+	profilesCmd.Flags().BoolVar(&profilesJSON, "json", false, "print as JSON")
+	profilesCmd.AddCommand(profilesAddCmd)
+	profilesCmd.AddCommand(profilesRmCmd)
+}
+
+// profileRow is a registered profile and a directory it resolves to.
+type profileRow struct {
+	Name       string `json:"name"`
+	Dir        string `json:"dir,omitempty"`
+	Registered string `json:"registered"`
+	ListedAs   string `json:"listedAs,omitempty"` // the earlier profile a directory is listed under
+}
+
+// printProfiles prints each registered profile with the directories it
+// resolves to.
+func printProfiles(registered []claude.Profile) error {
+	all, err := claude.Resolve(registered)
+	if err != nil {
+		return err
+	}
+	names := make(map[string]string, len(all)) // dir -> name it is listed under
+	for _, r := range all {
+		names[r.Dir] = r.Name
+	}
+
+	rows := []profileRow{}
+	for _, p := range registered {
+		roots, err := claude.Resolve([]claude.Profile{p})
+		if err != nil {
+			return err
+		}
+		if len(roots) == 0 {
+			rows = append(rows, profileRow{Name: p.Name, Registered: p.String()})
+		}
+		for _, r := range roots {
+			row := profileRow{Name: r.Name, Dir: r.Dir, Registered: p.String()}
+			if names[r.Dir] != r.Name {
+				row.ListedAs = names[r.Dir]
+			}
+			rows = append(rows, row)
+		}
+	}
+	if profilesJSON {
+		return writeJSON(rows)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDIRECTORY\tREGISTERED AS")
+	for _, r := range rows {
+		name, dir := r.Name, r.Dir
+		if name == "" {
+			name = "-"
+		}
+		switch {
+		case dir == "":
+			dir = "(no such directory)"
+		case r.ListedAs != "":
+			dir += " (listed as " + r.ListedAs + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, dir, r.Registered)
+	}
+	return w.Flush()
+}
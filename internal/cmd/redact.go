@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/redact"
+)
+
+var (
+	redactPatterns   []string
+	redactLiteral    bool
+	redactIgnoreCase bool
+	redactSecrets    bool
+	redactReplace    string
+	redactProject    string
+	redactIn         []string
+	redactDryRun     bool
+	redactYes        bool
+	redactForce      bool
+	redactPurge      bool
+)
+
+var redactCmd = &cobra.Command{
+	Use:   "redact",
+	Short: "Remove text from sessions, memories and plans",
+	Long: `Replace matching text everywhere Claude Code stored it: session
+transcripts, subagent transcripts, the summaries and first prompts in
+sessions-index.json, memories and plans.
+
+Transcripts are rewritten one JSON string value at a time, so every line
+stays valid JSON. IDs, entry types and timestamps are never rewritten,
+which keeps the uuid chain and tool call pairing intact. Files are listed
+for review before anything is written, and each original is copied to
+clsm's backups directory first.
+
+Those copies, the rolling backups of index files and files a sync deleted
+still hold the text. --purge-backups removes every backup that matches,
+after redacting; run with it again to clean up after an earlier redaction.
+
+--pattern is a Go regular expression unless --literal is given. Use
+--secrets to redact everything "clsm secrets scan" finds.`,
+	Example: `  clsm redact --pattern "Acme Corp" --literal -i --dry-run
+  clsm redact --pattern 'ghp_[A-Za-z0-9]{36}' --replace '[GITHUB TOKEN]'
+  clsm redact --secrets -p myapp -y`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		r, err := redactReplacer()
+		if err != nil {
+			return err
+		}
+		opts := redact.Options{Project: redactProject}
+		opts.Sessions, opts.Memories, opts.Plans, err = parseSources(redactIn)
+		if err != nil {
+			return err
+		}
+
+		changes, err := redact.Plan(opts, r)
+		if err != nil {
+			return err
+		}
+		if !redactForce {
+			changes = skipLiveChanges(os.Stdout, changes)
+		}
+		if len(changes) == 0 {
+			fmt.Println("No matches found.")
+			if redactPurge {
+				return purgeBackups(r)
+			}
+			return nil
+		}
+		printChanges(os.Stdout, changes)
+
+		var total int
+		for _, c := range changes {
+			total += c.Count
+		}
+		var chosen []int
+		switch {
+		case redactDryRun:
+			fmt.Printf("\nDry run: %d replacement(s) in %d file(s) would be made.\n", total, len(changes))
+			if redactPurge {
+				return purgeBackups(r)
+			}
+			return nil
+		case redactYes:
+			chosen = allIndices(len(changes))
+		default:
+			fmt.Println()
+			if chosen, err = promptSelection(os.Stdout, "Redact", "file", len(changes)); err != nil {
+				return err
+			}
+		}
+		if len(chosen) == 0 {
+			fmt.Println("Aborted.")
+			return nil
+		}
+
+		selected := make([]redact.Change, len(chosen))
+		for i, idx := range chosen {
+			selected[i] = changes[idx]
+		}
+		backupDir, err := redact.NewBackupDir()
+		if err != nil {
+			return err
+		}
+		var failed int
+		for _, r := range redact.Apply(selected, backupDir) {
+			if r.Success {
+				fmt.Printf("  Redacted: %s\n", r.Path)
+			} else {
+				fmt.Printf("  Failed:   %s — %s\n", r.Path, r.Error)
+				failed++
+			}
+		}
+		if redactPurge {
+			if err := purgeBackups(r); err != nil {
+				return err
+			}
+		} else if failed < len(selected) {
+			fmt.Printf("\nOriginals backed up to %s\n", backupDir)
+			fmt.Println("These and older backups still hold the redacted text; run again with --purge-backups to remove them.")
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d file(s) failed to redact", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	flags := redactCmd.Flags()
+	flags.StringArrayVar(&redactPatterns, "pattern", nil, "text to redact, as a regular expression (repeatable)")
+	flags.BoolVarP(&redactLiteral, "literal", "F", false, "treat --pattern as literal text")
+	flags.BoolVarP(&redactIgnoreCase, "ignore-case", "i", false, "match --pattern ignoring case")
+	flags.BoolVar(&redactSecrets, "secrets", false, "redact every secret that clsm secrets scan finds")
+	flags.StringVar(&redactReplace, "replace", redact.DefaultReplacement, "text to put in place of each match")
+	flags.StringVarP(&redactProject, "project", "p", "", "only redact in projects whose path contains this term")
+	flags.StringSliceVar(&redactIn, "in", []string{"sessions", "memories", "plans"}, "where to redact: sessions, memories and/or plans")
+	flags.BoolVarP(&redactDryRun, "dry-run", "n", false, "show which files would change without changing them")
+	flags.BoolVarP(&redactYes, "yes", "y", false, "redact every matching file without asking")
+	flags.BoolVar(&redactForce, "force", false, "also redact sessions Claude Code is writing right now")
+	flags.BoolVar(&redactPurge, "purge-backups", false, "also remove every backup in clsm's config directory that holds matching text")
+}
+
+// purgeBackups removes the backups that still hold text r matches, or
+// lists them on a dry run.
+func purgeBackups(r redact.Replacer) error {
+	found, err := redact.FindBackups(r)
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		fmt.Println("No backups hold matching text.")
+		return nil
+	}
+	verb := "Would remove"
+	if !redactDryRun {
+		if err := redact.PurgeBackups(found); err != nil {
+			return err
+		}
+		verb = "Removed"
+	}
+	fmt.Printf("\n%s %d backup(s) holding matching text:\n", verb, len(found))
+	for _, p := range found {
+		fmt.Printf("  %s\n", p)
+	}
+	return nil
+}
+
+// skipLiveChanges leaves out the transcripts of sessions Claude Code is
+// writing, listing them on out.
+func skipLiveChanges(out io.Writer, changes []redact.Change) []redact.Change {
+	var kept []redact.Change
+	skipped := make(map[string]string) // session ID -> title
+	for _, c := range changes {
+		if c.Live != "" {
+			skipped[c.SessionID] = c.Title
+			continue
+		}
+		kept = append(kept, c)
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(out, "Skipping %d session(s) Claude Code is writing; use --force to redact them anyway:\n", len(skipped))
+		for id, title := range skipped {
+			fmt.Fprintf(out, "  %s  %s\n", id, title)
+		}
+		fmt.Fprintln(out)
+	}
+	return kept
+}
+
+// redactReplacer builds the replacer for the --pattern and --secrets
+// flags.
+func redactReplacer() (redact.Replacer, error) {
+	if len(redactPatterns) == 0 && !redactSecrets {
+		return nil, errors.New("give a --pattern or --secrets")
+	}
+	var rs []redact.Replacer
+	for _, p := range redactPatterns {
+		if p == "" {
+			return nil, errors.New("--pattern must not be empty")
+		}
+		if redactLiteral {
+			p = regexp.QuoteMeta(p)
+		}
+		if redactIgnoreCase {
+			p = "(?i)" + p
+		}
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --pattern: %w", err)
+		}
+		if re.MatchString("") {
+			return nil, fmt.Errorf("--pattern %q matches empty text", p)
+		}
+		rs = append(rs, redact.Regexp(re, redactReplace))
+	}
+	if redactSecrets {
+		rs = append(rs, redact.Secrets(redactReplace))
+	}
+	return redact.Chain(rs...), nil
+}
+
+// printChanges writes the numbered list of files a redaction changes.
+func printChanges(out io.Writer, changes []redact.Change) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tMATCHES\tKIND\tOF\tFILE")
+	for i, c := range changes {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\n", i+1, c.Count, c.Kind, truncateRunes(c.Title, 40), c.Path)
+	}
+	w.Flush()
+}
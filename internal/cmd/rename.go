@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+var (
+	renameProject  string
+	renameTemplate string
+	renameDryRun   bool
+	renameYes      bool
+	renameForce    bool
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <session-id|prefix> <title>",
+	Short: "Set a session's custom title",
+	Long: `Set a session's custom title, the same way /rename does in Claude Code.
+
+The session may be given by its full ID or any unique prefix. All
+remaining arguments are joined to form the title.
+
+With --template, every argument is a session, or with no arguments every
+session in the --project projects, and each gets a title built from the
+template. Placeholders: ` + session.TemplateFields + `. A placeholder
+with no value is left out along with its separator. The new titles are
+previewed and confirmed before anything is written.
+
+A session Claude Code is writing right now is not renamed unless --force
+is given, since both would be appending to its transcript at once.`,
+	Example: `  clsm rename 3f2a "Auth refactor: token rotation"
+  clsm rename --template "{branch}: {title}" 3f2a 9c1d
+  clsm rename --template "[{date}] {summary}" -p myapp --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("template") {
+			return runRenameTemplate(cmd, args)
+		}
+		if len(args) < 2 {
+			return errors.New("provide a session and a title, or use --template")
+		}
+		title := strings.TrimSpace(strings.Join(args[1:], " "))
+		if title == "" {
+			return errors.New("title cannot be empty")
+		}
+		s, err := session.Find(args[0], renameProject)
+		if err != nil {
+			return err
+		}
+		if !renameForce {
+			if err := session.CheckLive(s); err != nil {
+				return fmt.Errorf("%w; use --force to rename it anyway", err)
+			}
+		}
+		if err := session.Rename(s, title); err != nil {
+			return err
+		}
+		fmt.Printf("Renamed %s: %s → %s\n", s.SessionID, s.Title(), title)
+		return nil
+	},
+}
+
+func init() {
+	renameCmd.Flags().StringVarP(&renameProject, "project", "p", "", "only consider sessions in projects whose path contains this term")
+	renameCmd.Flags().StringVarP(&renameTemplate, "template", "t", "", "rename several sessions with a title template, e.g. \"{branch}: {title}\"")
+	renameCmd.Flags().BoolVarP(&renameDryRun, "dry-run", "n", false, "with --template, show the new titles without applying them")
+	renameCmd.Flags().BoolVarP(&renameYes, "yes", "y", false, "with --template, apply every title without asking")
+	renameCmd.Flags().BoolVar(&renameForce, "force", false, "also rename sessions Claude Code is writing right now")
+}
+
+// runRenameTemplate renames the named sessions, or all sessions in the
+// --project projects, with the title template.
+func runRenameTemplate(cmd *cobra.Command, ids []string) error {
+	if len(ids) == 0 && !cmd.Flags().Changed("project") {
+		return errors.New("provide session IDs or --project")
+	}
+	var sessions []session.Session
+	if len(ids) > 0 {
+		for _, id := range ids {
+			s, err := session.Find(id, renameProject)
+			if err != nil {
+				return err
+			}
+			sessions = append(sessions, s)
+		}
+		sessions = dedupe(sessions, session.Session.Key)
+	} else {
+		all, err := session.ListAllSessions()
+		if err != nil {
+			return err
+		}
+		sessions = filterItems(all, func(s session.Session) bool {
+			return matchesProject(s.Project, s.ProjectPath, renameProject)
+		})
+	}
+
+	var suggestions []titleSuggestion
+	for _, s := range sessions {
+		title, err := session.ExpandTitle(renameTemplate, s)
+		if err != nil {
+			return err
+		}
+		if title == "" || title == s.CustomTitle {
+			continue
+		}
+		suggestions = append(suggestions, titleSuggestion{
+			ID: s.SessionID, Current: s.Title(), Title: title, Source: "template", session: s,
+		})
+	}
+	if len(suggestions) == 0 {
+		fmt.Println("No sessions to rename.")
+		return nil
+	}
+	return applySuggestions(suggestions, renameDryRun, renameYes, renameForce)
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/backup"
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+var (
+	restoreBackup int
+	restoreYes    bool
+)
+
+var restoreIndexCmd = &cobra.Command{
+	Use:   "restore-index [project|path]",
+	Short: "Roll an index file back to an earlier backup",
+	Long: fmt.Sprintf(`Roll a sessions-index.json or MEMORY.md back to one of its backups.
+
+Before clsm changes an index file, by deleting, importing, copying,
+syncing or redacting, it copies the file to backups/index/ in its config
+directory. The newest %d backups of each index are kept.
+
+With no arguments, lists the index files that have backups. With a
+project path or part of an index file's path, lists that index's backups
+and asks which one to restore. The index being replaced is backed up as
+well, so a restore can be undone the same way.`, backup.Keep),
+	Example: `  clsm restore-index
+  clsm restore-index ~/Dev/myapp
+  clsm restore-index myapp/memory/MEMORY.md --backup 2`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		indexes, err := backup.List()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return printIndexes(indexes)
+		}
+
+		idx, err := findIndex(indexes, args[0])
+		if err != nil {
+			return err
+		}
+		printBackups(idx)
+		if restoreBackup > len(idx.Backups) {
+			return fmt.Errorf("--backup %d is out of range 1-%d", restoreBackup, len(idx.Backups))
+		}
+		n := restoreBackup
+		switch {
+		case n > 0:
+		case restoreYes:
+			n = 1
+		default:
+			if n = promptBackup(len(idx.Backups)); n == 0 {
+				fmt.Println("Nothing restored.")
+				return nil
+			}
+		}
+
+		b := idx.Backups[n-1]
+		if err := backup.Restore(claude.RootOf(idx.Path), idx.Path, b); err != nil {
+			return err
+		}
+		fmt.Printf("Restored %s from the backup of %s.\n", idx.Path, b.Time.Format("2006-01-02 15:04:05"))
+		return nil
+	},
+}
+
+func init() {
+	restoreIndexCmd.Flags().IntVar(&restoreBackup, "backup", 0, "restore this backup, numbered from 1 for the newest, without asking")
+	restoreIndexCmd.Flags().BoolVarP(&restoreYes, "yes", "y", false, "restore the newest backup without asking")
+}
+
+// findIndex returns the one index whose path contains term, or the
+// encoded directory name of a project path.
+func findIndex(indexes []backup.Index, term string) (backup.Index, error) {
+	terms := []string{term}
+	if strings.ContainsRune(term, filepath.Separator) {
+		if abs, err := filepath.Abs(claude.ExpandHome(term)); err == nil {
+			terms = append(terms, abs, "/"+session.EncodeDirName(abs)+"/")
+		}
+	}
+	var matches []backup.Index
+	for _, idx := range indexes {
+		for _, t := range terms {
+			if strings.Contains(idx.Path, t) {
+				matches = append(matches, idx)
+				break
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return backup.Index{}, fmt.Errorf("no backed-up index file matches %q; run clsm restore-index to list them", term)
+	case 1:
+		return matches[0], nil
+	}
+	paths := make([]string, len(matches))
+	for i, m := range matches {
+		paths[i] = m.Path
+	}
+	return backup.Index{}, fmt.Errorf("%q matches %d index files:\n  %s", term, len(matches), strings.Join(paths, "\n  "))
+}
+
+func printIndexes(indexes []backup.Index) error {
+	if len(indexes) == 0 {
+		fmt.Println("No index backups yet.")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INDEX\tBACKUPS\tNEWEST")
+	for _, idx := range indexes {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", idx.Path, len(idx.Backups), idx.Backups[0].Time.Format("2006-01-02 15:04:05"))
+	}
+	return w.Flush()
+}
+
+func printBackups(idx backup.Index) {
+	fmt.Printf("Backups of %s", idx.Path)
+	if data, err := claude.RootOf(idx.Path).ReadFile(idx.Path); err == nil {
+		fmt.Printf(" (now %s)", describeIndex(idx.Path, data))
+	} else {
+		fmt.Print(" (now missing)")
+	}
+	fmt.Print(":\n\n")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tSAVED\tCONTENTS")
+	for i, b := range idx.Backups {
+		contents := "unreadable"
+		if data, err := os.ReadFile(b.Path); err == nil {
+			contents = describeIndex(idx.Path, data)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", i+1, b.Time.Format("2006-01-02 15:04:05"), contents)
+	}
+	w.Flush()
+	fmt.Println()
+}
+
+// describeIndex summarizes an index file's contents: how many sessions or
+// memories it lists.
+func describeIndex(path string, data []byte) string {
+	size := formatBytes(int64(len(data)))
+	if filepath.Base(path) == "MEMORY.md" {
+		var n int
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "- ") {
+				n++
+			}
+		}
+		return fmt.Sprintf("%d memory(s), %s", n, size)
+	}
+	var idx struct {
+		Entries []json.RawMessage `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return "not valid JSON, " + size
+	}
+	return fmt.Sprintf("%d session(s), %s", len(idx.Entries), size)
+}
+
+// promptBackup asks which of n backups to restore and returns its number,
+// or 0 for none.
+func promptBackup(n int) int {
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Printf("Restore which backup? [1-%d] (default none): ", n)
+		answer, err := reader.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+		if answer == "" || answer == "none" || answer == "n" {
+			if err != nil {
+				fmt.Println()
+			}
+			return 0
+		}
+		if i, perr := strconv.Atoi(answer); perr == nil && i >= 1 && i <= n {
+			return i
+		}
+		fmt.Printf("selection %q is out of range 1-%d\n", answer, n)
+		if err != nil {
+			return 0
+		}
+	}
+}
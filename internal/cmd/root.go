@@ -2,14 +2,27 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/spf13/cobra"
 
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/dupes"
+	"github.com/baz-sh/clsm/internal/secrets"
 	"github.com/baz-sh/clsm/internal/tui/browse"
+	dupestui "github.com/baz-sh/clsm/internal/tui/dupes"
 	"github.com/baz-sh/clsm/internal/tui/home"
 	"github.com/baz-sh/clsm/internal/tui/memorybrowse"
 	"github.com/baz-sh/clsm/internal/tui/planbrowse"
+	secretstui "github.com/baz-sh/clsm/internal/tui/secrets"
+	timelinetui "github.com/baz-sh/clsm/internal/tui/timeline"
+)
+
+var (
+	claudeDir string   // --claude-dir
+	profiles  []string // --profile
 )
 
 // rootCmd is the base command for clsm.
@@ -17,14 +30,26 @@ var rootCmd = &cobra.Command{
 	Use:   "clsm",
 	Short: "Claude Session Manager",
 	Long:  "A CLI/TUI tool for managing Claude Code sessions.",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		audit.SetCommand(cmd.CommandPath())
+		return useRoots()
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runHome()
+		order, err := browseSortState()
+		if err != nil {
+			return err
+		}
+		return runHome(order)
 	},
 }
 
 // Execute runs the root command.
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if aerr := audit.Err(); aerr != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", aerr)
+	}
+	return err
 }
 
 func init() {
@@ -32,9 +57,77 @@ func init() {
 	rootCmd.AddCommand(browseCmd)
 	rootCmd.AddCommand(memoriesCmd)
 	rootCmd.AddCommand(plansCmd)
+	rootCmd.AddCommand(lsCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(renameCmd)
+	rootCmd.AddCommand(autotitleCmd)
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(noteCmd)
+	rootCmd.AddCommand(timelineCmd)
+	rootCmd.AddCommand(tailCmd)
+	rootCmd.AddCommand(dupesCmd)
+	rootCmd.AddCommand(secretsCmd)
+	rootCmd.AddCommand(redactCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(profilesCmd)
+	rootCmd.AddCommand(copyCmd)
+	rootCmd.AddCommand(moveCmd)
+	rootCmd.AddCommand(restoreIndexCmd)
+	rootCmd.AddCommand(logCmd)
+	rootCmd.AddCommand(sweepCmd)
+
+	rootCmd.PersistentFlags().StringVar(&claudeDir, "claude-dir", "", "Claude Code data directory to use instead of $CLAUDE_CONFIG_DIR or ~/.claude")
+	rootCmd.PersistentFlags().StringSliceVar(&profiles, "profile", nil, "only use these registered profiles (comma-separated or repeated)")
+	addSortFlags(rootCmd)
+}
+
+// useRoots points the stores at the data directories to use: the
+// --claude-dir directory, else the registered profiles selected with
+// --profile, else the default data directory.
+func useRoots() error {
+	if claudeDir != "" {
+		if len(profiles) > 0 {
+			return fmt.Errorf("--claude-dir and --profile can't be used together")
+		}
+		dir := claude.ExpandHome(claudeDir)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return fmt.Errorf("--claude-dir %s is not a directory", claudeDir)
+		}
+		claude.Use(claude.New(dir))
+		return nil
+	}
+
+	registered, err := claude.LoadProfiles()
+	if err != nil {
+		return err
+	}
+	if len(registered) == 0 {
+		if len(profiles) > 0 {
+			return fmt.Errorf("no profiles are registered; add one with clsm profiles add")
+		}
+		return nil
+	}
+	roots, err := claude.Resolve(registered)
+	if err != nil {
+		return err
+	}
+	if len(profiles) > 0 {
+		if roots, err = claude.Select(roots, profiles); err != nil {
+			return err
+		}
+	}
+	if len(roots) == 0 {
+		return fmt.Errorf("none of the registered profiles' directories exist; see clsm profiles")
+	}
+	claude.UseAll(roots)
+	return nil
 }
 
-func runHome() error {
+// runHome shows the home menu until the user quits. The browse sort order
+// is carried from one browse run to the next.
+func runHome(order browse.SortState) error {
 	for {
 		m := home.New()
 		p := tea.NewProgram(m)
@@ -46,15 +139,15 @@ func runHome() error {
 		choice := result.(home.Model).Result
 		switch choice {
 		case home.ChoiceProjects:
-			if !runAndCheckBack(browse.New(browse.ModeProjects)) {
+			if !runBrowse(browse.ModeProjects, &order) {
 				return nil
 			}
 		case home.ChoiceSessions:
-			if !runAndCheckBack(browse.New(browse.ModeSessions)) {
+			if !runBrowse(browse.ModeSessions, &order) {
 				return nil
 			}
 		case home.ChoiceSearch:
-			if !runAndCheckBack(browse.New(browse.ModeSearch)) {
+			if !runBrowse(browse.ModeSearch, &order) {
 				return nil
 			}
 		case home.ChoiceMemories:
@@ -65,8 +158,21 @@ func runHome() error {
 			if !runAndCheckBack(planbrowse.New()) {
 				return nil
 			}
+		case home.ChoiceTimeline:
+			if !runAndCheckBack(timelinetui.New("")) {
+				return nil
+			}
+		case home.ChoiceDupes:
+			if !runAndCheckBack(dupestui.New("", dupes.DefaultThreshold)) {
+				return nil
+			}
+		case home.ChoiceSecrets:
+			opts := secrets.Options{Sessions: true, Memories: true, Plans: true}
+			if !runAndCheckBack(secretstui.New(opts)) {
+				return nil
+			}
 		case home.ChoicePrune:
-			if !runAndCheckBack(browse.New(browse.ModePrune)) {
+			if !runBrowse(browse.ModePrune, &order) {
 				return nil
 			}
 		case home.ChoiceNone:
@@ -96,3 +202,19 @@ func runAndCheckBack(model backToHomer) bool {
 	}
 	return false
 }
+
+// runBrowse runs the browse TUI starting with *order and stores the sort
+// order it ended with back into *order.
+func runBrowse(mode browse.StartMode, order *browse.SortState) bool {
+	p := tea.NewProgram(browse.New(mode).WithSort(*order))
+	result, err := p.Run()
+	if err != nil {
+		return false
+	}
+	m, ok := result.(browse.Model)
+	if !ok {
+		return false
+	}
+	*order = m.Sort()
+	return m.WantsBackToHome()
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/secrets"
+	secretstui "github.com/baz-sh/clsm/internal/tui/secrets"
+)
+
+var (
+	secretsProject     string
+	secretsIn          []string
+	secretsJSON        bool
+	secretsInteractive bool
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Find tokens and keys stored in Claude Code's files",
+}
+
+var secretsScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Scan transcripts, memories and plans for secrets",
+	Long: `Look for tokens, private keys and passwords in session transcripts
+(including subagents), memory files and plans.
+
+The ruleset runs locally and covers known token formats (AWS, GitHub,
+GitLab, Slack, Stripe, Google, npm, Anthropic and OpenAI keys, JWTs,
+private keys and passwords in URLs) plus entropy checks for values
+assigned to secret-looking names and for env file lines. Values that look
+like placeholders, such as "your-token-here", are skipped.
+
+Each hit is reported with its file and line, the session it belongs to
+and a masked value. Values are never printed in full; the fingerprint in
+the JSON output is the same wherever the same secret appears.`,
+	Example: `  clsm secrets scan
+  clsm secrets scan -p myapp --in sessions
+  clsm secrets scan --json | jq -r '.[].path' | sort -u
+  clsm secrets scan -i`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := secrets.Options{Project: secretsProject}
+		var err error
+		opts.Sessions, opts.Memories, opts.Plans, err = parseSources(secretsIn)
+		if err != nil {
+			return err
+		}
+
+		if secretsInteractive {
+			_, err = tea.NewProgram(secretstui.New(opts)).Run()
+			return err
+		}
+
+		findings, err := secrets.Scan(opts)
+		if err != nil {
+			return err
+		}
+		if secretsJSON {
+			if findings == nil {
+				findings = []secrets.Finding{}
+			}
+			return writeJSON(findings)
+		}
+		if len(findings) == 0 {
+			fmt.Println("No secrets found.")
+			return nil
+		}
+		printFindings(os.Stdout, findings)
+		return nil
+	},
+}
+
+func init() {
+	flags := secretsScanCmd.Flags()
+	flags.StringVarP(&secretsProject, "project", "p", "", "only scan projects whose path contains this term")
+	flags.StringSliceVar(&secretsIn, "in", []string{"sessions", "memories", "plans"}, "what to scan: sessions, memories and/or plans")
+	flags.BoolVar(&secretsJSON, "json", false, "print findings as JSON")
+	flags.BoolVarP(&secretsInteractive, "interactive", "i", false, "browse the findings in the TUI")
+
+	secretsCmd.AddCommand(secretsScanCmd)
+}
+
+// parseSources parses the values of an --in flag.
+func parseSources(in []string) (sessions, memories, plans bool, err error) {
+	for _, src := range in {
+		switch src {
+		case "sessions":
+			sessions = true
+		case "memories":
+			memories = true
+		case "plans":
+			plans = true
+		default:
+			return false, false, false, fmt.Errorf("unknown source %q (available: sessions, memories, plans)", src)
+		}
+	}
+	return sessions, memories, plans, nil
+}
+
+// printFindings writes the findings grouped by file: a heading naming the
+// file and its session, then one line per hit.
+func printFindings(out io.Writer, findings []secrets.Finding) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	files := 0
+	for i, f := range findings {
+		if i == 0 || f.Path != findings[i-1].Path {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			files++
+			fmt.Fprintln(w, f.Path)
+			fmt.Fprintf(w, "  %s\n", findingOwner(f))
+		}
+		fmt.Fprintf(w, "  line %d\t%s\t%s\t%s\n", f.Line, f.Description, f.Masked, truncateRunes(f.Snippet, 70))
+	}
+	w.Flush()
+	fmt.Fprintf(out, "\nFound %d secret(s), %d distinct, in %d file(s).\n",
+		len(findings), secrets.Distinct(findings), files)
+}
+
+// findingOwner describes what a finding's file belongs to.
+func findingOwner(f secrets.Finding) string {
+	switch f.Source {
+	case secrets.SourceSession, secrets.SourceSubagent:
+		return fmt.Sprintf("%s %s: %s (%s)", f.Source, shortID(f.SessionID), truncateRunes(f.SessionTitle, 50), f.ProjectPath)
+	case secrets.SourceMemory:
+		return "memory of " + f.ProjectPath
+	}
+	if f.ProjectPath != "" {
+		return "plan for " + f.ProjectPath
+	}
+	return "plan"
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+var (
+	showProject string
+	showJSON    bool
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show <session-id|prefix>",
+	Short: "Show a session's metadata and transcript summary",
+	Long: `Show everything clsm knows about one session: titles, project,
+branch, timestamps, message counts, models, token usage and tool calls.
+Token usage, tool calls and size include the session's subagents, which
+are listed after the transcript summary.
+
+The session may be given by its full ID or any unique prefix.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := session.Find(args[0], showProject)
+		if err != nil {
+			return err
+		}
+		sum, err := session.SummarizeSession(s)
+		if err != nil {
+			return err
+		}
+		if showJSON {
+			return writeJSON(newSessionDetail(s, sum))
+		}
+		printSessionDetail(s, sum)
+		return nil
+	},
+}
+
+func init() {
+	showCmd.Flags().StringVarP(&showProject, "project", "p", "", "only consider sessions in projects whose path contains this term")
+	showCmd.Flags().BoolVar(&showJSON, "json", false, "print as JSON")
+}
+
+// sessionDetail is the JSON form of show's output.
+type sessionDetail struct {
+	ID          string                    `json:"id"`
+	Profile     string                    `json:"profile,omitempty"`
+	Title       string                    `json:"title"`
+	CustomTitle string                    `json:"customTitle"`
+	Summary     string                    `json:"summary"`
+	FirstPrompt string                    `json:"firstPrompt"`
+	Project     string                    `json:"project"`
+	ProjectPath string                    `json:"projectPath"`
+	Branch      string                    `json:"branch"`
+	Tags        []string                  `json:"tags"`
+	Pinned      bool                      `json:"pinned"`
+	Note        string                    `json:"note"`
+	Live        string                    `json:"live,omitempty"`
+	Created     string                    `json:"created"`
+	Modified    string                    `json:"modified"`
+	Messages    int                       `json:"messages"`
+	File        string                    `json:"file"`
+	Transcript  session.TranscriptSummary `json:"transcript"`
+}
+
+func newSessionDetail(s session.Session, sum session.TranscriptSummary) sessionDetail {
+	return sessionDetail{
+		ID:          s.SessionID,
+		Profile:     s.Profile,
+		Title:       s.Title(),
+		CustomTitle: s.CustomTitle,
+		Summary:     s.Summary,
+		FirstPrompt: s.FirstPrompt,
+		Project:     s.Project,
+		ProjectPath: s.ProjectPath,
+		Branch:      s.GitBranch,
+		Tags:        s.Tags,
+		Pinned:      s.Pinned,
+		Note:        s.Note,
+		Live:        s.Live,
+		Created:     s.Created,
+		Modified:    s.Modified,
+		Messages:    s.MsgCount,
+		File:        s.FullPath,
+		Transcript:  sum,
+	}
+}
+
+func printSessionDetail(s session.Session, sum session.TranscriptSummary) {
+	row := func(label, value string) {
+		if value != "" {
+			fmt.Printf("%-13s %s\n", label+":", value)
+		}
+	}
+
+	row("Session", s.SessionID)
+	row("Profile", s.Profile)
+	row("Title", s.Title())
+	row("Custom title", s.CustomTitle)
+	row("Summary", s.Summary)
+	row("Project", s.ProjectPath)
+	row("Branch", s.GitBranch)
+	if s.Protected() {
+		row("Tags", formatTags(s))
+	}
+	row("Created", formatTimestamp(s.Created))
+	row("Modified", formatTimestamp(s.Modified))
+	row("Live", s.Live)
+	size := formatBytes(sum.Size)
+	if len(sum.Subagents) > 0 {
+		size += " with subagents"
+	}
+	row("File", fmt.Sprintf("%s (%s)", s.FullPath, size))
+	row("Version", sum.Version)
+	if prompt := strings.TrimSpace(s.FirstPrompt); prompt != "" {
+		row("First prompt", firstLine(prompt))
+	}
+
+	fmt.Println()
+	fmt.Println("Transcript:")
+	tr := func(label, value string) {
+		if value != "" {
+			fmt.Printf("  %-11s %s\n", label+":", value)
+		}
+	}
+	tr("Entries", fmt.Sprint(sum.Entries))
+	tr("Messages", fmt.Sprintf("%d (%d user, %d assistant)",
+		sum.UserMessages+sum.AssistantMessages, sum.UserMessages, sum.AssistantMessages))
+	if sum.FirstTimestamp != "" {
+		span := formatTimestamp(sum.FirstTimestamp) + " → " + formatTimestamp(sum.LastTimestamp)
+		first, err1 := time.Parse(time.RFC3339Nano, sum.FirstTimestamp)
+		last, err2 := time.Parse(time.RFC3339Nano, sum.LastTimestamp)
+		if err1 == nil && err2 == nil {
+			span += fmt.Sprintf(" (%s)", last.Sub(first).Round(time.Minute))
+		}
+		tr("Active", span)
+	}
+	tr("Models", strings.Join(sum.Models, ", "))
+	if sum.InputTokens+sum.OutputTokens > 0 {
+		tr("Tokens", fmt.Sprintf("%d in, %d out, %d cache read, %d cache write",
+			sum.InputTokens, sum.OutputTokens, sum.CacheReadTokens, sum.CacheCreationTokens))
+	}
+	if len(sum.ToolUses) > 0 {
+		names := make([]string, 0, len(sum.ToolUses))
+		for name := range sum.ToolUses {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if sum.ToolUses[names[i]] != sum.ToolUses[names[j]] {
+				return sum.ToolUses[names[i]] > sum.ToolUses[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		parts := make([]string, len(names))
+		for i, name := range names {
+			parts[i] = fmt.Sprintf("%s×%d", name, sum.ToolUses[name])
+		}
+		tr("Tools", strings.Join(parts, ", "))
+	}
+	for _, summary := range sum.Summaries {
+		tr("Summary", summary)
+	}
+
+	if len(sum.Subagents) > 0 {
+		fmt.Println()
+		fmt.Printf("Subagents (%d):\n", len(sum.Subagents))
+		for _, a := range sum.Subagents {
+			fmt.Printf("  %s  %s\n", a.AgentID, firstLine(a.Description))
+			fmt.Printf("    %d messages, %d tokens in, %d out, %s\n",
+				a.UserMessages+a.AssistantMessages, a.InputTokens, a.OutputTokens, formatBytes(a.Size))
+		}
+	}
+
+	if s.Note != "" {
+		fmt.Println()
+		fmt.Println("Note:")
+		for _, line := range strings.Split(s.Note, "\n") {
+			fmt.Println("  " + line)
+		}
+	}
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// formatTimestamp renders an RFC3339 timestamp in local time, or returns it
+// unchanged if it doesn't parse.
+func formatTimestamp(ts string) string {
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return ts
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%dB", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.0fKB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1024*1024))
+	}
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+var (
+	sweepDryRun bool
+	sweepYes    bool
+)
+
+var sweepCmd = &cobra.Command{
+	Use:   "sweep",
+	Short: "Remove files left behind by sessions that no longer exist",
+	Long: `Find and remove what Claude Code kept for sessions whose transcript is
+gone: todo lists, file history, session-env directories, debug logs,
+tool results and subagent transcripts named by a session ID, and shell
+snapshots no transcript or debug log names.
+
+clsm delete removes these along with a session; sweep cleans up after
+sessions removed some other way. Anything written in the last day, and
+anything of a session Claude Code lists as running, is left alone, as a
+session that has just started may not have a transcript yet.`,
+	Example: `  clsm sweep --dry-run
+  clsm sweep -y`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		orphans, err := session.Orphans()
+		if err != nil {
+			return err
+		}
+		if len(orphans) == 0 {
+			fmt.Println("Nothing to sweep.")
+			return nil
+		}
+
+		var total int64
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "#\tKIND\tSESSION\tSIZE\tWRITTEN\tPATH")
+		for i, a := range orphans {
+			id := shortID(a.SessionID)
+			if id == "" {
+				id = "-"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", i+1, a.Kind, id, formatBytes(a.Size), a.Modified.Format("2006-01-02 15:04"), a.Path)
+			total += a.Size
+		}
+		w.Flush()
+		fmt.Printf("\n%d artifact(s), %s.\n\n", len(orphans), formatBytes(total))
+
+		var chosen []int
+		switch {
+		case sweepDryRun:
+			fmt.Println("Dry run: nothing removed.")
+			return nil
+		case sweepYes:
+			chosen = allIndices(len(orphans))
+		default:
+			if chosen, err = promptSelection(os.Stdout, "Remove", "artifact", len(orphans)); err != nil {
+				return err
+			}
+		}
+		if len(chosen) == 0 {
+			fmt.Println("Aborted.")
+			return nil
+		}
+
+		selected := make([]session.Artifact, len(chosen))
+		for i, idx := range chosen {
+			selected[i] = orphans[idx]
+		}
+		var failed int
+		for i, r := range session.Sweep(selected) {
+			if r.Success {
+				fmt.Printf("  Removed: %s\n", selected[i].Path)
+			} else {
+				fmt.Printf("  Failed:  %s — %s\n", selected[i].Path, r.Error)
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d artifact(s) failed to remove", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	sweepCmd.Flags().BoolVarP(&sweepDryRun, "dry-run", "n", false, "list what would be removed without removing it")
+	sweepCmd.Flags().BoolVarP(&sweepYes, "yes", "y", false, "remove everything found without asking")
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/mirror"
+)
+
+var (
+	syncProject string
+	syncIn      []string
+	syncTagged  bool
+	syncPrefer  string
+	syncDryRun  bool
+	syncForce   bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <dir>",
+	Short: "Keep a mirror directory in sync with ~/.claude both ways",
+	Long: `Keep a mirror directory, such as a NAS mount, a USB drive or a folder
+another tool syncs, consistent with ~/.claude in both directions. The
+mirror gets the same layout, projects/ and plans/, and can be synced from
+any number of machines.
+
+Files are compared by size and modification time, then by content hash,
+with how both sides looked after the last sync. New and changed files are
+copied to the other side, and files deleted on one side are deleted on the
+other, after being copied to backups/ in clsm's config directory. A
+transcript with more messages on one side is copied over the shorter one.
+Session index entries are merged, so each side lists every session it has.
+
+A file changed on both sides is a conflict and is left alone unless
+--prefer names the side to keep. A local transcript Claude Code is writing
+right now is not replaced or deleted unless --force is given. Use
+--dry-run to see what would happen.`,
+	Example: `  clsm sync /mnt/nas/claude -n
+  clsm sync ~/Dropbox/claude --in memories,plans
+  clsm sync /media/usb/claude --tagged --prefer local`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := mirror.Options{Project: syncProject, Tagged: syncTagged, Prefer: syncPrefer, Force: syncForce}
+		var err error
+		opts.Sessions, opts.Memories, opts.Plans, err = parseSources(syncIn)
+		if err != nil {
+			return err
+		}
+		switch syncPrefer {
+		case "", "local", "mirror":
+		default:
+			return fmt.Errorf("invalid --prefer %q (available: local, mirror)", syncPrefer)
+		}
+
+		s, err := mirror.Plan(args[0], opts)
+		if err != nil {
+			return err
+		}
+		if len(s.Changes) == 0 {
+			fmt.Printf("%s is in sync.\n", s.Mirror)
+			return nil
+		}
+		printSync(os.Stdout, s.Changes)
+		conflicts, skipped := len(s.Conflicts()), len(s.Skipped())
+		if syncDryRun {
+			fmt.Printf("\nDry run: %d change(s), %d conflict(s), %d skipped. Nothing was written.\n", len(s.Changes)-conflicts-skipped, conflicts, skipped)
+			return nil
+		}
+
+		backupDir, err := mirror.NewBackupDir()
+		if err != nil {
+			return err
+		}
+		results := s.Apply(backupDir)
+		var ok, failed int
+		for _, r := range results {
+			if r.Success {
+				ok++
+				continue
+			}
+			failed++
+			fmt.Fprintf(os.Stderr, "  %s %s: %s\n", r.Action, r.Rel, r.Error)
+		}
+		fmt.Printf("\nSynced %d change(s) with %s", ok, s.Mirror)
+		if failed > 0 {
+			fmt.Printf(", %d failed", failed)
+		}
+		fmt.Println(".")
+		if conflicts > 0 {
+			fmt.Printf("%d conflict(s) left alone; use --prefer local or --prefer mirror to settle them.\n", conflicts)
+		}
+		if skipped > 0 {
+			fmt.Printf("%d session(s) Claude Code is writing left alone; use --force to sync them anyway.\n", skipped)
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d change(s) failed", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	flags := syncCmd.Flags()
+	flags.StringVarP(&syncProject, "project", "p", "", "only projects whose path contains this term")
+	flags.StringSliceVar(&syncIn, "in", []string{"sessions", "memories", "plans"}, "what to sync: sessions, memories and/or plans")
+	flags.BoolVar(&syncTagged, "tagged", false, "only sync sessions that are tagged or pinned")
+	flags.StringVar(&syncPrefer, "prefer", "", "settle conflicts by keeping this side: local or mirror")
+	flags.BoolVarP(&syncDryRun, "dry-run", "n", false, "show what would change without writing anything")
+	flags.BoolVar(&syncForce, "force", false, "also replace or delete local transcripts Claude Code is writing right now")
+}
+
+func printSync(out io.Writer, changes []mirror.Change) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tKIND\tFILE\tWHY")
+	for _, c := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Action, c.Kind, c.Rel, c.Reason)
+	}
+	w.Flush()
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/meta"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+var (
+	tagProject string
+	tagRemove  bool
+	tagPin     bool
+	tagUnpin   bool
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag [session-id|prefix] [tag...]",
+	Short: "Tag and pin sessions",
+	Long: `Add tags to a session, remove them, or pin it.
+
+Tags and pins are stored in clsm's own metadata file, never in Claude
+Code's files, and are keyed by session ID. Tagged and pinned sessions
+are skipped by prune and by bulk delete.
+
+With no arguments, lists every tag and how many sessions carry it. With
+only a session, prints that session's tags. Use the tag:name filter in
+the TUI or in "clsm ls sessions --filter" to find tagged sessions.`,
+	Example: `  clsm tag 3f2a incident design
+  clsm tag 3f2a --remove design
+  clsm tag 3f2a --remove          # remove all tags
+  clsm tag 3f2a --pin`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			if tagRemove || tagPin || tagUnpin {
+				return errors.New("name a session to change")
+			}
+			return listTags()
+		}
+		if tagPin && tagUnpin {
+			return errors.New("--pin and --unpin are mutually exclusive")
+		}
+
+		s, err := session.Find(args[0], tagProject)
+		if err != nil {
+			return err
+		}
+		tags := meta.ParseTags(args[1:]...)
+		if len(tags) == 0 && !tagRemove && !tagPin && !tagUnpin {
+			fmt.Printf("%s  %s\n", s.SessionID, formatTags(s))
+			return nil
+		}
+
+		var updated meta.SessionMeta
+		err = meta.Update(func(st *meta.Store) error {
+			switch {
+			case tagRemove:
+				st.Untag(s.SessionID, tags...)
+			case len(tags) > 0:
+				st.Tag(s.SessionID, tags...)
+			}
+			if tagPin || tagUnpin {
+				st.SetPinned(s.SessionID, tagPin)
+			}
+			updated = st.Get(s.SessionID)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		s.Tags, s.Pinned = updated.Tags, updated.Pinned
+		fmt.Printf("%s  %s\n", s.SessionID, formatTags(s))
+		return nil
+	},
+}
+
+func init() {
+	tagCmd.Flags().StringVarP(&tagProject, "project", "p", "", "only consider sessions in projects whose path contains this term")
+	tagCmd.Flags().BoolVarP(&tagRemove, "remove", "d", false, "remove the given tags (all tags if none are given)")
+	tagCmd.Flags().BoolVar(&tagPin, "pin", false, "pin the session to the top of every list")
+	tagCmd.Flags().BoolVar(&tagUnpin, "unpin", false, "unpin the session")
+}
+
+func listTags() error {
+	st, err := meta.Load()
+	if err != nil {
+		return err
+	}
+	counts := st.TagCounts()
+	if len(counts) == 0 {
+		fmt.Fprintln(os.Stderr, "No tags yet. Add one with: clsm tag <session-id> <tag>")
+		return nil
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%s\t%d\n", name, counts[name])
+	}
+	return nil
+}
+
+// formatTags renders a session's tags and pin for humans.
+func formatTags(s session.Session) string {
+	parts := make([]string, 0, len(s.Tags)+1)
+	if s.Pinned {
+		parts = append(parts, "pinned")
+	}
+	for _, t := range s.Tags {
+		parts = append(parts, "#"+t)
+	}
+	if len(parts) == 0 {
+		return "(no tags)"
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+var (
+	tailLines    int
+	tailProject  string
+	tailNoFollow bool
+)
+
+var tailCmd = &cobra.Command{
+	Use:   "tail [session-id|prefix]",
+	Short: "Follow a session's transcript as Claude writes it",
+	Long: `Print the last entries of a session's transcript and then keep printing
+new ones as they are written, like tail -f. Messages are shown with their
+time and speaker, and tool calls with their main argument.
+
+Without a session, the most recently modified session is followed (in
+the --project, if given). Press Ctrl+C to stop.`,
+	Example: `  clsm tail
+  clsm tail 3f2a -n 50
+  clsm tail -p myapp --no-follow`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var s session.Session
+		var err error
+		if len(args) == 1 {
+			s, err = session.Find(args[0], tailProject)
+		} else {
+			s, err = latestSession(tailProject)
+		}
+		if err != nil {
+			return err
+		}
+
+		t, entries, err := session.NewTailer(s.FullPath, tailLines)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("==> %s (%s) <==\n", s.Title(), s.SessionID)
+		printEntries(entries)
+		if tailNoFollow {
+			return nil
+		}
+
+		for range time.Tick(500 * time.Millisecond) {
+			entries, err := t.Poll()
+			if err != nil {
+				return err
+			}
+			printEntries(entries)
+		}
+		return nil
+	},
+}
+
+func init() {
+	tailCmd.Flags().IntVarP(&tailLines, "lines", "n", 20, "number of earlier entries to print (-1 for all)")
+	tailCmd.Flags().StringVarP(&tailProject, "project", "p", "", "only consider sessions in projects whose path contains this term")
+	tailCmd.Flags().BoolVar(&tailNoFollow, "no-follow", false, "print the last entries and exit")
+}
+
+// latestSession returns the most recently modified session in projects
+// matching the project term.
+func latestSession(project string) (session.Session, error) {
+	sessions, err := session.ListAllSessions()
+	if err != nil {
+		return session.Session{}, err
+	}
+	for _, s := range sessions { // newest first
+		if matchesProject(s.Project, s.ProjectPath, project) {
+			return s, nil
+		}
+	}
+	return session.Session{}, fmt.Errorf("no sessions found")
+}
+
+// printEntries writes transcript entries as "time speaker text" lines,
+// with continuation lines and tool calls indented under the text.
+func printEntries(entries []session.Entry) {
+	const indent = "              "
+	for _, e := range entries {
+		ts := "     "
+		if !e.Time.IsZero() {
+			ts = e.Time.Local().Format("15:04")
+		}
+		label := e.Type
+		switch e.Type {
+		case "user":
+			label = "you"
+		case "assistant":
+			label = "claude"
+		}
+		var lines []string
+		if e.Results > 0 && e.Text == "" {
+			lines = append(lines, fmt.Sprintf("← %d tool result(s)", e.Results))
+		}
+		for _, line := range strings.Split(e.Text, "\n") {
+			if line = strings.TrimRight(line, " "); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		for _, t := range e.Tools {
+			lines = append(lines, "→ "+t)
+		}
+		for i, line := range lines {
+			if i == 0 {
+				fmt.Printf("%s %-7s %s\n", ts, label, line)
+			} else {
+				fmt.Println(indent + line)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/baz-sh/clsm/internal/session"
+	"github.com/baz-sh/clsm/internal/timeline"
+	timelinetui "github.com/baz-sh/clsm/internal/tui/timeline"
+)
+
+var (
+	timelineSince       string
+	timelineProject     string
+	timelineHeatmap     bool
+	timelineJSON        bool
+	timelineInteractive bool
+)
+
+var timelineCmd = &cobra.Command{
+	Use:   "timeline",
+	Short: "Show which sessions were active on each day",
+	Long: `List the sessions active on each day, newest day first.
+
+Days come from the timestamps of the messages in each transcript rather
+than from file times, so a session that ran over several days is listed
+on each of them with that day's message count and time span.
+
+Use --heatmap for a calendar of activity, or -i to browse the heatmap
+and drill down into days in the TUI.`,
+	Example: `  clsm timeline --since 7d
+  clsm timeline --since yesterday -p myapp
+  clsm timeline --since 12w --heatmap
+  clsm timeline -i`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if timelineInteractive {
+			p := tea.NewProgram(timelinetui.New(timelineProject))
+			_, err := p.Run()
+			return err
+		}
+
+		now := time.Now()
+		since, err := timeline.ParseSince(timelineSince, now)
+		if err != nil {
+			return err
+		}
+		sessions, err := session.ListAllSessions()
+		if err != nil {
+			return err
+		}
+		sessions = filterItems(sessions, func(s session.Session) bool {
+			return matchesProject(s.Project, s.ProjectPath, timelineProject)
+		})
+		days := timeline.Build(sessions, since)
+
+		if timelineJSON {
+			return writeJSON(newTimelineDays(days))
+		}
+		if timelineHeatmap {
+			weeks := int(now.Sub(since).Hours()/24/7) + 1
+			fmt.Println(timeline.NewGrid(days, now, weeks))
+			fmt.Println()
+		}
+		if len(days) == 0 {
+			fmt.Println("No activity.")
+			return nil
+		}
+		printTimeline(days)
+		return nil
+	},
+}
+
+func init() {
+	timelineCmd.Flags().StringVar(&timelineSince, "since", "7d", "start of the period: days or weeks (7d, 2w), a duration (36h), today, yesterday or a date (2006-01-02)")
+	timelineCmd.Flags().StringVarP(&timelineProject, "project", "p", "", "only include sessions in projects whose path contains this term")
+	timelineCmd.Flags().BoolVar(&timelineHeatmap, "heatmap", false, "print a calendar heatmap of the period before the list")
+	timelineCmd.Flags().BoolVar(&timelineJSON, "json", false, "print as JSON")
+	timelineCmd.Flags().BoolVarP(&timelineInteractive, "interactive", "i", false, "browse the heatmap in the TUI")
+}
+
+// printTimeline writes one block per day, newest first.
+func printTimeline(days []timeline.Day) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i := len(days) - 1; i >= 0; i-- {
+		d := days[i]
+		if i != len(days)-1 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s\t%d session(s), %d messages\n", d.Date.Format("Mon 2006-01-02"), len(d.Sessions), d.Messages)
+		for _, a := range d.Sessions {
+			fmt.Fprintf(w, "  %s–%s\t%s\t%s\t%d msgs\n",
+				a.First.Format("15:04"), a.Last.Format("15:04"),
+				truncateRunes(a.Session.Title(), 60), a.Session.ProjectPath, a.Messages)
+		}
+	}
+	w.Flush()
+}
+
+// truncateRunes shortens s to at most n runes, ending with "…" if cut.
+func truncateRunes(s string, n int) string {
+	s = firstLine(s)
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// timelineDay is the JSON form of a timeline day.
+type timelineDay struct {
+	Date     string             `json:"date"`
+	Messages int                `json:"messages"`
+	Sessions []timelineActivity `json:"sessions"`
+}
+
+type timelineActivity struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	ProjectPath string `json:"projectPath"`
+	Messages    int    `json:"messages"`
+	First       string `json:"first"`
+	Last        string `json:"last"`
+}
+
+func newTimelineDays(days []timeline.Day) []timelineDay {
+	out := make([]timelineDay, 0, len(days))
+	for _, d := range days {
+		td := timelineDay{Date: d.Key(), Messages: d.Messages}
+		for _, a := range d.Sessions {
+			td.Sessions = append(td.Sessions, timelineActivity{
+				ID:          a.Session.SessionID,
+				Title:       a.Session.Title(),
+				ProjectPath: a.Session.ProjectPath,
+				Messages:    a.Messages,
+				First:       a.First.Format(time.RFC3339),
+				Last:        a.Last.Format(time.RFC3339),
+			})
+		}
+		out = append(out, td)
+	}
+	return out
+}
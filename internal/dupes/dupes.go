@@ -0,0 +1,267 @@
+// Package dupes finds sessions that repeat each other: retries, restarts
+// after a crash and sessions continued after /clear. Sessions of the same
+// project are grouped when their first prompts are the same after
+// normalization, or when their transcripts are similar by MinHash over
+// word shingles. Everything is computed locally from the JSONL files.
+package dupes
+
+import (
+	"bufio"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+// DefaultThreshold is the estimated Jaccard similarity above which two
+// transcripts count as near-duplicates.
+const DefaultThreshold = 0.8
+
+// Reasons a group was formed.
+const (
+	ReasonPrompt  = "same first prompt"
+	ReasonSimilar = "similar transcript"
+)
+
+// Group is a set of sessions that duplicate each other.
+type Group struct {
+	Sessions []session.Session // newest first
+	Reasons  []string          // ReasonPrompt and/or ReasonSimilar
+	Longest  int               // index in Sessions of the session with the most messages
+	Newest   int               // index in Sessions of the most recently modified session
+}
+
+// Keep returns the index of the session suggested for keeping: the longest,
+// since it usually holds the work the retries were aiming for.
+func (g Group) Keep() int {
+	return g.Longest
+}
+
+// Find groups duplicate sessions. Only sessions of the same project in the
+// same profile are grouped, as a generic opening prompt or boilerplate
+// repeated in another project doesn't make one session a retry of the
+// other. Sessions without messages are left out; prune deals with those.
+// Groups are ordered by the newest session in each.
+func Find(sessions []session.Session, threshold float64) []Group {
+	var candidates []session.Session
+	for _, s := range sessions {
+		if s.MsgCount > 0 {
+			candidates = append(candidates, s)
+		}
+	}
+
+	uf := newUnionFind(len(candidates))
+	reasons := make(map[[2]int]string) // union-find edge -> reason
+
+	// Same normalized first prompt.
+	byPrompt := make(map[string]int) // project key and prompt -> first session
+	for i, s := range candidates {
+		p := NormalizePrompt(s.FirstPrompt)
+		if len(p) < minPromptLen {
+			continue
+		}
+		p = projectKey(s) + "\x00" + p
+		if j, ok := byPrompt[p]; ok {
+			uf.union(j, i)
+			reasons[[2]int{j, i}] = ReasonPrompt
+		} else {
+			byPrompt[p] = i
+		}
+	}
+
+	// Similar transcripts: MinHash signatures, candidate pairs from LSH
+	// bands, then the estimated similarity checked against the threshold.
+	sigs := make([]signature, len(candidates))
+	for i, s := range candidates {
+		sigs[i] = minhash(shingles(transcriptText(claude.For(s.Profile), s.FullPath)))
+	}
+	buckets := make(map[bandKey][]int)
+	for i, sig := range sigs {
+		if sig.empty() {
+			continue
+		}
+		for b := 0; b < bands; b++ {
+			k := sig.band(b)
+			buckets[k] = append(buckets[k], i)
+		}
+	}
+	checked := make(map[[2]int]bool)
+	for _, members := range buckets {
+		for x := 0; x < len(members); x++ {
+			for y := x + 1; y < len(members); y++ {
+				pair := [2]int{members[x], members[y]}
+				if checked[pair] {
+					continue
+				}
+				checked[pair] = true
+				if projectKey(candidates[pair[0]]) != projectKey(candidates[pair[1]]) {
+					continue
+				}
+				if sigs[pair[0]].similarity(sigs[pair[1]]) >= threshold {
+					uf.union(pair[0], pair[1])
+					if _, ok := reasons[pair]; !ok {
+						reasons[pair] = ReasonSimilar
+					}
+				}
+			}
+		}
+	}
+
+	// Collect groups of two or more.
+	members := make(map[int][]int)
+	for i := range candidates {
+		r := uf.find(i)
+		members[r] = append(members[r], i)
+	}
+	groupReasons := make(map[int]map[string]bool)
+	for pair, reason := range reasons {
+		r := uf.find(pair[0])
+		if groupReasons[r] == nil {
+			groupReasons[r] = make(map[string]bool)
+		}
+		groupReasons[r][reason] = true
+	}
+
+	var groups []Group
+	for root, idx := range members {
+		if len(idx) < 2 {
+			continue
+		}
+		var sessions []session.Session
+		for _, i := range idx {
+			sessions = append(sessions, candidates[i])
+		}
+		var rs []string
+		for _, reason := range []string{ReasonPrompt, ReasonSimilar} {
+			if groupReasons[root][reason] {
+				rs = append(rs, reason)
+			}
+		}
+		g := newGroup(sessions, rs)
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(a, b int) bool {
+		return groups[a].Sessions[0].Modified > groups[b].Sessions[0].Modified
+	})
+	return groups
+}
+
+// projectKey identifies the project a session belongs to, in its profile.
+func projectKey(s session.Session) string {
+	return s.Profile + "\x00" + s.ProjectPath
+}
+
+// newGroup orders the sessions newest first and picks out the longest.
+func newGroup(sessions []session.Session, reasons []string) Group {
+	g := Group{Sessions: sessions, Reasons: reasons}
+	sort.Slice(g.Sessions, func(a, b int) bool { return g.Sessions[a].Modified > g.Sessions[b].Modified })
+	g.Newest = 0
+	for i, s := range g.Sessions {
+		l := g.Sessions[g.Longest]
+		if s.MsgCount > l.MsgCount || (s.MsgCount == l.MsgCount && s.Size > l.Size) {
+			g.Longest = i
+		}
+	}
+	return g
+}
+
+// Remove drops the sessions with the given keys, as Session.Key gives
+// them, from the groups, for example after they were deleted, and drops
+// groups left with fewer than two sessions.
+func Remove(groups []Group, keys map[string]bool) []Group {
+	var out []Group
+	for _, g := range groups {
+		var sessions []session.Session
+		for _, s := range g.Sessions {
+			if !keys[s.Key()] {
+				sessions = append(sessions, s)
+			}
+		}
+		if len(sessions) >= 2 {
+			out = append(out, newGroup(sessions, g.Reasons))
+		}
+	}
+	return out
+}
+
+// minPromptLen is the shortest normalized prompt that groups sessions;
+// shorter ones ("hi", "continue") say nothing about the session.
+const minPromptLen = 12
+
+// NormalizePrompt reduces a prompt to the words that identify it: lower
+// case, punctuation dropped and whitespace collapsed.
+func NormalizePrompt(p string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(p) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// maxTextBytes caps how much of a transcript is read for shingling. The
+// start of a session is what retries repeat.
+const maxTextBytes = 256 * 1024
+
+// transcriptText returns the message text of a transcript, normalized like
+// prompts, up to maxTextBytes.
+func transcriptText(root *claude.Root, path string) string {
+	f, err := root.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() && b.Len() < maxTextBytes {
+		e, ok := session.ParseEntry(scanner.Bytes())
+		if !ok || (e.Type != "user" && e.Type != "assistant") {
+			continue
+		}
+		b.WriteString(NormalizePrompt(e.Text))
+		b.WriteByte(' ')
+		for _, t := range e.Tools {
+			b.WriteString(NormalizePrompt(t))
+			b.WriteByte(' ')
+		}
+	}
+	return b.String()
+}
+
+// unionFind is a disjoint-set forest over indices.
+type unionFind []int
+
+func newUnionFind(n int) unionFind {
+	uf := make(unionFind, n)
+	for i := range uf {
+		uf[i] = i
+	}
+	return uf
+}
+
+func (uf unionFind) find(i int) int {
+	for uf[i] != i {
+		uf[i] = uf[uf[i]]
+		i = uf[i]
+	}
+	return i
+}
+
+func (uf unionFind) union(a, b int) {
+	if ra, rb := uf.find(a), uf.find(b); ra != rb {
+		uf[rb] = ra
+	}
+}
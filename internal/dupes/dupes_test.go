@@ -0,0 +1,170 @@
+package dupes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+func TestNormalizePrompt(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"Fix the login bug", "fix the login bug"},
+		{"  Fix   the\tlogin\nbug!! ", "fix the login bug"},
+		{"fix: the (login) bug?", "fix the login bug"},
+		{"don't break v2.1", "don t break v2 1"},
+		{"Ünïcödé wörds", "ünïcödé wörds"},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizePrompt(tt.in); got != tt.want {
+			t.Errorf("NormalizePrompt(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	claude.Use(claude.New(dir))
+	t.Cleanup(func() { claude.UseAll(nil) })
+
+	// Two long transcripts that differ in one word, and an unrelated one.
+	var words []string
+	for i := range 80 {
+		words = append(words, fmt.Sprintf("word%d", i))
+	}
+	similar := strings.Join(words, " ")
+	words[40] = "changed"
+	similar2 := strings.Join(words, " ")
+	other := strings.Repeat("something else entirely ", 20)
+
+	type sess struct {
+		id, profile, project, prompt, text string
+		msgs                               int
+	}
+	const prompt = "Fix the failing login tests"
+	tests := []struct {
+		name     string
+		sessions []sess
+		want     [][]string // groups of IDs, each sorted
+	}{
+		{
+			name: "same prompt in one project",
+			sessions: []sess{
+				{"a", "", "/p1", prompt, "", 3},
+				{"b", "", "/p1", "fix the FAILING login tests!", "", 5},
+				{"c", "", "/p1", "something different here", "", 2},
+			},
+			want: [][]string{{"a", "b"}},
+		},
+		{
+			name: "same prompt in other projects",
+			sessions: []sess{
+				{"a", "", "/p1", prompt, "", 3},
+				{"b", "", "/p2", prompt, "", 3},
+			},
+		},
+		{
+			name: "same prompt in another profile",
+			sessions: []sess{
+				{"a", "work", "/p1", prompt, "", 3},
+				{"b", "home", "/p1", prompt, "", 3},
+			},
+		},
+		{
+			name: "short prompt",
+			sessions: []sess{
+				{"a", "", "/p1", "continue", "", 3},
+				{"b", "", "/p1", "continue", "", 3},
+			},
+		},
+		{
+			name: "no messages",
+			sessions: []sess{
+				{"a", "", "/p1", prompt, "", 3},
+				{"b", "", "/p1", prompt, "", 0},
+			},
+		},
+		{
+			name: "similar transcripts in one project",
+			sessions: []sess{
+				{"a", "", "/p1", "first try at it", similar, 3},
+				{"b", "", "/p1", "second try at it", similar2, 3},
+				{"c", "", "/p1", "third thing", other, 3},
+			},
+			want: [][]string{{"a", "b"}},
+		},
+		{
+			name: "similar transcripts in other projects",
+			sessions: []sess{
+				{"a", "", "/p1", "first try at it", similar, 3},
+				{"b", "", "/p2", "second try at it", similar2, 3},
+			},
+		},
+		{
+			name: "no chaining across projects",
+			sessions: []sess{
+				{"a", "", "/p1", prompt, other, 3},
+				{"b", "", "/p1", prompt, similar, 3},
+				{"c", "", "/p2", "unrelated opener", similar2, 3},
+				{"d", "", "/p2", prompt, other, 3},
+			},
+			want: [][]string{{"a", "b"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sessions []session.Session
+			for _, s := range tt.sessions {
+				path := filepath.Join(dir, s.id+".jsonl")
+				if err := os.WriteFile(path, transcript(s.text), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				sessions = append(sessions, session.Session{
+					SessionID:   s.id,
+					Profile:     s.profile,
+					ProjectPath: s.project,
+					FirstPrompt: s.prompt,
+					MsgCount:    s.msgs,
+					FullPath:    path,
+				})
+			}
+			var got [][]string
+			for _, g := range Find(sessions, DefaultThreshold) {
+				var ids []string
+				for _, s := range g.Sessions {
+					ids = append(ids, s.SessionID)
+				}
+				sort.Strings(ids)
+				got = append(got, ids)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i][0] < got[j][0] })
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("got groups %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// transcript returns a one-message transcript holding text, or an empty
+// one.
+func transcript(text string) []byte {
+	if text == "" {
+		return nil
+	}
+	line, _ := json.Marshal(map[string]any{
+		"type":    "user",
+		"message": map[string]any{"role": "user", "content": text},
+	})
+	return append(line, '\n')
+}
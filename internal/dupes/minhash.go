@@ -0,0 +1,117 @@
+package dupes
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+)
+
+const (
+	shingleSize = 5  // words per shingle
+	numHashes   = 64 // MinHash signature length
+	bands       = 16 // LSH bands; numHashes/bands rows each
+	rows        = numHashes / bands
+)
+
+// signature is a MinHash signature: for each hash function, the smallest
+// hash over a document's shingles.
+type signature [numHashes]uint64
+
+// bandKey identifies one LSH band of a signature.
+type bandKey struct {
+	band int
+	sum  uint64
+}
+
+// shingles returns the hashes of the overlapping word n-grams in text.
+// Texts shorter than one shingle yield a single shingle of all their words.
+func shingles(text string) []uint64 {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+	if len(words) < shingleSize {
+		return []uint64{hashString(strings.Join(words, " "))}
+	}
+	seen := make(map[uint64]bool)
+	var out []uint64
+	for i := 0; i+shingleSize <= len(words); i++ {
+		h := hashString(strings.Join(words[i:i+shingleSize], " "))
+		if !seen[h] {
+			seen[h] = true
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// minhash computes the signature of a set of shingle hashes. The hash
+// functions are the shingle hash mixed with a different seed each.
+func minhash(shingles []uint64) signature {
+	var sig signature
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for _, s := range shingles {
+		for i := range sig {
+			if h := mix(s ^ seeds[i]); h < sig[i] {
+				sig[i] = h
+			}
+		}
+	}
+	return sig
+}
+
+// empty reports whether the signature was computed from no shingles.
+func (s signature) empty() bool {
+	return s[0] == math.MaxUint64
+}
+
+// similarity estimates the Jaccard similarity of the two shingle sets as
+// the fraction of hash functions whose minimums agree.
+func (s signature) similarity(o signature) float64 {
+	same := 0
+	for i := range s {
+		if s[i] == o[i] {
+			same++
+		}
+	}
+	return float64(same) / numHashes
+}
+
+// band returns the key of band b, combining its rows.
+func (s signature) band(b int) bandKey {
+	var sum uint64
+	for _, v := range s[b*rows : (b+1)*rows] {
+		sum = mix(sum ^ v)
+	}
+	return bandKey{b, sum}
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// mix is the splitmix64 finalizer, which spreads every input bit over the
+// output.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// seeds are the per-hash-function seeds, fixed so signatures are stable.
+var seeds = func() [numHashes]uint64 {
+	var s [numHashes]uint64
+	x := uint64(0x9e3779b97f4a7c15)
+	for i := range s {
+		x += 0x9e3779b97f4a7c15
+		s[i] = mix(x)
+	}
+	return s
+}()
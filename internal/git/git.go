@@ -0,0 +1,42 @@
+// Package git runs read-only git queries against project directories.
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// run executes git with the given arguments inside dir and returns its
+// trimmed standard output.
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// LocalBranches returns the set of local branch names in the repository
+// containing dir. It fails if dir doesn't exist or isn't inside a git
+// work tree.
+func LocalBranches(dir string) (map[string]bool, error) {
+	out, err := run(dir, "for-each-ref", "--format=%(refname:short)", "refs/heads/")
+	if err != nil {
+		return nil, err
+	}
+	branches := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			branches[line] = true
+		}
+	}
+	return branches, nil
+}
@@ -0,0 +1,172 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Repo describes the repository a directory belongs to. It is read from
+// the .git files on disk, so it works for paths whose repository git
+// itself can't open, and costs no process per project.
+type Repo struct {
+	Root      string // top of the work tree containing the directory
+	CommonDir string // the .git directory shared by all worktrees
+	Remote    string // URL of the origin remote, or of the first remote
+}
+
+// ErrNotRepo is returned by Identify for directories outside any work tree.
+var ErrNotRepo = errors.New("not in a git repository")
+
+// Key returns an identity shared by every worktree and clone of the
+// repository: the normalized remote URL, or the shared .git directory when
+// there is no remote.
+func (r Repo) Key() string {
+	if r.Remote != "" {
+		return NormalizeRemote(r.Remote)
+	}
+	return r.CommonDir
+}
+
+// Main reports whether the directory is in the repository's main worktree
+// rather than a linked one.
+func (r Repo) Main() bool {
+	return r.CommonDir == filepath.Join(r.Root, ".git")
+}
+
+// Identify finds the repository containing dir by looking for .git upwards
+// from it. A .git directory marks a main worktree; a .git file points to a
+// linked worktree's git dir, whose commondir file leads to the shared one.
+func Identify(dir string) (Repo, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Repo{}, err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return Repo{}, err
+	}
+	for d := dir; ; d = filepath.Dir(d) {
+		dotGit := filepath.Join(d, ".git")
+		info, err := os.Stat(dotGit)
+		if err == nil {
+			r := Repo{Root: d, CommonDir: dotGit}
+			if !info.IsDir() {
+				if r.CommonDir, err = linkedCommonDir(d, dotGit); err != nil {
+					return Repo{}, err
+				}
+			}
+			r.Remote = remoteURL(filepath.Join(r.CommonDir, "config"))
+			return r, nil
+		}
+		if parent := filepath.Dir(d); parent == d {
+			return Repo{}, ErrNotRepo
+		}
+	}
+}
+
+// linkedCommonDir follows the "gitdir:" line of a .git file and the
+// commondir file next to it.
+func linkedCommonDir(root, dotGit string) (string, error) {
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", err
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", errors.New(dotGit + ": no gitdir line")
+	}
+	gitDir = resolve(root, strings.TrimSpace(gitDir))
+	common, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir, nil // a submodule or a separate git dir: not shared
+	}
+	return resolve(gitDir, strings.TrimSpace(string(common))), nil
+}
+
+func resolve(base, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	return filepath.Clean(path)
+}
+
+// remoteURL returns the url of the origin remote in a git config file, or
+// of the first remote if there's no origin.
+func remoteURL(configPath string) string {
+	f, err := os.Open(configPath)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	var section, first, origin string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[]")
+			continue
+		}
+		name, ok := strings.CutPrefix(section, "remote ")
+		if !ok {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(k) != "url" {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		if first == "" {
+			first = v
+		}
+		if strings.Trim(name, `"`) == "origin" && origin == "" {
+			origin = v
+		}
+	}
+	if origin != "" {
+		return origin
+	}
+	return first
+}
+
+// NormalizeRemote reduces the different spellings of a remote URL to one
+// form, lower-case host/path, so https and ssh clones of a repository
+// compare equal: "git@github.com:Me/App.git" and "https://github.com/me/app"
+// both become "github.com/me/app". Hosting services ignore case in paths.
+func NormalizeRemote(url string) string {
+	host, path := splitRemote(url)
+	return strings.ToLower(host + "/" + path)
+}
+
+// RemoteName returns the last two elements of a remote's path, usually
+// owner/repo, as the remote spells them.
+func RemoteName(url string) string {
+	_, path := splitRemote(url)
+	parts := strings.Split(path, "/")
+	if len(parts) > 2 {
+		parts = parts[len(parts)-2:]
+	}
+	return strings.Join(parts, "/")
+}
+
+// splitRemote returns the host and repository path of a remote URL in any
+// of the forms git accepts, without user, port or ".git" suffix.
+func splitRemote(url string) (host, path string) {
+	u := strings.TrimSpace(url)
+	if i := strings.Index(u, "://"); i >= 0 {
+		u = u[i+3:]
+	} else if host, path, ok := strings.Cut(u, ":"); ok && !strings.Contains(host, "/") {
+		u = host + "/" + path // scp-like syntax
+	}
+	if at := strings.Index(u, "@"); at >= 0 && at < strings.Index(u+"/", "/") {
+		u = u[at+1:] // user name
+	}
+	host, path, _ = strings.Cut(u, "/")
+	if h, _, ok := strings.Cut(host, ":"); ok {
+		host = h // port
+	}
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git")
+	return host, path
+}
@@ -7,13 +7,12 @@ import (
 	"sort"
 	"strings"
 	"time"
-)
 
-// ClaudeDir returns the path to the Claude projects directory.
-func ClaudeDir() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".claude", "projects")
-}
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/backup"
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/meta"
+)
 
 // ListProjects returns all projects that contain memory directories
 // with at least one .md file, sorted by most recently modified memory.
@@ -27,18 +26,9 @@ func ListProjectsWithProgress(progress chan<- LoadProgress) ([]MemoryProject, er
 	if progress != nil {
 		defer close(progress)
 	}
-	base := ClaudeDir()
-
-	entries, err := os.ReadDir(base)
+	dirs, err := claude.ProjectDirs()
 	if err != nil {
-		return nil, fmt.Errorf("reading projects dir: %w", err)
-	}
-
-	var dirs []os.DirEntry
-	for _, e := range entries {
-		if e.IsDir() {
-			dirs = append(dirs, e)
-		}
+		return nil, err
 	}
 
 	report := func(current, total int) {
@@ -54,15 +44,15 @@ func ListProjectsWithProgress(progress chan<- LoadProgress) ([]MemoryProject, er
 	var projects []MemoryProject
 	for i, dir := range dirs {
 		report(i+1, len(dirs))
-		dirName := dir.Name()
-		memDir := filepath.Join(base, dirName, "memory")
+		dirName := dir.Name
+		memDir := filepath.Join(dir.Root.ProjectsDir(), dirName, "memory")
 
-		info, err := os.Stat(memDir)
+		info, err := dir.Root.Stat(memDir)
 		if err != nil || !info.IsDir() {
 			continue
 		}
 
-		memFiles, _ := filepath.Glob(filepath.Join(memDir, "*.md"))
+		memFiles, _ := dir.Root.Glob(filepath.Join(memDir, "*.md"))
 
 		var count int
 		var hasIndex bool
@@ -74,7 +64,7 @@ func ListProjectsWithProgress(progress chan<- LoadProgress) ([]MemoryProject, er
 				hasIndex = true
 			}
 			count++
-			if fi, err := os.Stat(f); err == nil {
+			if fi, err := dir.Root.Stat(f); err == nil {
 				if fi.ModTime().After(lastMod) {
 					lastMod = fi.ModTime()
 				}
@@ -91,6 +81,7 @@ func ListProjectsWithProgress(progress chan<- LoadProgress) ([]MemoryProject, er
 		}
 
 		projects = append(projects, MemoryProject{
+			Profile:      dir.Root.Name,
 			DirName:      dirName,
 			Path:         decodeDirName(dirName),
 			MemoryCount:  count,
@@ -107,16 +98,23 @@ func ListProjectsWithProgress(progress chan<- LoadProgress) ([]MemoryProject, er
 }
 
 // ListMemories returns all memory files for a given project directory,
-// sorted by modification time descending. Excludes MEMORY.md.
-func ListMemories(projectDir string) ([]Memory, error) {
-	base := ClaudeDir()
-	memDir := filepath.Join(base, projectDir, "memory")
+// sorted by modification time descending. Excludes MEMORY.md. profile
+// names the data directory the project is in; it is empty without
+// profiles.
+func ListMemories(profile, projectDir string) ([]Memory, error) {
+	r := claude.Lookup(profile)
+	if r == nil {
+		return nil, fmt.Errorf("no profile named %q", profile)
+	}
+	memDir := filepath.Join(r.ProjectsDir(), projectDir, "memory")
 
-	files, err := filepath.Glob(filepath.Join(memDir, "*.md"))
+	files, err := r.Glob(filepath.Join(memDir, "*.md"))
 	if err != nil {
 		return nil, fmt.Errorf("globbing memory files: %w", err)
 	}
 
+	notes, _ := meta.Notes(meta.NoteMemory)
+
 	var memories []Memory
 	for _, f := range files {
 		m, err := ReadMemory(f)
@@ -127,8 +125,10 @@ func ListMemories(projectDir string) ([]Memory, error) {
 			m.Name = "Memory Index"
 			m.Type = "index"
 		}
+		m.Profile = profile
 		m.ProjectDir = projectDir
 		m.ProjectPath = decodeDirName(projectDir)
+		m.Note = notes[meta.MemoryNoteKey(projectDir, m.FileName)]
 		memories = append(memories, m)
 	}
 
@@ -141,7 +141,7 @@ func ListMemories(projectDir string) ([]Memory, error) {
 
 // ReadMemory reads and parses a single memory file, including YAML frontmatter.
 func ReadMemory(path string) (Memory, error) {
-	data, err := os.ReadFile(path)
+	data, err := claude.RootOf(path).ReadFile(path)
 	if err != nil {
 		return Memory{}, err
 	}
@@ -152,7 +152,7 @@ func ReadMemory(path string) (Memory, error) {
 		FileName: filepath.Base(path),
 	}
 
-	if info, err := os.Stat(path); err == nil {
+	if info, err := claude.RootOf(path).Stat(path); err == nil {
 		m.ModTime = info.ModTime().Format(time.RFC3339)
 	}
 
@@ -201,14 +201,15 @@ func Delete(memories []Memory) []DeleteResult {
 	byDir := make(map[string][]string) // dir -> filenames
 
 	for _, m := range memories {
-		r := DeleteResult{FileName: m.FileName, Success: true}
+		r := DeleteResult{FileName: m.FileName, FullPath: m.FullPath, Success: true}
 
-		if err := os.Remove(m.FullPath); err != nil && !os.IsNotExist(err) {
+		if err := claude.For(m.Profile).Remove(m.FullPath); err != nil && !os.IsNotExist(err) {
 			r.Success = false
 			r.Error = fmt.Sprintf("removing file: %v", err)
 			results = append(results, r)
 			continue
 		}
+		audit.Record(audit.Entry{Op: audit.OpDelete, Kind: audit.KindMemory, ID: m.FileName, Paths: []string{m.FullPath}, Before: m.Name})
 
 		dir := filepath.Dir(m.FullPath)
 		byDir[dir] = append(byDir[dir], m.FileName)
@@ -217,18 +218,30 @@ func Delete(memories []Memory) []DeleteResult {
 
 	// Update MEMORY.md in each affected directory.
 	for dir, filenames := range byDir {
-		removeFromIndex(filepath.Join(dir, "MEMORY.md"), filenames)
+		if err := removeFromIndex(filepath.Join(dir, "MEMORY.md"), filenames); err != nil {
+			for i, r := range results {
+				if r.Success && filepath.Dir(memories[i].FullPath) == dir {
+					results[i].Success = false
+					results[i].Error = fmt.Sprintf("updating MEMORY.md: %v", err)
+				}
+			}
+		}
 	}
 
 	return results
 }
 
 // removeFromIndex reads a MEMORY.md file, removes lines referencing the
-// given filenames, and writes it back. Errors are silently ignored.
-func removeFromIndex(indexPath string, filenames []string) {
-	data, err := os.ReadFile(indexPath)
+// given filenames, and writes it back, backing up the old one first. A
+// missing MEMORY.md is left missing.
+func removeFromIndex(indexPath string, filenames []string) error {
+	root := claude.RootOf(indexPath)
+	data, err := root.ReadFile(indexPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
 	if err != nil {
-		return
+		return err
 	}
 
 	removed := make(map[string]bool)
@@ -256,7 +269,13 @@ func removeFromIndex(indexPath string, filenames []string) {
 	}
 
 	result := strings.Join(kept, "\n") + "\n"
-	os.WriteFile(indexPath, []byte(result), 0644)
+	if result == string(data) {
+		return nil
+	}
+	if err := backup.Save(root, indexPath); err != nil {
+		return err
+	}
+	return root.WriteFile(indexPath, []byte(result), 0644)
 }
 
 // decodeDirName converts an encoded project directory name back to a path.
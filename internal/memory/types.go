@@ -1,5 +1,7 @@
 package memory
 
+import "strings"
+
 // Memory represents a single Claude memory file with parsed frontmatter.
 type Memory struct {
 	Name        string // from YAML frontmatter "name" field
@@ -8,13 +10,24 @@ type Memory struct {
 	Content     string // markdown body after frontmatter
 	FileName    string // e.g. "feedback_github_urls.md"
 	FullPath    string // absolute path to the .md file
+	Profile     string // profile of the data directory it is in; empty without profiles
 	ProjectDir  string // encoded project directory name
 	ProjectPath string // decoded project path
 	ModTime     string // file modification time as RFC3339
+	Note        string // markdown note from clsm's sidecar store
+}
+
+// Matches reports whether the memory matches a list filter term. It is a
+// case-insensitive substring match over the name, description, type, file
+// name and note.
+func (m Memory) Matches(term string) bool {
+	searchable := strings.ToLower(m.Name + " " + m.Description + " " + m.Type + " " + m.FileName + " " + m.Note)
+	return strings.Contains(searchable, strings.ToLower(term))
 }
 
 // MemoryProject represents a project that has a memory directory.
 type MemoryProject struct {
+	Profile      string // profile of the data directory it is in; empty without profiles
 	DirName      string // encoded directory name
 	Path         string // decoded project path
 	MemoryCount  int    // number of .md files in memory/ (excluding MEMORY.md)
@@ -22,9 +35,16 @@ type MemoryProject struct {
 	LastModified string // most recent memory file modification time
 }
 
+// Matches reports whether the project path contains the given filter term,
+// ignoring case.
+func (p MemoryProject) Matches(term string) bool {
+	return strings.Contains(strings.ToLower(p.Path), strings.ToLower(term))
+}
+
 // DeleteResult tracks the outcome of deleting a single memory.
 type DeleteResult struct {
 	FileName string
+	FullPath string
 	Success  bool
 	Error    string
 }
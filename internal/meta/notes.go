@@ -0,0 +1,148 @@
+package meta
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/baz-sh/clsm/internal/atomicfile"
+)
+
+// NoteKind is the kind of item a note is attached to. Notes of each kind
+// live in their own directory under Dir()/notes.
+type NoteKind string
+
+const (
+	NoteSession NoteKind = "sessions" // keyed by session ID
+	NoteMemory  NoteKind = "memories" // keyed by "<project dir>/<file name>"
+	NotePlan    NoteKind = "plans"    // keyed by plan file name
+)
+
+// MemoryNoteKey returns the note key for a memory file.
+func MemoryNoteKey(projectDir, fileName string) string {
+	return projectDir + "/" + fileName
+}
+
+// NotePath returns the markdown file that holds the note for key. The file
+// may not exist yet.
+func NotePath(kind NoteKind, key string) (string, error) {
+	if key == "" || strings.Contains(key, "..") || filepath.IsAbs(key) {
+		return "", fmt.Errorf("invalid note key %q", key)
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	name := key
+	if !strings.HasSuffix(name, ".md") {
+		name += ".md"
+	}
+	return filepath.Join(dir, "notes", string(kind), filepath.FromSlash(name)), nil
+}
+
+// ReadNote returns the note for key, or "" if there is none.
+func ReadNote(kind NoteKind, key string) (string, error) {
+	path, err := NotePath(kind, key)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// WriteNote saves the note for key. An empty note removes it.
+func WriteNote(kind NoteKind, key, text string) error {
+	path, err := NotePath(kind, key)
+	if err != nil {
+		return err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, []byte(text+"\n"), 0o644)
+}
+
+// PrepareNote makes sure the note file for key exists so it can be opened
+// in an editor, and returns its path. Call TidyNote afterwards.
+func PrepareNote(kind NoteKind, key string) (string, error) {
+	path, err := NotePath(kind, key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// TidyNote removes the note file at path if it was left empty, so an
+// editor session that wrote nothing doesn't leave a note behind.
+func TidyNote(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return os.Remove(path)
+	}
+	return nil
+}
+
+// Notes returns every note of a kind keyed as in NotePath. A missing notes
+// directory is no notes.
+func Notes(kind NoteKind) (map[string]string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	root := filepath.Join(dir, "notes", string(kind))
+	notes := make(map[string]string)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		key := filepath.ToSlash(rel)
+		if kind == NoteSession {
+			key = strings.TrimSuffix(key, ".md")
+		}
+		if text := strings.TrimSpace(string(data)); text != "" {
+			notes[key] = text
+		}
+		return nil
+	})
+	return notes, err
+}
@@ -0,0 +1,204 @@
+// Package meta stores clsm's own metadata about sessions — tags and pins —
+// in a sidecar file, so Claude Code's files are never modified. Entries
+// are keyed by session ID, which stays the same when a session's files are
+// moved or archived.
+package meta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/baz-sh/clsm/internal/atomicfile"
+)
+
+// fileVersion is the current version of the metadata file format.
+const fileVersion = 1
+
+// SessionMeta is clsm's metadata for one session.
+type SessionMeta struct {
+	Tags   []string `json:"tags,omitempty"`
+	Pinned bool     `json:"pinned,omitempty"`
+}
+
+// Protected reports whether the session is tagged or pinned, which excludes
+// it from prune and bulk delete.
+func (m SessionMeta) Protected() bool {
+	return len(m.Tags) > 0 || m.Pinned
+}
+
+// Store is the contents of the metadata file.
+type Store struct {
+	Version  int                    `json:"version"`
+	Sessions map[string]SessionMeta `json:"sessions"`
+}
+
+// Dir returns the directory clsm keeps its own data in, normally
+// ~/.config/clsm. CLSM_CONFIG_DIR overrides it.
+func Dir() (string, error) {
+	if dir := os.Getenv("CLSM_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("finding config dir: %w", err)
+	}
+	return filepath.Join(base, "clsm"), nil
+}
+
+func storePath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "meta.json"), nil
+}
+
+// Load reads the metadata file. A missing file is an empty store.
+func Load() (*Store, error) {
+	st := &Store{Version: fileVersion, Sessions: make(map[string]SessionMeta)}
+	path, err := storePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if st.Version > fileVersion {
+		return nil, fmt.Errorf("%s has version %d; this clsm only understands up to %d", path, st.Version, fileVersion)
+	}
+	if st.Sessions == nil {
+		st.Sessions = make(map[string]SessionMeta)
+	}
+	return st, nil
+}
+
+// Save writes the store to disk atomically, so a crash never leaves a
+// half-written file.
+func (st *Store) Save() error {
+	path, err := storePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	st.Version = fileVersion
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// Update loads the store, applies fn and saves the result if fn succeeds.
+func Update(fn func(*Store) error) error {
+	st, err := Load()
+	if err != nil {
+		return err
+	}
+	if err := fn(st); err != nil {
+		return err
+	}
+	return st.Save()
+}
+
+// Get returns the metadata for a session; the zero value if it has none.
+func (st *Store) Get(id string) SessionMeta {
+	return st.Sessions[id]
+}
+
+func (st *Store) set(id string, m SessionMeta) {
+	if len(m.Tags) == 0 && !m.Pinned {
+		delete(st.Sessions, id)
+		return
+	}
+	st.Sessions[id] = m
+}
+
+// Tag adds tags to a session. Tags already present are ignored.
+func (st *Store) Tag(id string, tags ...string) {
+	m := st.Get(id)
+	for _, t := range tags {
+		if !contains(m.Tags, t) {
+			m.Tags = append(m.Tags, t)
+		}
+	}
+	sort.Strings(m.Tags)
+	st.set(id, m)
+}
+
+// Untag removes tags from a session. With no tags, it removes all of them.
+func (st *Store) Untag(id string, tags ...string) {
+	m := st.Get(id)
+	if len(tags) == 0 {
+		m.Tags = nil
+	} else {
+		kept := m.Tags[:0]
+		for _, t := range m.Tags {
+			if !contains(tags, t) {
+				kept = append(kept, t)
+			}
+		}
+		m.Tags = kept
+	}
+	st.set(id, m)
+}
+
+// SetPinned pins or unpins a session.
+func (st *Store) SetPinned(id string, pinned bool) {
+	m := st.Get(id)
+	m.Pinned = pinned
+	st.set(id, m)
+}
+
+// TagCounts returns how many sessions carry each tag.
+func (st *Store) TagCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, m := range st.Sessions {
+		for _, t := range m.Tags {
+			counts[t]++
+		}
+	}
+	return counts
+}
+
+// ParseTags splits user input on commas and whitespace into normalized
+// tags: lowercase, without a leading '#', and de-duplicated.
+func ParseTags(input ...string) []string {
+	var tags []string
+	for _, in := range input {
+		for _, t := range strings.FieldsFunc(in, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		}) {
+			t = strings.ToLower(strings.TrimPrefix(t, "#"))
+			if t != "" && !contains(tags, t) {
+				tags = append(tags, t)
+			}
+		}
+	}
+	return tags
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
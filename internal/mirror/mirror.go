@@ -0,0 +1,856 @@
+// Package mirror keeps a mirror directory, such as a NAS mount, a USB drive
+// or a folder another tool syncs, consistent with ~/.claude in both
+// directions. The mirror has the same layout as ~/.claude, with projects/
+// and plans/, so clsm import can read it too.
+//
+// Each side is compared with the base recorded after the last sync: a
+// file that differs from its base changed on that side, and a file that
+// changed on both sides is a conflict. A transcript that only grew on
+// both sides, because one side has more of the same messages, is not.
+package mirror
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/atomicfile"
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/backup"
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/meta"
+	"github.com/baz-sh/clsm/internal/redact"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+// Kinds of file a Change copies.
+const (
+	KindSession  = "session"
+	KindSubagent = "subagent"
+	KindIndex    = "index"
+	KindMemory   = "memory"
+	KindPlan     = "plan"
+)
+
+// Actions a Change takes.
+const (
+	ActionPush         = "push" // copy local to mirror
+	ActionPull         = "pull" // copy mirror to local
+	ActionDeleteLocal  = "delete local"
+	ActionDeleteMirror = "delete mirror"
+	ActionConflict     = "conflict" // changed on both sides; left alone
+	ActionSkip         = "skip"     // Claude Code is writing the local transcript; left alone
+)
+
+// Options selects what is synced.
+type Options struct {
+	Project  string // only projects whose path contains this term
+	Sessions bool   // transcripts, subagent transcripts and session indexes
+	Memories bool
+	Plans    bool
+	Tagged   bool   // only tagged or pinned sessions
+	Prefer   string // "local" or "mirror" settles conflicts in favor of that side
+	Force    bool   // also replace or delete transcripts Claude Code is writing
+}
+
+// Change is one planned step of a sync.
+type Change struct {
+	Rel       string // path relative to ~/.claude and to the mirror, with slashes
+	Kind      string // one of the Kind constants
+	Action    string // one of the Action constants
+	Reason    string
+	SessionID string // for transcripts
+
+	local, mirror *file // as planned; nil if missing
+
+	// For indexes: entries to merge into the target, by session ID, and
+	// sessions whose entries to remove.
+	entries map[string]json.RawMessage
+	remove  []string
+}
+
+// Result is the outcome of applying one Change.
+type Result struct {
+	Rel     string
+	Action  string
+	Success bool
+	Error   string
+}
+
+// Sync is a planned sync with one mirror.
+type Sync struct {
+	Mirror  string
+	Changes []Change
+
+	local, remote side
+	state         *state
+	next          map[string]*Base // bases of files already in sync; nil drops one
+}
+
+// file is a file on one side.
+type file struct {
+	root    *claude.Root
+	path    string
+	kind    string
+	session string // the session the file belongs to, when known
+	stat    Stat
+	hash    string // filled in when needed
+}
+
+// side is ~/.claude or the mirror.
+type side struct {
+	root     *claude.Root
+	projects string
+	plans    string
+}
+
+func (s side) exists(rel string) bool {
+	_, err := s.root.Stat(s.path(rel))
+	return err == nil
+}
+
+func (s side) path(rel string) string {
+	top, rest, _ := strings.Cut(rel, "/")
+	if top == "plans" {
+		return filepath.Join(s.plans, filepath.FromSlash(rest))
+	}
+	return filepath.Join(s.projects, filepath.FromSlash(rest))
+}
+
+// Plan compares ~/.claude with the mirror at dir and works out what a sync
+// would do, without writing anything.
+func Plan(dir string, opts Options) (*Sync, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	local, remote := claude.Current(), claude.New(dir)
+	st, err := loadState(local.Dir, dir)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		if len(st.Files) > 0 {
+			return nil, fmt.Errorf("mirror %s is missing; is it mounted?", dir)
+		}
+	}
+
+	s := &Sync{
+		Mirror: dir,
+		local:  side{root: local, projects: local.ProjectsDir(), plans: local.Plans},
+		remote: side{root: remote, projects: remote.ProjectsDir(), plans: filepath.Join(dir, "plans")},
+		state:  st,
+		next:   map[string]*Base{},
+	}
+
+	var tagged map[string]bool
+	if opts.Tagged {
+		store, err := meta.Load()
+		if err != nil {
+			return nil, err
+		}
+		tagged = map[string]bool{}
+		for id, m := range store.Sessions {
+			if m.Protected() {
+				tagged[id] = true
+			}
+		}
+	}
+	localFiles, err := s.local.scan(opts, tagged)
+	if err != nil {
+		return nil, err
+	}
+	remoteFiles, err := s.remote.scan(opts, tagged)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMounted(st, localFiles, remoteFiles); err != nil {
+		return nil, err
+	}
+
+	rels := make([]string, 0, len(localFiles)+len(remoteFiles))
+	for rel := range localFiles {
+		rels = append(rels, rel)
+	}
+	for rel := range remoteFiles {
+		if localFiles[rel] == nil {
+			rels = append(rels, rel)
+		}
+	}
+	sort.Strings(rels)
+	for _, rel := range rels {
+		if err := s.decide(rel, localFiles[rel], remoteFiles[rel], opts.Prefer); err != nil {
+			return nil, err
+		}
+	}
+	if !opts.Force {
+		s.skipLive()
+	}
+	// Forget files gone from both sides.
+	for rel := range st.Files {
+		if localFiles[rel] == nil && remoteFiles[rel] == nil && !s.local.exists(rel) && !s.remote.exists(rel) {
+			s.next[rel] = nil
+		}
+	}
+
+	if opts.Sessions {
+		if err := s.planIndexes(localFiles, remoteFiles); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// checkMounted refuses to sync when one side has none of the files synced
+// before, which more likely means an unmounted drive than that they were
+// all deleted.
+func checkMounted(st *state, local, remote map[string]*file) error {
+	var before, onLocal, onRemote int
+	for rel := range st.Files {
+		l, r := local[rel] != nil, remote[rel] != nil
+		if !l && !r {
+			continue
+		}
+		before++
+		if l {
+			onLocal++
+		}
+		if r {
+			onRemote++
+		}
+	}
+	switch {
+	case before > 0 && onRemote == 0:
+		return fmt.Errorf("the mirror has none of the %d files synced before; is it mounted?", before)
+	case before > 0 && onLocal == 0:
+		return fmt.Errorf("~/.claude has none of the %d files synced before; refusing to delete them from the mirror", before)
+	}
+	return nil
+}
+
+// scan lists the files of a side that opts selects.
+func (s side) scan(opts Options, tagged map[string]bool) (map[string]*file, error) {
+	files := map[string]*file{}
+	add := func(rel, p, kind, id string) {
+		if tagged != nil && (kind == KindSession || kind == KindSubagent) && !tagged[id] {
+			return
+		}
+		info, err := s.root.Stat(p)
+		if err != nil || !info.Mode().IsRegular() {
+			return
+		}
+		files[rel] = &file{
+			root:    s.root,
+			path:    p,
+			kind:    kind,
+			session: id,
+			stat:    Stat{Size: info.Size(), MTime: info.ModTime().UnixNano()},
+		}
+	}
+
+	if opts.Sessions || opts.Memories {
+		projects, err := s.root.ReadDir(s.projects)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		for _, d := range projects {
+			if !d.IsDir() || !matchesProject(d.Name(), opts.Project) {
+				continue
+			}
+			dir := filepath.Join(s.projects, d.Name())
+			prefix := "projects/" + d.Name() + "/"
+			if opts.Sessions {
+				transcripts, _ := s.root.Glob(filepath.Join(dir, "*.jsonl"))
+				for _, p := range transcripts {
+					name := filepath.Base(p)
+					if strings.HasPrefix(name, "agent-") {
+						id := ""
+						if tagged != nil {
+							id = parentSession(s.root, p)
+						}
+						add(prefix+name, p, KindSubagent, id)
+						continue
+					}
+					add(prefix+name, p, KindSession, strings.TrimSuffix(name, ".jsonl"))
+				}
+				nested, _ := s.root.Glob(filepath.Join(dir, "*", "subagents", "*.jsonl"))
+				for _, p := range nested {
+					rel, _ := filepath.Rel(dir, p)
+					rel = filepath.ToSlash(rel)
+					id, _, _ := strings.Cut(rel, "/")
+					add(prefix+rel, p, KindSubagent, id)
+				}
+			}
+			if opts.Memories {
+				memories, _ := s.root.Glob(filepath.Join(dir, "memory", "*.md"))
+				for _, p := range memories {
+					add(prefix+"memory/"+filepath.Base(p), p, KindMemory, "")
+				}
+			}
+		}
+	}
+	if opts.Plans {
+		plans, _ := s.root.Glob(filepath.Join(s.plans, "*.md"))
+		for _, p := range plans {
+			add("plans/"+filepath.Base(p), p, KindPlan, "")
+		}
+	}
+	return files, nil
+}
+
+// decide works out what to do with one file.
+func (s *Sync) decide(rel string, l, m *file, prefer string) error {
+	b, synced := s.state.Files[rel]
+	lh, err := hashOf(l, b.Local, b, synced)
+	if err != nil {
+		return err
+	}
+	mh, err := hashOf(m, b.Mirror, b, synced)
+	if err != nil {
+		return err
+	}
+	if lh == mh {
+		s.next[rel] = &Base{Hash: lh, Local: l.stat, Mirror: m.stat}
+		return nil
+	}
+
+	c := Change{Rel: rel, local: l, mirror: m}
+	if f := either(l, m); f != nil {
+		c.Kind = f.kind
+		if f.kind == KindSession {
+			c.SessionID = f.session
+		}
+	}
+	switch {
+	case synced && mh == b.Hash && l == nil:
+		c.Action, c.Reason = ActionDeleteMirror, "deleted locally"
+	case synced && mh == b.Hash:
+		c.Action, c.Reason = ActionPush, "changed locally"
+	case synced && lh == b.Hash && m == nil:
+		c.Action, c.Reason = ActionDeleteLocal, "deleted in mirror"
+	case synced && lh == b.Hash:
+		c.Action, c.Reason = ActionPull, "changed in mirror"
+	case l == nil && synced:
+		c.Action, c.Reason = ActionPull, "changed in mirror, deleted locally"
+	case l == nil:
+		c.Action, c.Reason = ActionPull, "new in mirror"
+	case m == nil && synced:
+		c.Action, c.Reason = ActionPush, "changed locally, deleted in mirror"
+	case m == nil:
+		c.Action, c.Reason = ActionPush, "new locally"
+	default:
+		grown, err := appended(l, m)
+		if err != nil {
+			return err
+		}
+		switch {
+		case grown == l:
+			c.Action, c.Reason = ActionPush, "more messages locally"
+		case grown == m:
+			c.Action, c.Reason = ActionPull, "more messages in mirror"
+		case prefer == "local":
+			c.Action, c.Reason = ActionPush, "changed on both sides; keeping local"
+		case prefer == "mirror":
+			c.Action, c.Reason = ActionPull, "changed on both sides; keeping mirror"
+		default:
+			c.Action, c.Reason = ActionConflict, "changed on both sides"
+		}
+	}
+	s.Changes = append(s.Changes, c)
+	return nil
+}
+
+// skipLive turns pulls and deletes of local transcripts Claude Code is
+// writing into skips, as replacing one could lose what it writes next.
+func (s *Sync) skipLive() {
+	var sessions []session.Session
+	for _, c := range s.Changes {
+		if c.Kind == KindSession && c.local != nil && (c.Action == ActionPull || c.Action == ActionDeleteLocal) {
+			sessions = append(sessions, session.Session{SessionID: c.SessionID, FullPath: c.local.path, Profile: s.local.root.Name})
+		}
+	}
+	_, live := session.SplitLive(sessions)
+	reasons := make(map[string]string, len(live))
+	for _, l := range live {
+		reasons[l.SessionID] = l.Live
+	}
+	for i, c := range s.Changes {
+		if r, ok := reasons[c.SessionID]; ok && c.Kind == KindSession {
+			s.Changes[i].Action = ActionSkip
+			s.Changes[i].Reason = fmt.Sprintf("%s, but in use by Claude Code (%s)", c.Reason, r)
+		}
+	}
+}
+
+// planIndexes works out which sessions-index.json entries each side needs
+// once the transcripts are synced. An entry follows its transcript; for a
+// transcript already in sync, a missing entry is copied and the entry
+// modified last wins.
+func (s *Sync) planIndexes(local, remote map[string]*file) error {
+	byID := map[string]*Change{}
+	for i, c := range s.Changes {
+		if c.Kind == KindSession {
+			byID[c.SessionID] = &s.Changes[i]
+		}
+	}
+
+	projects := map[string]map[string]bool{}
+	for _, files := range []map[string]*file{local, remote} {
+		for rel, f := range files {
+			if f.kind != KindSession {
+				continue
+			}
+			proj := strings.Split(rel, "/")[1]
+			if projects[proj] == nil {
+				projects[proj] = map[string]bool{}
+			}
+			projects[proj][f.session] = true
+		}
+	}
+	names := make([]string, 0, len(projects))
+	for proj := range projects {
+		names = append(names, proj)
+	}
+	sort.Strings(names)
+
+	for _, proj := range names {
+		rel := "projects/" + proj + "/" + session.IndexFileName
+		l, err := session.IndexEntries(s.local.root, s.local.path(rel))
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.local.path(rel), err)
+		}
+		m, err := session.IndexEntries(s.remote.root, s.remote.path(rel))
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.remote.path(rel), err)
+		}
+		toLocal := Change{Rel: rel, Kind: KindIndex, Action: ActionPull, entries: map[string]json.RawMessage{}}
+		toMirror := Change{Rel: rel, Kind: KindIndex, Action: ActionPush, entries: map[string]json.RawMessage{}}
+
+		ids := make([]string, 0, len(projects[proj]))
+		for id := range projects[proj] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			action := ""
+			if c := byID[id]; c != nil {
+				action = c.Action
+			}
+			switch action {
+			case ActionConflict, ActionSkip:
+			case ActionPull:
+				if m[id] != nil {
+					toLocal.entries[id] = m[id]
+				}
+			case ActionPush:
+				if l[id] != nil {
+					toMirror.entries[id] = l[id]
+				}
+			case ActionDeleteLocal:
+				if l[id] != nil {
+					toLocal.remove = append(toLocal.remove, id)
+				}
+			case ActionDeleteMirror:
+				if m[id] != nil {
+					toMirror.remove = append(toMirror.remove, id)
+				}
+			default:
+				switch {
+				case l[id] == nil && m[id] == nil:
+				case l[id] == nil:
+					toLocal.entries[id] = m[id]
+				case m[id] == nil:
+					toMirror.entries[id] = l[id]
+				case !sameEntry(l[id], m[id]):
+					if entryTime(m[id]) > entryTime(l[id]) {
+						toLocal.entries[id] = m[id]
+					} else if entryTime(l[id]) > entryTime(m[id]) {
+						toMirror.entries[id] = l[id]
+					}
+				}
+			}
+		}
+		for _, c := range []Change{toLocal, toMirror} {
+			if len(c.entries) == 0 && len(c.remove) == 0 {
+				continue
+			}
+			var parts []string
+			if len(c.entries) > 0 {
+				parts = append(parts, fmt.Sprintf("%d to merge", len(c.entries)))
+			}
+			if len(c.remove) > 0 {
+				parts = append(parts, fmt.Sprintf("%d to remove", len(c.remove)))
+			}
+			c.Reason = "entries: " + strings.Join(parts, ", ")
+			s.Changes = append(s.Changes, c)
+		}
+	}
+	return nil
+}
+
+// Conflicts returns the changes left alone because both sides changed.
+func (s *Sync) Conflicts() []Change {
+	return s.left(ActionConflict)
+}
+
+// Skipped returns the changes left alone because Claude Code is writing the
+// local transcript.
+func (s *Sync) Skipped() []Change {
+	return s.left(ActionSkip)
+}
+
+func (s *Sync) left(action string) []Change {
+	var out []Change
+	for _, c := range s.Changes {
+		if c.Action == action {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// NewBackupDir returns a new directory under clsm's config directory to
+// move files into before a sync deletes them.
+func NewBackupDir() (string, error) {
+	dir, err := meta.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "backups", "sync-"+time.Now().Format("20060102-150405")), nil
+}
+
+// Apply carries out the planned changes and records the new base. Files
+// are deleted by moving them into backupDir. A file that changed since it
+// was planned is left alone, and so are conflicts.
+func (s *Sync) Apply(backupDir string) []Result {
+	var results []Result
+	failed := map[string]bool{} // sessions whose transcript wasn't synced
+	for _, c := range s.Changes {
+		if c.Kind == KindIndex || c.Action == ActionConflict || c.Action == ActionSkip {
+			continue
+		}
+		r := Result{Rel: c.Rel, Action: c.Action}
+		if err := s.apply(c, backupDir); err != nil {
+			r.Error = err.Error()
+			if c.Kind == KindSession {
+				failed[c.SessionID] = true
+			}
+		} else {
+			r.Success = true
+			s.record(c, backupDir)
+		}
+		results = append(results, r)
+	}
+	for _, c := range s.Changes {
+		if c.Kind != KindIndex {
+			continue
+		}
+		r := Result{Rel: c.Rel, Action: c.Action}
+		if err := s.applyIndex(c, failed); err != nil {
+			r.Error = err.Error()
+		} else {
+			r.Success = true
+			s.record(c, "")
+		}
+		results = append(results, r)
+	}
+
+	for rel, b := range s.next {
+		if b == nil {
+			delete(s.state.Files, rel)
+		} else {
+			s.state.Files[rel] = *b
+		}
+	}
+	if err := s.state.save(); err != nil {
+		results = append(results, Result{Rel: "sync state", Error: err.Error()})
+	}
+	return results
+}
+
+// record adds an applied change to the audit log.
+func (s *Sync) record(c Change, backupDir string) {
+	e := audit.Entry{Op: audit.OpSync, Kind: c.Kind, ID: c.SessionID, After: c.Action}
+	switch c.Kind {
+	case KindSubagent:
+		e.Kind = audit.KindSession
+	case KindIndex:
+		e.Kind = audit.KindIndex
+	}
+	switch c.Action {
+	case ActionPush:
+		e.Paths = []string{s.remote.path(c.Rel)}
+	case ActionPull:
+		e.Paths = []string{s.local.path(c.Rel)}
+	case ActionDeleteLocal:
+		e.Paths = []string{s.local.path(c.Rel)}
+		e.Backup = filepath.Join(backupDir, "local", filepath.FromSlash(c.Rel))
+	case ActionDeleteMirror:
+		e.Paths = []string{s.remote.path(c.Rel)}
+		e.Backup = filepath.Join(backupDir, "mirror", filepath.FromSlash(c.Rel))
+	}
+	audit.Record(e)
+}
+
+func (s *Sync) apply(c Change, backupDir string) error {
+	switch c.Action {
+	case ActionPush:
+		b, err := copyFile(c.local, s.remote.root, s.remote.path(c.Rel), c.mirror)
+		if err != nil {
+			return err
+		}
+		s.next[c.Rel] = &Base{Hash: b.Hash, Local: b.Local, Mirror: b.Mirror}
+	case ActionPull:
+		b, err := copyFile(c.mirror, s.local.root, s.local.path(c.Rel), c.local)
+		if err != nil {
+			return err
+		}
+		s.next[c.Rel] = &Base{Hash: b.Hash, Local: b.Mirror, Mirror: b.Local}
+	case ActionDeleteLocal:
+		if err := remove(c.local, filepath.Join(backupDir, "local", filepath.FromSlash(c.Rel))); err != nil {
+			return err
+		}
+		s.next[c.Rel] = nil
+	case ActionDeleteMirror:
+		if err := remove(c.mirror, filepath.Join(backupDir, "mirror", filepath.FromSlash(c.Rel))); err != nil {
+			return err
+		}
+		s.next[c.Rel] = nil
+	}
+	return nil
+}
+
+// applyIndex merges and removes index entries on the target side. Entries
+// copied to ~/.claude get a fullPath that points at the local transcript.
+func (s *Sync) applyIndex(c Change, failed map[string]bool) error {
+	target := s.remote
+	if c.Action == ActionPull {
+		target = s.local
+	}
+	idxPath := target.path(c.Rel)
+	ids := make([]string, 0, len(c.entries))
+	for id := range c.entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var errs []error
+	for _, id := range ids {
+		if failed[id] {
+			continue
+		}
+		entry := c.entries[id]
+		if c.Action == ActionPull {
+			full := filepath.Join(filepath.Dir(idxPath), id+".jsonl")
+			entry, _ = redact.RewriteFields(entry, []string{"fullPath"}, func(string) (string, int) { return full, 1 })
+		}
+		var p struct {
+			ProjectPath string `json:"projectPath"`
+		}
+		json.Unmarshal(entry, &p)
+		if err := session.MergeIndexEntry(target.root, idxPath, entry, p.ProjectPath); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, id := range c.remove {
+		if failed[id] {
+			continue
+		}
+		if err := session.RemoveIndexEntry(target.root, idxPath, id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// copyFile copies src over dst, or to a new file at dstPath, keeping its
+// modification time, and returns the base of the copy: Local is the
+// source's stat and Mirror the destination's.
+func copyFile(src *file, root *claude.Root, dstPath string, dst *file) (Base, error) {
+	if err := unchanged(root, dstPath, dst); err != nil {
+		return Base{}, err
+	}
+	data, err := src.root.ReadFile(src.path)
+	if err != nil {
+		return Base{}, err
+	}
+	info, err := src.root.Stat(src.path)
+	if err != nil {
+		return Base{}, err
+	}
+	if err := root.MkdirAll(filepath.Dir(dstPath), 0o755); err != nil {
+		return Base{}, err
+	}
+	if backup.IsIndex(dstPath) {
+		if err := backup.Save(root, dstPath); err != nil {
+			return Base{}, err
+		}
+	}
+	tmp := dstPath + ".clsm-tmp"
+	if err := root.WriteFile(tmp, data, info.Mode().Perm()); err != nil {
+		root.Remove(tmp)
+		return Base{}, err
+	}
+	if err := root.Chtimes(tmp, info.ModTime(), info.ModTime()); err != nil {
+		root.Remove(tmp)
+		return Base{}, err
+	}
+	if err := root.Rename(tmp, dstPath); err != nil {
+		root.Remove(tmp)
+		return Base{}, err
+	}
+
+	b := Base{Hash: hashBytes(data), Local: Stat{Size: info.Size(), MTime: info.ModTime().UnixNano()}}
+	if info.Size() != int64(len(data)) {
+		b.Local = Stat{} // it grew while being copied; hash it again next time
+	}
+	if out, err := root.Stat(dstPath); err == nil {
+		b.Mirror = Stat{Size: out.Size(), MTime: out.ModTime().UnixNano()}
+	}
+	return b, nil
+}
+
+// remove moves f into backup.
+func remove(f *file, backup string) error {
+	if err := unchanged(f.root, f.path, f); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(backup), 0o700); err != nil {
+		return fmt.Errorf("backing up: %w", err)
+	}
+	data, err := f.root.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.WriteFile(backup, data, 0o600); err != nil {
+		return fmt.Errorf("backing up: %w", err)
+	}
+	return f.root.Remove(f.path)
+}
+
+// unchanged checks that the file at p is still as planned, or still
+// missing if f is nil.
+func unchanged(root *claude.Root, p string, f *file) error {
+	info, err := root.Stat(p)
+	switch {
+	case f == nil && err == nil:
+		return errors.New("appeared since it was planned; run again")
+	case f == nil && errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	case info.Size() != f.stat.Size || info.ModTime().UnixNano() != f.stat.MTime:
+		return errors.New("changed since it was planned; run again")
+	}
+	return nil
+}
+
+// hashOf returns the hash of f, or "" if it is nil. A file whose size and
+// modification time match its base is taken to be unchanged.
+func hashOf(f *file, was Stat, b Base, synced bool) (string, error) {
+	if f == nil {
+		return "", nil
+	}
+	if synced && f.stat == was && was != (Stat{}) {
+		f.hash = b.Hash
+		return f.hash, nil
+	}
+	data, err := f.root.ReadFile(f.path)
+	if err != nil {
+		return "", err
+	}
+	f.hash = hashBytes(data)
+	return f.hash, nil
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// appended returns whichever of two transcripts begins with all of the
+// other, or nil if neither does or they aren't transcripts.
+func appended(l, m *file) (*file, error) {
+	if !strings.HasSuffix(l.path, ".jsonl") {
+		return nil, nil
+	}
+	ld, err := l.root.ReadFile(l.path)
+	if err != nil {
+		return nil, err
+	}
+	md, err := m.root.ReadFile(m.path)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(ld, md):
+		return l, nil
+	case bytes.HasPrefix(md, ld):
+		return m, nil
+	}
+	return nil, nil
+}
+
+// sameEntry reports whether two index entries match apart from fullPath,
+// which differs between machines.
+func sameEntry(a, b json.RawMessage) bool {
+	blank := func(string) (string, int) { return "", 1 }
+	a, _ = redact.RewriteFields(a, []string{"fullPath"}, blank)
+	b, _ = redact.RewriteFields(b, []string{"fullPath"}, blank)
+	return bytes.Equal(a, b)
+}
+
+// entryTime returns when an index entry was last modified, as its
+// modified timestamp.
+func entryTime(e json.RawMessage) string {
+	var t struct {
+		Modified string `json:"modified"`
+	}
+	json.Unmarshal(e, &t)
+	return t.Modified
+}
+
+// parentSession returns the session ID recorded in a subagent transcript.
+func parentSession(root *claude.Root, p string) string {
+	f, err := root.Open(p)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var entry struct {
+			SessionID string `json:"sessionId"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil && entry.SessionID != "" {
+			return entry.SessionID
+		}
+	}
+	return ""
+}
+
+// matchesProject reports whether a project directory name matches a
+// --project term, which is encoded the way the directory name is.
+func matchesProject(dirName, term string) bool {
+	return strings.Contains(strings.ToLower(dirName), strings.ToLower(session.EncodeDirName(term)))
+}
+
+// either returns the first of files that is not nil.
+func either(files ...*file) *file {
+	for _, f := range files {
+		if f != nil {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package mirror
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+func TestDecide(t *testing.T) {
+	const (
+		transcript = "projects/-p/3f2a8c1e-0b4d-4e5f-9a6b-7c8d9e0f1a2b.jsonl"
+		memory     = "projects/-p/memory/notes.md"
+		none       = "\x00" // marks a file missing from its side
+	)
+
+	tests := []struct {
+		name       string
+		rel        string
+		base       string // content after the last sync; none if never synced
+		local      string
+		mirror     string
+		prefer     string
+		wantAction string // "" when the sides are in sync
+	}{
+		{"in sync", transcript, "a\n", "a\n", "a\n", "", ""},
+		{"same on both sides, never synced", memory, none, "a", "a", "", ""},
+		{"changed locally", memory, "a", "b", "a", "", ActionPush},
+		{"changed in mirror", memory, "a", "a", "b", "", ActionPull},
+		{"deleted locally", memory, "a", none, "a", "", ActionDeleteMirror},
+		{"deleted in mirror", memory, "a", "a", none, "", ActionDeleteLocal},
+		{"changed in mirror, deleted locally", memory, "a", none, "b", "", ActionPull},
+		{"changed locally, deleted in mirror", memory, "a", "b", none, "", ActionPush},
+		{"new locally", memory, none, "a", none, "", ActionPush},
+		{"new in mirror", memory, none, none, "a", "", ActionPull},
+		{"more messages locally", transcript, "a\n", "a\nb\nc\n", "a\nb\n", "", ActionPush},
+		{"more messages in mirror", transcript, "a\n", "a\nb\n", "a\nb\nc\n", "", ActionPull},
+		{"more messages, never synced", transcript, none, "a\n", "a\nb\n", "", ActionPull},
+		{"transcripts diverged", transcript, "a\n", "a\nx\n", "a\ny\n", "", ActionConflict},
+		{"transcripts diverged, prefer local", transcript, "a\n", "a\nx\n", "a\ny\n", "local", ActionPush},
+		{"transcripts diverged, prefer mirror", transcript, "a\n", "a\nx\n", "a\ny\n", "mirror", ActionPull},
+		{"memory changed on both sides", memory, "a", "ab", "abc", "", ActionConflict},
+		{"memory differs, never synced", memory, none, "a", "b", "", ActionConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local, remote := claude.New(t.TempDir()), claude.New(t.TempDir())
+			s := &Sync{
+				local:  side{root: local, projects: local.ProjectsDir(), plans: local.Plans},
+				remote: side{root: remote, projects: remote.ProjectsDir(), plans: remote.Plans},
+				state:  &state{Files: map[string]Base{}},
+				next:   map[string]*Base{},
+			}
+			if tt.base != none {
+				s.state.Files[tt.rel] = Base{Hash: hashBytes([]byte(tt.base))}
+			}
+			kind := KindMemory
+			if tt.rel == transcript {
+				kind = KindSession
+			}
+			var l, m *file
+			if tt.local != none {
+				l = writeFile(t, s.local, tt.rel, kind, tt.local)
+			}
+			if tt.mirror != none {
+				m = writeFile(t, s.remote, tt.rel, kind, tt.mirror)
+			}
+
+			if err := s.decide(tt.rel, l, m, tt.prefer); err != nil {
+				t.Fatalf("decide: %v", err)
+			}
+			if tt.wantAction == "" {
+				if len(s.Changes) != 0 {
+					t.Fatalf("got %s (%s), want no change", s.Changes[0].Action, s.Changes[0].Reason)
+				}
+				if s.next[tt.rel] == nil {
+					t.Error("in-sync file has no new base")
+				}
+				return
+			}
+			if len(s.Changes) != 1 {
+				t.Fatalf("got %d changes, want 1", len(s.Changes))
+			}
+			if c := s.Changes[0]; c.Action != tt.wantAction {
+				t.Errorf("got %s (%s), want %s", c.Action, c.Reason, tt.wantAction)
+			}
+		})
+	}
+}
+
+// writeFile writes content to rel on one side and returns it as scanned.
+func writeFile(t *testing.T, sd side, rel, kind, content string) *file {
+	t.Helper()
+	p := sd.path(rel)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &file{root: sd.root, path: p, kind: kind, stat: Stat{Size: info.Size(), MTime: info.ModTime().UnixNano()}}
+}
+
+func TestPlanKeepsBasePerDataDir(t *testing.T) {
+	t.Setenv("CLSM_CONFIG_DIR", t.TempDir())
+	t.Cleanup(func() { claude.UseAll(nil) })
+	const (
+		first  = "projects/-p/11111111-1111-4111-8111-111111111111.jsonl"
+		second = "projects/-p/22222222-2222-4222-8222-222222222222.jsonl"
+	)
+	dir := t.TempDir()
+	opts := Options{Sessions: true}
+
+	// The first data directory syncs its transcript to the mirror.
+	a := claude.New(t.TempDir())
+	claude.Use(a)
+	writeFile(t, side{root: a, projects: a.ProjectsDir(), plans: a.Plans}, first, KindSession, "a\n")
+	s, err := Plan(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range s.Apply(t.TempDir()) {
+		if !r.Success {
+			t.Fatalf("%s %s: %s", r.Action, r.Rel, r.Error)
+		}
+	}
+
+	// A second data directory without it must not take that as a delete.
+	b := claude.New(t.TempDir())
+	claude.Use(b)
+	writeFile(t, side{root: b, projects: b.ProjectsDir(), plans: b.Plans}, second, KindSession, "b\n")
+	s, err = Plan(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{first: ActionPull, second: ActionPush}
+	for _, c := range s.Changes {
+		if c.Kind == KindIndex {
+			continue
+		}
+		if want[c.Rel] != c.Action {
+			t.Errorf("%s: got %s (%s), want %s", c.Rel, c.Action, c.Reason, want[c.Rel])
+		}
+		delete(want, c.Rel)
+	}
+	for rel, action := range want {
+		t.Errorf("%s: no change, want %s", rel, action)
+	}
+
+	// The first data directory still has its own base.
+	claude.Use(a)
+	if s, err = Plan(dir, opts); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range s.Changes {
+		if c.Rel == first {
+			t.Errorf("%s: got %s (%s), want it in sync", c.Rel, c.Action, c.Reason)
+		}
+	}
+}
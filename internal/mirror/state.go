@@ -0,0 +1,96 @@
+package mirror
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/baz-sh/clsm/internal/atomicfile"
+	"github.com/baz-sh/clsm/internal/meta"
+)
+
+// stateVersion is the current version of the state file format.
+const stateVersion = 1
+
+// state records every file as it was on both sides after the last sync
+// with one mirror. A file that differs from its base has changed on that
+// side since.
+type state struct {
+	Version int             `json:"version"`
+	Local   string          `json:"local"` // the data directory synced
+	Mirror  string          `json:"mirror"`
+	Files   map[string]Base `json:"files"`
+}
+
+// Base is a file as it was on both sides after the last sync. The sizes
+// and modification times let a sync skip hashing files that haven't been
+// touched.
+type Base struct {
+	Hash   string `json:"hash"`
+	Local  Stat   `json:"local"`
+	Mirror Stat   `json:"mirror"`
+}
+
+// Stat is the size and modification time of a file.
+type Stat struct {
+	Size  int64 `json:"size"`
+	MTime int64 `json:"mtime"` // nanoseconds since the epoch
+}
+
+// statePath returns the state file for a data directory and a mirror, in
+// clsm's config directory rather than the mirror, since every machine and
+// every data directory that syncs with a mirror has its own base.
+func statePath(local, mirror string) (string, error) {
+	dir, err := meta.Dir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(local + "\x00" + mirror))
+	return filepath.Join(dir, "sync", hex.EncodeToString(sum[:6])+".json"), nil
+}
+
+// loadState reads the state for a data directory and a mirror. A pair
+// never synced has an empty state.
+func loadState(local, mirror string) (*state, error) {
+	st := &state{Version: stateVersion, Local: local, Mirror: mirror, Files: map[string]Base{}}
+	p, err := statePath(local, mirror)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", p, err)
+	}
+	if st.Local != local || st.Mirror != mirror {
+		return nil, fmt.Errorf("%s is the state of %s with %s, not %s with %s", p, st.Local, st.Mirror, local, mirror)
+	}
+	if st.Files == nil {
+		st.Files = map[string]Base{}
+	}
+	return st, nil
+}
+
+func (st *state) save() error {
+	p, err := statePath(st.Local, st.Mirror)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(p, append(data, '\n'), 0o600)
+}
@@ -8,30 +8,38 @@ import (
 	"sort"
 	"strings"
 	"time"
-)
 
-// PlansDir returns the path to the Claude plans directory.
-func PlansDir() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".claude", "plans")
-}
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/meta"
+)
 
 // ListPlans returns all plan files sorted by modification time descending.
+// Profiles that share a plans directory list its plans once.
 func ListPlans() ([]Plan, error) {
-	dir := PlansDir()
-
-	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
-	if err != nil {
-		return nil, fmt.Errorf("globbing plan files: %w", err)
-	}
+	notes, _ := meta.Notes(meta.NotePlan)
 
 	var plans []Plan
-	for _, f := range files {
-		p, err := ReadPlan(f)
-		if err != nil {
+	seen := make(map[string]bool)
+	for _, r := range claude.Roots() {
+		if seen[r.Plans] {
 			continue
 		}
-		plans = append(plans, p)
+		seen[r.Plans] = true
+
+		files, err := r.Glob(filepath.Join(r.Plans, "*.md"))
+		if err != nil {
+			return nil, fmt.Errorf("globbing plan files: %w", err)
+		}
+		for _, f := range files {
+			p, err := ReadPlan(f)
+			if err != nil {
+				continue
+			}
+			p.Profile = r.Name
+			p.Note = notes[p.FileName]
+			plans = append(plans, p)
+		}
 	}
 
 	sort.Slice(plans, func(i, j int) bool {
@@ -43,13 +51,13 @@ func ListPlans() ([]Plan, error) {
 
 // ReadPlan reads and parses a plan file, extracting title, context, and project hint.
 func ReadPlan(path string) (Plan, error) {
-	data, err := os.ReadFile(path)
+	data, err := claude.RootOf(path).ReadFile(path)
 	if err != nil {
 		return Plan{}, err
 	}
 
 	content := string(data)
-	info, _ := os.Stat(path)
+	info, _ := claude.RootOf(path).Stat(path)
 
 	p := Plan{
 		FileName: filepath.Base(path),
@@ -76,10 +84,12 @@ func ReadPlan(path string) (Plan, error) {
 func Delete(plans []Plan) []DeleteResult {
 	results := make([]DeleteResult, 0, len(plans))
 	for _, p := range plans {
-		r := DeleteResult{FileName: p.FileName, Success: true}
-		if err := os.Remove(p.FullPath); err != nil && !os.IsNotExist(err) {
+		r := DeleteResult{FileName: p.FileName, FullPath: p.FullPath, Success: true}
+		if err := claude.For(p.Profile).Remove(p.FullPath); err != nil && !os.IsNotExist(err) {
 			r.Success = false
 			r.Error = fmt.Sprintf("removing file: %v", err)
+		} else {
+			audit.Record(audit.Entry{Op: audit.OpDelete, Kind: audit.KindPlan, ID: p.FileName, Paths: []string{p.FullPath}, Before: p.Title})
 		}
 		results = append(results, r)
 	}
@@ -1,19 +1,32 @@
 package plan
 
+import "strings"
+
 // Plan represents a Claude plan file with extracted metadata.
 type Plan struct {
 	FileName    string // e.g. "fluffy-coalescing-giraffe.md"
 	FullPath    string
+	Profile     string // profile of the data directory it is in; empty without profiles
 	Title       string // from first # heading
 	Context     string // first paragraph under ## Context/Overview/Summary
 	ProjectHint string // heuristic: best-guess project path from content
 	ModTime     string // RFC3339
 	Size        int64  // bytes
+	Note        string // markdown note from clsm's sidecar store
+}
+
+// Matches reports whether the plan matches a list filter term. It is a
+// case-insensitive substring match over the title, context, project hint,
+// file name and note.
+func (p Plan) Matches(term string) bool {
+	searchable := strings.ToLower(p.Title + " " + p.Context + " " + p.ProjectHint + " " + p.FileName + " " + p.Note)
+	return strings.Contains(searchable, strings.ToLower(term))
 }
 
 // DeleteResult tracks the outcome of deleting a single plan.
 type DeleteResult struct {
 	FileName string
+	FullPath string
 	Success  bool
 	Error    string
 }
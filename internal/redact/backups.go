@@ -0,0 +1,76 @@
+package redact
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/baz-sh/clsm/internal/meta"
+)
+
+// FindBackups returns the files under clsm's backups directory that still
+// hold text r matches: originals copied before earlier redactions, the
+// rolling copies of index files, and files a sync deleted. Redacting only
+// rewrites Claude Code's files, so these keep the text until removed.
+func FindBackups(r Replacer) ([]string, error) {
+	root, err := backupsDir()
+	if err != nil {
+		return nil, err
+	}
+	var found []string
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == root {
+			return fs.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		// The raw text catches values RewriteJSONL keeps, and RewriteJSONL
+		// catches text escaped inside JSON strings.
+		if _, n := r(string(data)); n > 0 {
+			found = append(found, p)
+		} else if _, n := RewriteJSONL(data, r); n > 0 {
+			found = append(found, p)
+		}
+		return nil
+	})
+	return found, err
+}
+
+// PurgeBackups removes backup files found by FindBackups, and the
+// directories under the backups directory they leave empty.
+func PurgeBackups(paths []string) error {
+	root, err := backupsDir()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		for dir := filepath.Dir(p); dir != root && len(dir) > len(root); dir = filepath.Dir(dir) {
+			if os.Remove(dir) != nil {
+				break // not empty
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func backupsDir() (string, error) {
+	dir, err := meta.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "backups"), nil
+}
@@ -0,0 +1,207 @@
+// Package redact rewrites text in Claude Code's files: in place, to remove
+// a secret or a name from every session, memory and plan, and on the way
+// out, to sanitize a transcript before it is shared. Transcripts are
+// rewritten one JSON string value at a time, so every line stays valid
+// JSON and the IDs that chain entries together are never touched.
+package redact
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/atomicfile"
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/backup"
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/memory"
+	"github.com/baz-sh/clsm/internal/plan"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+// Kinds of file a Change rewrites.
+const (
+	KindSession  = "session"
+	KindSubagent = "subagent"
+	KindIndex    = "index"
+	KindMemory   = "memory"
+	KindPlan     = "plan"
+)
+
+// Options selects which files Plan looks at.
+type Options struct {
+	Project  string // only projects whose path contains this term
+	Sessions bool   // transcripts, subagent transcripts and session indexes
+	Memories bool
+	Plans    bool
+}
+
+// Change is the planned rewrite of one file.
+type Change struct {
+	Path      string
+	Kind      string // one of the Kind constants
+	SessionID string // for transcripts
+	Profile   string // profile of the data directory the file is in; empty without profiles
+	Live      string // for transcripts, why Claude Code seems to be writing the session
+	Title     string // session title, project path or plan title
+	Count     int    // number of replacements
+
+	data []byte   // the rewritten contents
+	sum  [32]byte // hash of the contents the rewrite was made from
+}
+
+// Result is the outcome of applying one Change.
+type Result struct {
+	Path    string
+	Success bool
+	Error   string
+}
+
+// Plan works out which files r changes, without writing anything.
+func Plan(opts Options, r Replacer) ([]Change, error) {
+	var changes []Change
+	text := func(data []byte) ([]byte, int) {
+		s, n := r(string(data))
+		return []byte(s), n
+	}
+	jsonl := func(data []byte) ([]byte, int) { return RewriteJSONL(data, r) }
+	whole := func(data []byte) ([]byte, int) { return RewriteJSON(data, r) }
+
+	add := func(path, kind string, rewrite func([]byte) ([]byte, int), c Change) {
+		data, err := claude.For(c.Profile).ReadFile(path)
+		if err != nil {
+			return
+		}
+		out, n := rewrite(data)
+		if n == 0 {
+			return
+		}
+		c.Path, c.Kind, c.Count = path, kind, n
+		c.data, c.sum = out, sha256.Sum256(data)
+		changes = append(changes, c)
+	}
+
+	if opts.Sessions {
+		sessions, err := session.ListAllSessions()
+		if err != nil {
+			return nil, err
+		}
+		indexes := make(map[string]bool)
+		for _, s := range sessions {
+			if !matches(s.ProjectPath, opts.Project) {
+				continue
+			}
+			c := Change{SessionID: s.SessionID, Profile: s.Profile, Title: s.Title(), Live: s.Live}
+			add(s.FullPath, KindSession, jsonl, c)
+			for _, a := range s.Subagents {
+				add(a.FullPath, KindSubagent, jsonl, c)
+			}
+			idx := filepath.Join(claude.For(s.Profile).ProjectsDir(), s.Project, session.IndexFileName)
+			if !indexes[idx] {
+				indexes[idx] = true
+				add(idx, KindIndex, whole, Change{Profile: s.Profile, Title: s.ProjectPath})
+			}
+		}
+	}
+	if opts.Memories {
+		projects, err := memory.ListProjects()
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			if !matches(p.Path, opts.Project) {
+				continue
+			}
+			r := claude.For(p.Profile)
+			files, _ := r.Glob(filepath.Join(r.ProjectsDir(), p.DirName, "memory", "*.md"))
+			for _, f := range files {
+				add(f, KindMemory, text, Change{Profile: p.Profile, Title: p.Path})
+			}
+		}
+	}
+	if opts.Plans {
+		plans, err := plan.ListPlans()
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range plans {
+			if opts.Project != "" && !matches(p.ProjectHint, opts.Project) {
+				continue
+			}
+			add(p.FullPath, KindPlan, text, Change{Profile: p.Profile, Title: p.Title})
+		}
+	}
+	return changes, nil
+}
+
+func matches(path, term string) bool {
+	return strings.Contains(strings.ToLower(path), strings.ToLower(term))
+}
+
+// NewBackupDir returns a new directory under clsm's config directory to
+// back up files into before they are rewritten.
+func NewBackupDir() (string, error) {
+	dir, err := backupsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "redact-"+time.Now().Format("20060102-150405")), nil
+}
+
+// Apply writes the planned changes, first copying each original file into
+// backupDir under its full path. A file that changed since it was planned,
+// for example because Claude Code appended to it, is left alone.
+func Apply(changes []Change, backupDir string) []Result {
+	results := make([]Result, 0, len(changes))
+	for _, c := range changes {
+		r := Result{Path: c.Path}
+		if err := apply(c, backupDir); err != nil {
+			r.Error = err.Error()
+		} else {
+			r.Success = true
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func apply(c Change, backupDir string) error {
+	root := claude.For(c.Profile)
+	info, err := root.Stat(c.Path)
+	if err != nil {
+		return err
+	}
+	data, err := root.ReadFile(c.Path)
+	if err != nil {
+		return err
+	}
+	if sha256.Sum256(data) != c.sum {
+		return errors.New("changed since it was scanned; run again")
+	}
+
+	copied := filepath.Join(backupDir, filepath.FromSlash(strings.TrimPrefix(filepath.ToSlash(c.Path), "/")))
+	if err := os.MkdirAll(filepath.Dir(copied), 0o700); err != nil {
+		return fmt.Errorf("backing up: %w", err)
+	}
+	if err := atomicfile.WriteFile(copied, data, 0o600); err != nil {
+		return fmt.Errorf("backing up: %w", err)
+	}
+	if backup.IsIndex(c.Path) {
+		if err := backup.Save(root, c.Path); err != nil {
+			return err
+		}
+	}
+	if err := root.WriteFile(c.Path, c.data, info.Mode().Perm()); err != nil {
+		return err
+	}
+	kind := c.Kind
+	if kind == KindSubagent {
+		kind = audit.KindSession
+	}
+	audit.Record(audit.Entry{Op: audit.OpRedact, Kind: kind, ID: c.SessionID, Paths: []string{c.Path}, After: fmt.Sprintf("%d replacement(s)", c.Count), Backup: copied})
+	return nil
+}
@@ -0,0 +1,237 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/baz-sh/clsm/internal/secrets"
+)
+
+// DefaultReplacement is what matched text is replaced with unless told
+// otherwise.
+const DefaultReplacement = "[REDACTED]"
+
+// Replacer rewrites a piece of text and reports how many replacements it
+// made.
+type Replacer func(text string) (string, int)
+
+// Regexp returns a Replacer that replaces every match of re with
+// replacement, taken literally.
+func Regexp(re *regexp.Regexp, replacement string) Replacer {
+	return func(text string) (string, int) {
+		n := len(re.FindAllStringIndex(text, -1))
+		if n == 0 {
+			return text, 0
+		}
+		return re.ReplaceAllLiteralString(text, replacement), n
+	}
+}
+
+// Secrets returns a Replacer that replaces every secret the secret scanner
+// finds with replacement.
+func Secrets(replacement string) Replacer {
+	return func(text string) (string, int) {
+		matches := secrets.Find(text)
+		if len(matches) == 0 {
+			return text, 0
+		}
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			b.WriteString(text[last:m.Start])
+			b.WriteString(replacement)
+			last = m.End
+		}
+		b.WriteString(text[last:])
+		return b.String(), len(matches)
+	}
+}
+
+// Chain returns a Replacer that applies each of rs in turn.
+func Chain(rs ...Replacer) Replacer {
+	return func(text string) (string, int) {
+		var total int
+		for _, r := range rs {
+			var n int
+			text, n = r(text)
+			total += n
+		}
+		return text, total
+	}
+}
+
+// keepKeys are the fields whose string values are never rewritten: IDs
+// that link entries into a chain or pair tool calls with their results,
+// entry types, timestamps and the paths Claude Code resumes from.
+var keepKeys = map[string]bool{
+	"cwd":                     true,
+	"projectPath":             true,
+	"fullPath":                true,
+	"originalPath":            true,
+	"uuid":                    true,
+	"parentUuid":              true,
+	"logicalParentUuid":       true,
+	"leafUuid":                true,
+	"sessionId":               true,
+	"agentId":                 true,
+	"requestId":               true,
+	"messageId":               true,
+	"id":                      true,
+	"tool_use_id":             true,
+	"sourceToolAssistantUUID": true,
+	"type":                    true,
+	"role":                    true,
+	"timestamp":               true,
+	"created":                 true,
+	"modified":                true,
+	"version":                 true,
+}
+
+// PathKeys are the keepKeys that hold paths. RewriteJSON leaves them alone
+// so redacted sessions can still be resumed; a sanitized export rewrites
+// them with RewriteFields, as home directories must not leave the machine.
+var PathKeys = []string{"cwd", "projectPath", "fullPath", "originalPath"}
+
+// RewriteJSON applies r to the string values of one JSON document, such
+// as a line of a transcript, and returns the new document and the number
+// of replacements. Everything but the rewritten strings is copied byte for
+// byte, so field order, spacing and number formatting are kept, and the
+// values of keepKeys are left alone. Object keys are never rewritten.
+func RewriteJSON(data []byte, r Replacer) ([]byte, int) {
+	return rewriteStrings(data, func(key string) bool { return !keepKeys[key] }, r)
+}
+
+// RewriteFields is like RewriteJSON but rewrites only the string values of
+// the named fields, at any depth.
+func RewriteFields(data []byte, keys []string, r Replacer) ([]byte, int) {
+	return rewriteStrings(data, func(key string) bool { return slices.Contains(keys, key) }, r)
+}
+
+// rewriteStrings applies r to the string values whose key rewrite
+// accepts. Strings in arrays have the empty key.
+func rewriteStrings(data []byte, rewrite func(key string) bool, r Replacer) ([]byte, int) {
+	var out []byte
+	var total int
+	last := 0
+	key := ""
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '{', '}', '[', ']', ',':
+			key = ""
+			continue
+		case '"':
+		default:
+			continue
+		}
+		end := stringEnd(data, i)
+		if end < 0 {
+			break
+		}
+		lit := data[i:end]
+		if isKey(data, end) {
+			json.Unmarshal(lit, &key)
+			i = end - 1
+			continue
+		}
+		if rewrite(key) {
+			var s string
+			if json.Unmarshal(lit, &s) == nil {
+				if ns, n := r(s); n > 0 {
+					out = append(out, data[last:i]...)
+					out = append(out, encodeString(ns)...)
+					last = end
+					total += n
+				}
+			}
+		}
+		i = end - 1
+	}
+	if total == 0 {
+		return data, 0
+	}
+	return append(out, data[last:]...), total
+}
+
+// RewriteJSONL applies RewriteJSON to each line of a JSONL file. Lines that
+// are not JSON are rewritten as plain text.
+func RewriteJSONL(data []byte, r Replacer) ([]byte, int) {
+	return rewriteLines(data, r, func(line []byte) ([]byte, int) { return RewriteJSON(line, r) })
+}
+
+// RewriteFieldsJSONL applies RewriteFields to each line of a JSONL file.
+// Lines that are not JSON are left alone.
+func RewriteFieldsJSONL(data []byte, keys []string, r Replacer) ([]byte, int) {
+	return rewriteLines(data, nil, func(line []byte) ([]byte, int) { return RewriteFields(line, keys, r) })
+}
+
+// rewriteLines applies rewrite to each JSON line of data, and text, if not
+// nil, to the other lines.
+func rewriteLines(data []byte, text Replacer, rewrite func([]byte) ([]byte, int)) ([]byte, int) {
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	var out bytes.Buffer
+	var total int
+	for _, line := range lines {
+		body := bytes.TrimRight(line, "\r\n")
+		var n int
+		var nb []byte
+		switch {
+		case json.Valid(body):
+			nb, n = rewrite(body)
+		case text != nil:
+			var s string
+			s, n = text(string(body))
+			nb = []byte(s)
+		default:
+			nb = body
+		}
+		total += n
+		out.Write(nb)
+		out.Write(line[len(body):])
+	}
+	if total == 0 {
+		return data, 0
+	}
+	return out.Bytes(), total
+}
+
+// stringEnd returns the offset just past the JSON string literal that
+// starts at data[i], or -1 if it is not terminated.
+func stringEnd(data []byte, i int) int {
+	for j := i + 1; j < len(data); j++ {
+		switch data[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+	return -1
+}
+
+// isKey reports whether the string literal ending at offset end is an
+// object key, which is followed by a colon.
+func isKey(data []byte, end int) bool {
+	for j := end; j < len(data); j++ {
+		switch data[j] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case ':':
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+// encodeString returns s as a JSON string literal. Like Claude Code, it
+// leaves <, > and & unescaped.
+func encodeString(s string) []byte {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return bytes.TrimRight(b.Bytes(), "\n")
+}
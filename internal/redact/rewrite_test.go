@@ -0,0 +1,89 @@
+package redact
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestRewriteJSON(t *testing.T) {
+	r := Regexp(regexp.MustCompile(`secret`), "X")
+	tests := []struct {
+		name  string
+		in    string
+		want  string
+		count int
+	}{
+		{"plain value", `{"text":"a secret here"}`, `{"text":"a X here"}`, 1},
+		{"keys never rewritten", `{"secret":"secret"}`, `{"secret":"X"}`, 1},
+		{"keepKeys left alone", `{"uuid":"secret","sessionId":"secret","type":"secret","text":"secret"}`, `{"uuid":"secret","sessionId":"secret","type":"secret","text":"X"}`, 1},
+		{"paths left alone", `{"cwd":"/home/secret/app","projectPath":"/home/secret/app","fullPath":"/home/secret/a.jsonl","text":"/home/secret"}`, `{"cwd":"/home/secret/app","projectPath":"/home/secret/app","fullPath":"/home/secret/a.jsonl","text":"/home/X"}`, 1},
+		{"keepKeys at depth", `{"message":{"id":"secret","content":[{"type":"text","text":"secret"}]}}`, `{"message":{"id":"secret","content":[{"type":"text","text":"X"}]}}`, 1},
+		{"array strings rewritten", `{"id":["secret"]}`, `{"id":["X"]}`, 1},
+		{"spacing and numbers kept", `{ "n" : 1.50 , "text" : "secret" }`, `{ "n" : 1.50 , "text" : "X" }`, 1},
+		{"escaped quote", `{"text":"say \"secret\""}`, `{"text":"say \"X\""}`, 1},
+		{"html not escaped", `{"text":"<secret>&"}`, `{"text":"<X>&"}`, 1},
+		{"nothing to do", `{"text":"public"}`, `{"text":"public"}`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, n := RewriteJSON([]byte(tt.in), r)
+			if string(got) != tt.want || n != tt.count {
+				t.Errorf("got %s (%d), want %s (%d)", got, n, tt.want, tt.count)
+			}
+		})
+	}
+}
+
+func TestRewriteFields(t *testing.T) {
+	r := Regexp(regexp.MustCompile(`/old`), "/new")
+	tests := []struct {
+		name string
+		in   string
+		keys []string
+		want string
+	}{
+		{"named field only", `{"cwd":"/old/a","text":"/old/a"}`, []string{"cwd"}, `{"cwd":"/new/a","text":"/old/a"}`},
+		{"at depth", `{"x":{"cwd":"/old"}}`, []string{"cwd"}, `{"x":{"cwd":"/new"}}`},
+		{"keepKeys can be named", `{"sessionId":"/old"}`, []string{"sessionId"}, `{"sessionId":"/new"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := RewriteFields([]byte(tt.in), tt.keys, r); string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteJSONL(t *testing.T) {
+	r := Regexp(regexp.MustCompile(`secret`), "X")
+	tests := []struct {
+		name  string
+		in    string
+		want  string
+		count int
+	}{
+		{"lines", "{\"text\":\"secret\"}\n{\"uuid\":\"secret\"}\n", "{\"text\":\"X\"}\n{\"uuid\":\"secret\"}\n", 1},
+		{"no final newline", "{\"text\":\"secret\"}", "{\"text\":\"X\"}", 1},
+		{"crlf kept", "{\"text\":\"secret\"}\r\n", "{\"text\":\"X\"}\r\n", 1},
+		{"text line", "not json secret\n{\"type\":\"secret\"}\n", "not json X\n{\"type\":\"secret\"}\n", 1},
+		{"unchanged", "{\"uuid\":\"secret\"}\n", "{\"uuid\":\"secret\"}\n", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, n := RewriteJSONL([]byte(tt.in), r)
+			if string(got) != tt.want || n != tt.count {
+				t.Errorf("got %q (%d), want %q (%d)", got, n, tt.want, tt.count)
+			}
+		})
+	}
+}
+
+func TestRewriteFieldsJSONLLeavesText(t *testing.T) {
+	r := Regexp(regexp.MustCompile(`/old`), "/new")
+	in := "not json /old\n{\"cwd\":\"/old\"}\n"
+	want := "not json /old\n{\"cwd\":\"/new\"}\n"
+	if got, _ := RewriteFieldsJSONL([]byte(in), []string{"cwd"}, r); string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+package redact
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/baz-sh/clsm/internal/meta"
+)
+
+// Placeholders used when sanitizing.
+const (
+	HomePlaceholder = "~"
+	UserPlaceholder = "user"
+	HostPlaceholder = "host"
+)
+
+// genericNames are user and host names that identify no one and are
+// common words, so they are not replaced.
+var genericNames = map[string]bool{
+	"root": true, "user": true, "admin": true, "ubuntu": true, "vscode": true,
+	"node": true, "localhost": true, "host": true,
+}
+
+// Term is a piece of text to replace when sanitizing.
+type Term struct {
+	Text        string
+	Placeholder string
+}
+
+// ParseTerm parses "text" or "text=placeholder". Without a placeholder the
+// text is replaced with DefaultReplacement.
+func ParseTerm(s string) Term {
+	text, placeholder, ok := strings.Cut(s, "=")
+	if !ok || strings.TrimSpace(placeholder) == "" {
+		placeholder = DefaultReplacement
+	}
+	return Term{Text: strings.TrimSpace(text), Placeholder: strings.TrimSpace(placeholder)}
+}
+
+// TermsPath returns the path of the file that lists the terms to replace
+// when sanitizing.
+func TermsPath() (string, error) {
+	dir, err := meta.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "sanitize.txt"), nil
+}
+
+// LoadTerms reads the configured sanitize terms: one per line, in the form
+// ParseTerm accepts. Blank lines and lines starting with # are ignored. A
+// missing file has no terms.
+func LoadTerms() ([]Term, error) {
+	path, err := TermsPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var terms []Term
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if t := ParseTerm(line); t.Text != "" {
+			terms = append(terms, t)
+		}
+	}
+	return terms, scanner.Err()
+}
+
+// Sanitizer returns a Replacer for sharing a transcript outside the team.
+// It replaces the home directory with ~, each term with its placeholder,
+// and the host name and user name with "host" and "user". Terms match
+// anywhere, ignoring case; host and user names match as whole words.
+func Sanitizer(terms []Term) Replacer {
+	var rs []Replacer
+	if home, err := os.UserHomeDir(); err == nil && home != "/" && home != "" {
+		rs = append(rs, Regexp(regexp.MustCompile(regexp.QuoteMeta(home)+`\b`), HomePlaceholder))
+	}
+	for _, t := range terms {
+		rs = append(rs, Regexp(regexp.MustCompile(`(?i)`+regexp.QuoteMeta(t.Text)), t.Placeholder))
+	}
+	if host, err := os.Hostname(); err == nil {
+		short, _, _ := strings.Cut(host, ".")
+		for _, h := range []string{host, short} {
+			if r := wordReplacer(h, HostPlaceholder); r != nil {
+				rs = append(rs, r)
+			}
+		}
+	}
+	if u, err := user.Current(); err == nil {
+		if r := wordReplacer(u.Username, UserPlaceholder); r != nil {
+			rs = append(rs, r)
+		}
+	}
+	return Chain(rs...)
+}
+
+// wordReplacer replaces name as a whole word, ignoring case. It returns nil
+// for names too short or too generic to be worth replacing.
+func wordReplacer(name, placeholder string) Replacer {
+	if len(name) < 2 || genericNames[strings.ToLower(name)] {
+		return nil
+	}
+	return Regexp(regexp.MustCompile(`(?i)\b`+regexp.QuoteMeta(name)+`\b`), placeholder)
+}
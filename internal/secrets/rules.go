@@ -0,0 +1,214 @@
+package secrets
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rule describes one kind of secret.
+type Rule struct {
+	ID          string
+	Description string
+	Pattern     *regexp.Regexp // the first group, if any, is the secret itself
+
+	// MinEntropy is the Shannon entropy in bits per character the secret
+	// must reach. Rules for known token formats leave it at zero; the
+	// generic rules use it to skip words and placeholders.
+	MinEntropy float64
+}
+
+// Rules is the built-in ruleset, most specific first. When matches
+// overlap, the earlier rule wins.
+var Rules = []Rule{
+	{
+		ID:          "private-key",
+		Description: "Private key",
+		Pattern:     regexp.MustCompile(`-----BEGIN[A-Z ]{0,20} PRIVATE KEY(?: BLOCK)?-----\s*([A-Za-z0-9+/=\s]{32,})`),
+	},
+	{
+		ID:          "aws-access-key",
+		Description: "AWS access key ID",
+		Pattern:     regexp.MustCompile(`\b((?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA)[0-9A-Z]{16})\b`),
+	},
+	{
+		ID:          "aws-secret-key",
+		Description: "AWS secret access key",
+		Pattern:     regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+]{40})\b`),
+	},
+	{
+		ID:          "github-token",
+		Description: "GitHub token",
+		Pattern:     regexp.MustCompile(`\b((?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{60,255})\b`),
+	},
+	{
+		ID:          "gitlab-token",
+		Description: "GitLab token",
+		Pattern:     regexp.MustCompile(`\b(glpat-[A-Za-z0-9_-]{20,})\b`),
+	},
+	{
+		ID:          "anthropic-key",
+		Description: "Anthropic API key",
+		Pattern:     regexp.MustCompile(`\b(sk-ant-[a-z0-9]{2,10}-[A-Za-z0-9_-]{40,})`),
+	},
+	{
+		ID:          "openai-key",
+		Description: "OpenAI API key",
+		Pattern:     regexp.MustCompile(`\b(sk-(?:proj-|svcacct-|admin-)?[A-Za-z0-9_-]{20,}T3BlbkFJ[A-Za-z0-9_-]{20,}|sk-proj-[A-Za-z0-9_-]{40,})`),
+	},
+	{
+		ID:          "slack-token",
+		Description: "Slack token",
+		Pattern:     regexp.MustCompile(`\b(xox[abprse]-[A-Za-z0-9-]{10,})\b`),
+	},
+	{
+		ID:          "slack-webhook",
+		Description: "Slack webhook URL",
+		Pattern:     regexp.MustCompile(`https://hooks\.slack\.com/services/(T[A-Z0-9]+/B[A-Z0-9]+/[A-Za-z0-9]{20,})`),
+	},
+	{
+		ID:          "stripe-key",
+		Description: "Stripe secret key",
+		Pattern:     regexp.MustCompile(`\b((?:sk|rk)_live_[A-Za-z0-9]{20,})\b`),
+	},
+	{
+		ID:          "google-api-key",
+		Description: "Google API key",
+		Pattern:     regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})`),
+	},
+	{
+		ID:          "npm-token",
+		Description: "npm access token",
+		Pattern:     regexp.MustCompile(`\b(npm_[A-Za-z0-9]{36})\b`),
+	},
+	{
+		ID:          "jwt",
+		Description: "JSON Web Token",
+		Pattern:     regexp.MustCompile(`\b(eyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,})`),
+	},
+	{
+		ID:          "url-credentials",
+		Description: "Password in a URL",
+		Pattern:     regexp.MustCompile(`\b[a-z][a-z0-9+.-]{1,20}://[^\s:/@'"]+:([^\s:/@'"]{3,})@[^\s'"]+`),
+	},
+	{
+		ID:          "secret-assignment",
+		Description: "Secret assigned to a named variable",
+		Pattern:     regexp.MustCompile(`(?i)(?:api[_-]?key|apikey|secret|token|passw(?:or)?d|pwd|credentials?|auth)[a-z0-9_-]*["']?\s*[:=]\s*["']?([A-Za-z0-9+/=_.~!@#$%^&*-]{12,})`),
+		MinEntropy:  3.0,
+	},
+	{
+		ID:          "env-value",
+		Description: "High-entropy value in an env file",
+		Pattern:     regexp.MustCompile(`(?m)^\s*(?:export\s+)?[A-Z][A-Z0-9_]{2,}\s*=\s*["']?([A-Za-z0-9+/=_.-]{20,})`),
+		MinEntropy:  3.5,
+	},
+}
+
+// placeholders are substrings of values that are examples rather than
+// real secrets.
+var placeholders = []string{
+	"example", "xxxx", "****", "your", "changeme", "placeholder", "dummy",
+	"redacted", "<", "${", "{{", "$(", "0000000000",
+}
+
+// Match is a secret found in a piece of text.
+type Match struct {
+	Rule       *Rule
+	Start, End int // byte offsets of the secret in the text
+	Secret     string
+}
+
+// Find returns the secrets in text, in order of position.
+func Find(text string) []Match {
+	var matches []Match
+	for i := range Rules {
+		r := &Rules[i]
+		for _, loc := range r.Pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			secret := strings.TrimRight(text[start:end], "\r\n\t ")
+			end = start + len(secret)
+			if !plausible(r, secret) || overlaps(matches, start, end) {
+				continue
+			}
+			matches = append(matches, Match{Rule: r, Start: start, End: end, Secret: secret})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+	return matches
+}
+
+// plausible reports whether a candidate looks like a real secret for the
+// rule rather than an example value.
+func plausible(r *Rule, secret string) bool {
+	lower := strings.ToLower(secret)
+	for _, p := range placeholders {
+		if strings.Contains(lower, p) {
+			return false
+		}
+	}
+	if r.MinEntropy == 0 {
+		return true
+	}
+	return hasLetterAndDigit(secret) && Entropy(secret) >= r.MinEntropy
+}
+
+func overlaps(matches []Match, start, end int) bool {
+	for _, m := range matches {
+		if start < m.End && m.Start < end {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLetterAndDigit(s string) bool {
+	var letter, digit bool
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digit = true
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+			letter = true
+		}
+	}
+	return letter && digit
+}
+
+// Entropy returns the Shannon entropy of s in bits per byte.
+func Entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	n := float64(len(s))
+	var h float64
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / n
+			h -= p * math.Log2(p)
+		}
+	}
+	return h
+}
+
+// Mask hides all but the first four and last two characters of a secret,
+// enough to recognise it without revealing it. Short secrets keep only
+// their first character.
+func Mask(secret string) string {
+	secret = strings.Join(strings.Fields(secret), "")
+	n := len(secret)
+	if n <= 10 {
+		return secret[:min(n, 1)] + strings.Repeat("*", 6)
+	}
+	return secret[:4] + strings.Repeat("*", min(n-6, 12)) + secret[n-2:]
+}
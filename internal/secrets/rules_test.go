@@ -0,0 +1,50 @@
+package secrets
+
+import "testing"
+
+// Made-up secrets, built up so the file itself doesn't look like it holds
+// any.
+const (
+	chars36 = "aB3dE5fG7hJ9kL1mN2pQ4rS6tU8vW0xY2zA4"
+	chars40 = chars36 + "cD6e"
+	ghToken = "ghp_" + chars36
+	antKey  = "sk-ant-api03-" + chars40
+	awsID   = "AKIA" + "Q3EGRZ7XK5WB2NMY"
+)
+
+func TestFind(t *testing.T) {
+	type hit struct{ rule, secret string }
+	tests := []struct {
+		name string
+		text string
+		want []hit
+	}{
+		{"known format", "token " + ghToken, []hit{{"github-token", ghToken}}},
+		{"known format beats assignment", "GITHUB_TOKEN=" + ghToken, []hit{{"github-token", ghToken}}},
+		{"known format beats env value", "export ANTHROPIC_API_KEY=" + antKey, []hit{{"anthropic-key", antKey}}},
+		{"assignment beats env value", "DB_PASSWORD=" + chars36, []hit{{"secret-assignment", chars36}}},
+		{"env value", "SOME_SETTING=" + chars36, []hit{{"env-value", chars36}}},
+		{"url credentials", "postgres://app:" + "s3cr3tPw@db.internal/app", []hit{{"url-credentials", "s3cr3tPw"}}},
+		{"in order of position", "id " + awsID + " then " + ghToken, []hit{{"aws-access-key", awsID}, {"github-token", ghToken}}},
+		{"placeholder", "api_key=your_api_key_here_123", nil},
+		{"low entropy", "password=aaaaaaaaaaaa1", nil},
+		{"no digits", "token=abcdefghijklmnopqrstuv", nil},
+		{"plain text", "nothing to see here", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Find(tt.text)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Find(%q) found %d, want %d: %+v", tt.text, len(got), len(tt.want), got)
+			}
+			for i, m := range got {
+				if m.Rule.ID != tt.want[i].rule || m.Secret != tt.want[i].secret {
+					t.Errorf("match %d = %s %q, want %s %q", i, m.Rule.ID, m.Secret, tt.want[i].rule, tt.want[i].secret)
+				}
+				if tt.text[m.Start:m.End] != m.Secret {
+					t.Errorf("match %d offsets give %q, want %q", i, tt.text[m.Start:m.End], m.Secret)
+				}
+			}
+		})
+	}
+}
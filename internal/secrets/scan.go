@@ -0,0 +1,343 @@
+// Package secrets finds tokens, keys and passwords in the files Claude
+// Code keeps under ~/.claude: session transcripts, memories and plans.
+// Scanning is local and uses a fixed ruleset of known token formats plus
+// entropy checks for generic assignments.
+package secrets
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/memory"
+	"github.com/baz-sh/clsm/internal/plan"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+// Sources that can be scanned.
+const (
+	SourceSession  = "session"
+	SourceSubagent = "subagent"
+	SourceMemory   = "memory"
+	SourcePlan     = "plan"
+)
+
+// snippetWidth is how much text is kept on each side of a hit in a
+// finding's snippet.
+const snippetWidth = 50
+
+// contextLines is how many lines around a hit are kept in its context.
+const contextLines = 3
+
+// Finding is one secret at one location.
+type Finding struct {
+	Rule         string    `json:"rule"`
+	Description  string    `json:"description"`
+	Source       string    `json:"source"` // one of the Source constants
+	Path         string    `json:"path"`
+	Line         int       `json:"line"` // 1-based line in the file
+	SessionID    string    `json:"sessionId,omitempty"`
+	SessionTitle string    `json:"sessionTitle,omitempty"`
+	ProjectPath  string    `json:"projectPath,omitempty"`
+	Time         time.Time `json:"time,omitzero"` // timestamp of the transcript entry
+	Masked       string    `json:"masked"`
+	Fingerprint  string    `json:"fingerprint"` // the same secret has the same fingerprint everywhere
+	Snippet      string    `json:"snippet"`     // the hit's line around it, secrets masked
+	Context      string    `json:"context"`     // the lines around the hit, secrets masked
+}
+
+// Options selects what Scan looks at.
+type Options struct {
+	Project  string // only projects whose path contains this term
+	Sessions bool
+	Memories bool
+	Plans    bool
+}
+
+// Scan looks for secrets in the selected sources. Findings are returned
+// file by file, sessions first and newest first, then memories and plans.
+func Scan(opts Options) ([]Finding, error) {
+	var findings []Finding
+	if opts.Sessions {
+		sessions, err := session.ListAllSessions()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range sessions {
+			if !matches(s.ProjectPath, opts.Project) {
+				continue
+			}
+			findings = append(findings, scanSession(s)...)
+		}
+	}
+	if opts.Memories {
+		projects, err := memory.ListProjects()
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			if !matches(p.Path, opts.Project) {
+				continue
+			}
+			memories, _ := memory.ListMemories(p.Profile, p.DirName)
+			for _, m := range memories {
+				findings = append(findings, scanText(claude.For(m.Profile), m.FullPath, Finding{
+					Source:      SourceMemory,
+					ProjectPath: p.Path,
+				})...)
+			}
+		}
+	}
+	if opts.Plans {
+		plans, err := plan.ListPlans()
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range plans {
+			if opts.Project != "" && !matches(p.ProjectHint, opts.Project) {
+				continue
+			}
+			findings = append(findings, scanText(claude.For(p.Profile), p.FullPath, Finding{
+				Source:      SourcePlan,
+				ProjectPath: p.ProjectHint,
+			})...)
+		}
+	}
+	return findings, nil
+}
+
+func matches(path, term string) bool {
+	return strings.Contains(strings.ToLower(path), strings.ToLower(term))
+}
+
+// scanSession scans a session's transcript and its subagents' transcripts.
+func scanSession(s session.Session) []Finding {
+	base := Finding{
+		Source:       SourceSession,
+		SessionID:    s.SessionID,
+		SessionTitle: s.Title(),
+		ProjectPath:  s.ProjectPath,
+	}
+	root := claude.For(s.Profile)
+	findings := ScanTranscript(root, s.FullPath, base)
+	for _, a := range s.Subagents {
+		base.Source = SourceSubagent
+		findings = append(findings, ScanTranscript(root, a.FullPath, base)...)
+	}
+	return findings
+}
+
+// ScanTranscript scans the string values of each JSONL entry in a
+// transcript. Fields of base other than the location and the secret are
+// copied into every finding. path is read through root.
+func ScanTranscript(root *claude.Root, path string, base Finding) []Finding {
+	f, err := root.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var findings []Finding
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var entry any
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			continue
+		}
+		var ts time.Time
+		if obj, ok := entry.(map[string]any); ok {
+			if s, ok := obj["timestamp"].(string); ok {
+				ts, _ = time.Parse(time.RFC3339Nano, s)
+			}
+		}
+		// Claude Code often stores a tool's output twice in one entry, so
+		// each secret is reported once per line.
+		seen := make(map[string]bool)
+		walkStrings(entry, func(text string) {
+			for _, fd := range findIn(text) {
+				if seen[fd.Fingerprint] {
+					continue
+				}
+				seen[fd.Fingerprint] = true
+				fd.copyFrom(base)
+				fd.Path = path
+				fd.Line = line
+				fd.Time = ts
+				findings = append(findings, fd)
+			}
+		})
+	}
+	return findings
+}
+
+// scanText scans a plain text file such as a memory or a plan.
+func scanText(root *claude.Root, path string, base Finding) []Finding {
+	data, err := root.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	text := string(data)
+	var findings []Finding
+	for _, fd := range findIn(text) {
+		fd.copyFrom(base)
+		fd.Path = path
+		findings = append(findings, fd)
+	}
+	return findings
+}
+
+// findIn returns a finding for each secret in text. Line holds the 1-based
+// line of the hit within text.
+func findIn(text string) []Finding {
+	found := Find(text)
+	if len(found) == 0 {
+		return nil
+	}
+	masked, starts := maskText(text, found)
+	findings := make([]Finding, len(found))
+	for i, m := range found {
+		sum := sha256.Sum256([]byte(m.Secret))
+		findings[i] = Finding{
+			Rule:        m.Rule.ID,
+			Description: m.Rule.Description,
+			Line:        strings.Count(text[:m.Start], "\n") + 1,
+			Masked:      Mask(m.Secret),
+			Fingerprint: hex.EncodeToString(sum[:])[:12],
+			Snippet:     snippet(masked, starts[i], len(Mask(m.Secret))),
+			Context:     context(masked, starts[i], len(Mask(m.Secret))),
+		}
+	}
+	return findings
+}
+
+// copyFrom fills in the source, session and project fields from base.
+func (f *Finding) copyFrom(base Finding) {
+	f.Source = base.Source
+	f.SessionID = base.SessionID
+	f.SessionTitle = base.SessionTitle
+	f.ProjectPath = base.ProjectPath
+}
+
+// walkStrings calls fn with every string value in a decoded JSON value.
+// Object fields are visited in key order so findings come out the same way
+// each time. Base64 payloads such as pasted images are skipped.
+func walkStrings(v any, fn func(string)) {
+	switch v := v.(type) {
+	case string:
+		fn(v)
+	case []any:
+		for _, e := range v {
+			walkStrings(e, fn)
+		}
+	case map[string]any:
+		if v["type"] == "base64" {
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			walkStrings(v[k], fn)
+		}
+	}
+}
+
+// maskText replaces each match in text with its mask and returns the new
+// text and the new start offset of each match.
+func maskText(text string, matches []Match) (string, []int) {
+	var b strings.Builder
+	starts := make([]int, len(matches))
+	last := 0
+	for i, m := range matches {
+		b.WriteString(text[last:m.Start])
+		starts[i] = b.Len()
+		b.WriteString(Mask(m.Secret))
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	return b.String(), starts
+}
+
+// snippet returns the line of text holding [start, start+n), cut to
+// snippetWidth bytes on each side of it.
+func snippet(text string, start, n int) string {
+	lineStart, lineEnd := lineBounds(text, start)
+	from, to := max(lineStart, start-snippetWidth), min(lineEnd, start+n+snippetWidth)
+	for from > lineStart && !utf8.RuneStart(text[from]) {
+		from--
+	}
+	for to < lineEnd && !utf8.RuneStart(text[to]) {
+		to++
+	}
+	s := strings.TrimSpace(text[from:to])
+	if from > lineStart {
+		s = "…" + s
+	}
+	if to < lineEnd {
+		s += "…"
+	}
+	return s
+}
+
+// context returns the snippet of [start, start+n) with up to contextLines
+// lines of text on each side, each cut to fit a terminal.
+func context(text string, start, n int) string {
+	lineStart, lineEnd := lineBounds(text, start)
+	var lines []string
+	if lineStart > 0 {
+		before := strings.Split(text[:lineStart-1], "\n")
+		for _, l := range before[max(0, len(before)-contextLines):] {
+			lines = append(lines, cutLine(l))
+		}
+	}
+	lines = append(lines, snippet(text, start, n))
+	if lineEnd < len(text) {
+		after := strings.Split(text[lineEnd+1:], "\n")
+		for _, l := range after[:min(len(after), contextLines)] {
+			lines = append(lines, cutLine(l))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lineBounds returns the start and end offsets of the line holding offset
+// i.
+func lineBounds(text string, i int) (start, end int) {
+	start = strings.LastIndexByte(text[:i], '\n') + 1
+	end = len(text)
+	if j := strings.IndexByte(text[i:], '\n'); j >= 0 {
+		end = i + j
+	}
+	return start, end
+}
+
+// cutLine shortens a context line to 2*snippetWidth bytes.
+func cutLine(line string) string {
+	line = strings.TrimRight(line, "\r")
+	if len(line) <= 2*snippetWidth {
+		return line
+	}
+	to := 2 * snippetWidth
+	for to > 0 && !utf8.RuneStart(line[to]) {
+		to--
+	}
+	return line[:to] + "…"
+}
+
+// Distinct returns the number of different secrets among the findings.
+func Distinct(findings []Finding) int {
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		seen[f.Fingerprint] = true
+	}
+	return len(seen)
+}
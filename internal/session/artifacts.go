@@ -0,0 +1,482 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+// Besides its transcript and its subagents', Claude Code keeps data for a
+// session elsewhere in its data directory, named by the session ID:
+//
+//	todos/<id>-agent-<agent id>.json  the todo list of the session and each agent
+//	file-history/<id>/                files as they were before each edit, for rewinding
+//	session-env/<id>/                 environment set up by hooks
+//	debug/<id>.txt                    the debug log
+//	projects/<project>/<id>/          tool results too big for the transcript
+//
+// Shell snapshots, shell-snapshots/snapshot-<shell>-<time>-<random>.sh,
+// aren't named by session. A snapshot belongs to the sessions whose
+// transcript or debug log names it.
+
+// Kinds of artifact.
+const (
+	ArtifactTodos         = "todos"
+	ArtifactFileHistory   = "file history"
+	ArtifactSessionEnv    = "session env"
+	ArtifactDebugLog      = "debug log"
+	ArtifactToolResults   = "tool results"
+	ArtifactShellSnapshot = "shell snapshot"
+	ArtifactSubagents     = "subagents" // only from Orphans; Delete removes a session's own
+)
+
+// SweepGrace is how long an artifact is left alone by Orphans after it
+// was last written, since a session that has just started may not have
+// written its transcript yet.
+const SweepGrace = 24 * time.Hour
+
+// Artifact is a file or directory Claude Code keeps for a session outside
+// its transcripts.
+type Artifact struct {
+	Kind      string
+	SessionID string
+	Path      string
+	Profile   string    // profile of the data directory it is in; empty without profiles
+	Size      int64     // for a directory, of all the files in it
+	Modified  time.Time // for a directory, of the newest file in it
+}
+
+// artifactPatterns are where the artifacts named by session ID are, as
+// glob patterns under the data directory with %s for the ID.
+var artifactPatterns = []struct{ kind, pattern string }{
+	{ArtifactTodos, "todos/%s-agent-*.json"},
+	{ArtifactFileHistory, "file-history/%s"},
+	{ArtifactSessionEnv, "session-env/%s"},
+	{ArtifactDebugLog, "debug/%s.txt"},
+}
+
+var snapshotName = regexp.MustCompile(`snapshot-[a-z]+-[0-9]+-[a-z0-9]+\.sh`)
+
+// Artifacts returns what Claude Code keeps for a session besides its
+// transcript and subagents, which Delete removes along with it. A shell
+// snapshot is included only if no other transcript or debug log in the
+// data directory names it, as Orphans decides, so one a running session
+// in another project sources is kept.
+func Artifacts(s Session) []Artifact {
+	r := claude.For(s.Profile)
+	var out []Artifact
+	add := func(kind, path string) {
+		if a, ok := statArtifact(r, kind, s.SessionID, path); ok {
+			out = append(out, a)
+		}
+	}
+	for _, p := range artifactPatterns {
+		matches, _ := r.Glob(filepath.Join(r.Dir, fmt.Sprintf(p.pattern, s.SessionID)))
+		for _, m := range matches {
+			add(p.kind, m)
+		}
+	}
+	add(ArtifactToolResults, filepath.Join(filepath.Dir(s.FullPath), s.SessionID))
+
+	own := []string{s.FullPath, filepath.Join(r.Dir, "debug", s.SessionID+".txt")}
+	for _, a := range s.Subagents {
+		own = append(own, a.FullPath)
+	}
+	names := snapshotNames(r, own...)
+	if len(names) > 0 {
+		var others []string
+		for _, f := range snapshotSources(r) {
+			if !slices.Contains(own, f) {
+				others = append(others, f)
+			}
+		}
+		for name := range snapshotNames(r, others...) {
+			delete(names, name)
+		}
+	}
+	for name := range names {
+		add(ArtifactShellSnapshot, filepath.Join(r.Dir, "shell-snapshots", name))
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+// statArtifact describes the artifact at path, or returns false if there
+// is nothing there. A session's tool results directory doesn't count its
+// subagents, which are listed on their own.
+func statArtifact(r *claude.Root, kind, id, path string) (Artifact, bool) {
+	info, err := r.Stat(path)
+	if err != nil {
+		return Artifact{}, false
+	}
+	a := Artifact{Kind: kind, SessionID: id, Path: path, Profile: r.Name, Size: info.Size(), Modified: info.ModTime()}
+	if !info.IsDir() {
+		return a, true
+	}
+	a.Size = 0
+	var files int
+	walkFiles(r, path, func(p string, info fs.FileInfo) {
+		if kind == ArtifactToolResults && strings.HasPrefix(p, filepath.Join(path, "subagents")+string(filepath.Separator)) {
+			return
+		}
+		files++
+		a.Size += info.Size()
+		if info.ModTime().After(a.Modified) {
+			a.Modified = info.ModTime()
+		}
+	})
+	if kind == ArtifactToolResults && files == 0 {
+		return Artifact{}, false
+	}
+	return a, true
+}
+
+// walkFiles calls fn for each file under dir, in no particular order.
+func walkFiles(r *claude.Root, dir string, fn func(path string, info fs.FileInfo)) {
+	entries, err := r.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		p := filepath.Join(dir, e.Name())
+		if e.IsDir() {
+			walkFiles(r, p, fn)
+			continue
+		}
+		if info, err := e.Info(); err == nil {
+			fn(p, info)
+		}
+	}
+}
+
+// snapshotNames returns the shell snapshot file names mentioned in files.
+func snapshotNames(r *claude.Root, files ...string) map[string]bool {
+	names := make(map[string]bool)
+	for _, f := range files {
+		data, err := r.ReadFile(f)
+		if err != nil || !bytes.Contains(data, []byte("snapshot-")) {
+			continue
+		}
+		for _, m := range snapshotName.FindAll(data, -1) {
+			names[string(m)] = true
+		}
+	}
+	return names
+}
+
+// snapshotSources returns the files in r that may name shell snapshots:
+// every transcript, subagent transcript and debug log.
+func snapshotSources(r *claude.Root) []string {
+	var files []string
+	for _, pattern := range []string{
+		filepath.Join(r.ProjectsDir(), "*", "*.jsonl"),
+		filepath.Join(r.ProjectsDir(), "*", "*", "subagents", "*.jsonl"),
+		filepath.Join(r.Dir, "debug", "*.txt"),
+	} {
+		matches, _ := r.Glob(pattern)
+		files = append(files, matches...)
+	}
+	return files
+}
+
+// removeArtifacts removes artifacts, going on past failures, and returns
+// the first error.
+func removeArtifacts(artifacts []Artifact) error {
+	var first error
+	for _, a := range artifacts {
+		r := claude.For(a.Profile)
+		var err error
+		switch a.Kind {
+		case ArtifactToolResults:
+			// Subagents in there were removed with the session, or are
+			// left for removeSubagents to report on.
+			err = removeTree(r, a.Path, filepath.Join(a.Path, "subagents"))
+		case ArtifactSubagents:
+			if err = removeTree(r, a.Path, ""); err == nil {
+				r.Remove(filepath.Dir(a.Path))
+			}
+		default:
+			err = removeTree(r, a.Path, "")
+		}
+		if err != nil && first == nil {
+			first = fmt.Errorf("removing %s: %w", a.Kind, err)
+		}
+	}
+	return first
+}
+
+// removeTree removes path and, if it is a directory, everything in it
+// except keep.
+func removeTree(r *claude.Root, path, keep string) error {
+	if path == keep {
+		return nil
+	}
+	info, err := r.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		entries, err := r.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := removeTree(r, filepath.Join(path, e.Name()), keep); err != nil {
+				return err
+			}
+		}
+		if keep != "" && strings.HasPrefix(keep, path+string(filepath.Separator)) {
+			if _, err := r.Stat(keep); err == nil {
+				return nil
+			}
+		}
+	}
+	if err := r.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// copyArtifacts copies a session's artifacts into the same places in dst,
+// keeping modification times. A file dst already has at least as new is
+// left alone.
+func copyArtifacts(artifacts []Artifact, dst *claude.Root) error {
+	for _, a := range artifacts {
+		src := claude.For(a.Profile)
+		var err error
+		walkOrStat(src, a.Path, func(p string, info fs.FileInfo) {
+			if err != nil || (a.Kind == ArtifactToolResults && strings.HasPrefix(p, filepath.Join(a.Path, "subagents")+string(filepath.Separator))) {
+				return
+			}
+			var rel string
+			if rel, err = filepath.Rel(src.Dir, p); err != nil {
+				return
+			}
+			to := filepath.Join(dst.Dir, rel)
+			if have, serr := dst.Stat(to); serr == nil && !have.ModTime().Before(info.ModTime()) {
+				return
+			}
+			err = copyFile(src, p, dst, to)
+		})
+		if err != nil {
+			return fmt.Errorf("copying %s: %w", a.Kind, err)
+		}
+	}
+	return nil
+}
+
+// walkOrStat calls fn for path if it is a file, or for each file under it
+// if it is a directory.
+func walkOrStat(r *claude.Root, path string, fn func(path string, info fs.FileInfo)) {
+	info, err := r.Stat(path)
+	if err != nil {
+		return
+	}
+	if info.IsDir() {
+		walkFiles(r, path, fn)
+		return
+	}
+	fn(path, info)
+}
+
+// Orphans returns the artifacts in the active data directories whose
+// session no longer exists: no transcript has its ID, or, for a shell
+// snapshot, no transcript or debug log names it. Artifacts written in the
+// last SweepGrace, and those of sessions Claude Code lists as running,
+// are left out.
+func Orphans() ([]Artifact, error) {
+	var out []Artifact
+	now := time.Now()
+	for _, r := range claude.Roots() {
+		known, err := transcriptIDs(r)
+		if err != nil {
+			return nil, err
+		}
+		running := runningSessions(r)
+		add := func(kind, id, path string) {
+			if known[id] || running[id] {
+				return
+			}
+			if a, ok := statArtifact(r, kind, id, path); ok && now.Sub(a.Modified) > SweepGrace {
+				out = append(out, a)
+			}
+		}
+
+		for _, p := range artifactPatterns {
+			matches, _ := r.Glob(filepath.Join(r.Dir, fmt.Sprintf(p.pattern, "*")))
+			for _, m := range matches {
+				if id := artifactSessionID(m); IsSessionID(id) {
+					add(p.kind, id, m)
+				}
+			}
+		}
+		dirs, _ := r.Glob(filepath.Join(r.ProjectsDir(), "*", "*"))
+		for _, d := range dirs {
+			if id := filepath.Base(d); IsSessionID(id) {
+				add(ArtifactToolResults, id, d)
+				add(ArtifactSubagents, id, filepath.Join(d, "subagents"))
+			}
+		}
+
+		snapshots, _ := r.Glob(filepath.Join(r.Dir, "shell-snapshots", "snapshot-*.sh"))
+		if len(snapshots) == 0 {
+			continue
+		}
+		named := snapshotNames(r, snapshotSources(r)...)
+		for _, s := range snapshots {
+			if !named[filepath.Base(s)] {
+				add(ArtifactShellSnapshot, "", s)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out, nil
+}
+
+// Sweep removes artifacts found by Orphans and records them in the audit
+// log.
+func Sweep(artifacts []Artifact) []DeleteResult {
+	results := make([]DeleteResult, 0, len(artifacts))
+	for _, a := range artifacts {
+		r := DeleteResult{SessionID: a.SessionID, Profile: a.Profile, Success: true}
+		if err := removeArtifacts([]Artifact{a}); err != nil {
+			r.Success = false
+			r.Error = err.Error()
+		} else {
+			audit.Record(audit.Entry{Op: audit.OpSweep, Kind: audit.KindArtifact, ID: a.SessionID, Paths: []string{a.Path}, Before: a.Kind})
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// artifactSessionID returns the session ID an artifact found by one of
+// artifactPatterns is named by.
+func artifactSessionID(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if len(name) > 36 {
+		name = name[:36]
+	}
+	return name
+}
+
+// IsSessionID reports whether name has the form of a session ID, a
+// lowercase UUID.
+func IsSessionID(name string) bool {
+	if len(name) != 36 {
+		return false
+	}
+	for i, c := range name {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdef", c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// transcriptIDs returns the IDs of the sessions with a transcript in r,
+// including sidechain transcripts' parents.
+func transcriptIDs(r *claude.Root) (map[string]bool, error) {
+	ids := make(map[string]bool)
+	entries, err := r.ReadDir(r.ProjectsDir())
+	if errors.Is(err, fs.ErrNotExist) {
+		return ids, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading projects dir: %w", err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		projPath := filepath.Join(r.ProjectsDir(), e.Name())
+		files, _ := r.Glob(filepath.Join(projPath, "*.jsonl"))
+		for _, f := range files {
+			if !isAgentFile(f) {
+				ids[strings.TrimSuffix(filepath.Base(f), ".jsonl")] = true
+			}
+		}
+	}
+	return ids, nil
+}
+
+// runningSessions returns the IDs of the sessions Claude Code lists in
+// sessions/<pid>.json for processes that are still running.
+func runningSessions(r *claude.Root) map[string]bool {
+	ids := make(map[string]bool)
+	files, _ := r.Glob(filepath.Join(r.Dir, "sessions", "*.json"))
+	for _, f := range files {
+		data, err := r.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		var proc struct {
+			PID       int    `json:"pid"`
+			SessionID string `json:"sessionId"`
+		}
+		if json.Unmarshal(data, &proc) != nil || proc.SessionID == "" {
+			continue
+		}
+		if proc.PID == 0 || processExists(proc.PID) {
+			ids[proc.SessionID] = true
+		}
+	}
+	return ids
+}
+
+// DescribeArtifacts sums up artifacts in a line, by kind with a count
+// where there is more than one, such as "todos (2), file history, debug
+// log", and returns their total size.
+func DescribeArtifacts(artifacts []Artifact) (string, int64) {
+	var kinds []string
+	counts := make(map[string]int)
+	var size int64
+	for _, a := range artifacts {
+		if counts[a.Kind] == 0 {
+			kinds = append(kinds, a.Kind)
+		}
+		counts[a.Kind]++
+		size += a.Size
+	}
+	for i, k := range kinds {
+		if n := counts[k]; n > 1 {
+			kinds[i] = fmt.Sprintf("%s (%d)", k, n)
+		}
+	}
+	return strings.Join(kinds, ", "), size
+}
+
+// DescribeSessionArtifacts sums up with DescribeArtifacts, for each session
+// that has any, the artifacts Delete would remove with it, keyed by
+// Session.Key. formatSize writes their total size.
+func DescribeSessionArtifacts(sessions []Session, formatSize func(int64) string) map[string]string {
+	also := make(map[string]string)
+	for _, s := range sessions {
+		if kinds, size := DescribeArtifacts(Artifacts(s)); kinds != "" {
+			also[s.Key()] = kinds + ", " + formatSize(size)
+		}
+	}
+	return also
+}
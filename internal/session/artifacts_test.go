@@ -0,0 +1,50 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+func TestArtifactsSharedSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	claude.Use(claude.New(dir))
+	t.Cleanup(func() { claude.UseAll(nil) })
+
+	const (
+		id    = "11111111-1111-4111-8111-111111111111"
+		other = "22222222-2222-4222-8222-222222222222"
+		own   = "snapshot-bash-1700000000000-aaaaaa.sh"
+		inB   = "snapshot-bash-1700000000001-bbbbbb.sh"
+		inLog = "snapshot-bash-1700000000002-cccccc.sh"
+	)
+	transcript := filepath.Join(dir, "projects", "-a", id+".jsonl")
+	files := map[string]string{
+		transcript: `{"text":"source ` + own + ` ` + inB + ` ` + inLog + `"}`,
+		filepath.Join(dir, "projects", "-b", other+".jsonl"): `{"text":"source ` + inB + `"}`,
+		filepath.Join(dir, "debug", other+".txt"):            "sourcing " + inLog,
+		filepath.Join(dir, "shell-snapshots", own):           "",
+		filepath.Join(dir, "shell-snapshots", inB):           "",
+		filepath.Join(dir, "shell-snapshots", inLog):         "",
+	}
+	for p, data := range files {
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	for _, a := range Artifacts(Session{SessionID: id, FullPath: transcript}) {
+		if a.Kind == ArtifactShellSnapshot {
+			got = append(got, filepath.Base(a.Path))
+		}
+	}
+	if len(got) != 1 || got[0] != own {
+		t.Errorf("snapshots = %v, want only %s", got, own)
+	}
+}
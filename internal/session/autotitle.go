@@ -0,0 +1,186 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+// maxTitleLen is the longest title SuggestTitle returns, in runes.
+const maxTitleLen = 60
+
+// Title sources reported by SuggestTitle.
+const (
+	TitleFromSummary = "summary"
+	TitleFromPrompt  = "prompt"
+	TitleFromFiles   = "files"
+)
+
+// boilerplatePrefixes start user messages that Claude Code writes itself
+// rather than the person at the keyboard: slash command output, caveats
+// and interruptions.
+var boilerplatePrefixes = []string{
+	"Caveat:",
+	"<local-command-stdout>",
+	"<local-command-stderr>",
+	"<command-message>",
+	"<system-reminder>",
+	"<user-prompt-submit-hook>",
+	"[Request interrupted",
+	"This session is being continued from a previous conversation",
+}
+
+var (
+	commandNameRe = regexp.MustCompile(`<command-name>\s*(.*?)\s*</command-name>`)
+	commandArgsRe = regexp.MustCompile(`(?s)<command-args>\s*(.*?)\s*</command-args>`)
+)
+
+// SuggestTitle derives a title for a session from its transcript. The
+// last summary Claude Code wrote is preferred, then the first prompt typed
+// by a person; either is followed by the files the session edited most.
+// A session with neither is named after its files alone. source is one of
+// the TitleFrom constants, or empty if nothing could be derived.
+func SuggestTitle(s Session) (title, source string) {
+	summary, prompt, files := scanForTitle(s.FullPath)
+	switch {
+	case summary != "":
+		title, source = summary, TitleFromSummary
+	case prompt != "":
+		title, source = prompt, TitleFromPrompt
+	case len(files) > 0:
+		return "Edit " + strings.Join(files, ", "), TitleFromFiles
+	default:
+		return "", ""
+	}
+	title = shortenTitle(title, maxTitleLen)
+	if len(files) > 0 {
+		if withFiles := title + " (" + strings.Join(files, ", ") + ")"; len([]rune(withFiles)) <= maxTitleLen+24 {
+			title = withFiles
+		} else if withFile := title + " (" + files[0] + ")"; len([]rune(withFile)) <= maxTitleLen+24 {
+			title = withFile
+		}
+	}
+	return title, source
+}
+
+// scanForTitle reads what SuggestTitle needs from a transcript: the last
+// summary, the first human prompt and the names of the two files edited
+// most often.
+func scanForTitle(path string) (summary, prompt string, files []string) {
+	f, err := claude.RootOf(path).Open(path)
+	if err != nil {
+		return "", "", nil
+	}
+	defer f.Close()
+
+	edits := make(map[string]int)
+	var order []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var line struct {
+			transcriptLine
+			IsMeta bool `json:"isMeta"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		switch line.Type {
+		case "summary":
+			if line.Summary != "" {
+				summary = line.Summary
+			}
+		case "user":
+			if prompt != "" || line.IsMeta {
+				continue
+			}
+			for _, b := range line.blocks() {
+				if b.Type == "text" {
+					if p := humanPrompt(b.Text); p != "" {
+						prompt = p
+						break
+					}
+				}
+			}
+		case "assistant":
+			for _, b := range line.blocks() {
+				if b.Type != "tool_use" {
+					continue
+				}
+				switch b.Name {
+				case "Edit", "MultiEdit", "Write", "NotebookEdit":
+				default:
+					continue
+				}
+				var input struct {
+					FilePath     string `json:"file_path"`
+					NotebookPath string `json:"notebook_path"`
+				}
+				json.Unmarshal(b.Input, &input)
+				name := input.FilePath
+				if name == "" {
+					name = input.NotebookPath
+				}
+				if name == "" {
+					continue
+				}
+				name = filepath.Base(name)
+				if edits[name] == 0 {
+					order = append(order, name)
+				}
+				edits[name]++
+			}
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return edits[order[i]] > edits[order[j]] })
+	if len(order) > 2 {
+		order = order[:2]
+	}
+	return summary, prompt, order
+}
+
+// humanPrompt returns the text of a user message if a person typed it, or
+// "" for boilerplate. A slash command counts through its arguments.
+func humanPrompt(text string) string {
+	text = strings.TrimSpace(text)
+	if m := commandNameRe.FindStringSubmatch(text); m != nil {
+		args := commandArgsRe.FindStringSubmatch(text)
+		if args == nil || args[1] == "" {
+			return ""
+		}
+		return args[1]
+	}
+	for _, p := range boilerplatePrefixes {
+		if strings.HasPrefix(text, p) {
+			return ""
+		}
+	}
+	return text
+}
+
+// shortenTitle reduces text to its first line, with whitespace collapsed,
+// cut at a word boundary to at most n runes.
+func shortenTitle(text string, n int) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			text = line
+			break
+		}
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	r := []rune(text)
+	if len(r) <= n {
+		return text
+	}
+	cut := string(r[:n-1])
+	if i := strings.LastIndexByte(cut, ' '); i > n/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:-") + "…"
+}
@@ -0,0 +1,43 @@
+package session
+
+import "github.com/baz-sh/clsm/internal/git"
+
+// MarkGoneBranches sets BranchGone on sessions whose GitBranch no longer
+// exists in the local repository at their project path. Sessions without a
+// branch, on a detached HEAD, or whose project is missing or not a git
+// repository are left unmarked, since there is nothing to check against.
+func MarkGoneBranches(sessions []Session) {
+	branchesByPath := make(map[string]map[string]bool)
+	checked := make(map[string]bool)
+
+	for i := range sessions {
+		s := &sessions[i]
+		if s.GitBranch == "" || s.GitBranch == "HEAD" || s.ProjectPath == "" {
+			continue
+		}
+		if !checked[s.ProjectPath] {
+			checked[s.ProjectPath] = true
+			if branches, err := git.LocalBranches(s.ProjectPath); err == nil {
+				branchesByPath[s.ProjectPath] = branches
+			}
+		}
+		branches, ok := branchesByPath[s.ProjectPath]
+		s.BranchGone = ok && !branches[s.GitBranch]
+	}
+}
+
+// GoneBranchKeys returns the keys, as Session.Key gives them, of sessions
+// whose branch no longer exists, as determined by MarkGoneBranches.
+func GoneBranchKeys(sessions []Session) map[string]bool {
+	marked := make([]Session, len(sessions))
+	copy(marked, sessions)
+	MarkGoneBranches(marked)
+
+	gone := make(map[string]bool)
+	for _, s := range marked {
+		if s.BranchGone {
+			gone[s.Key()] = true
+		}
+	}
+	return gone
+}
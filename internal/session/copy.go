@@ -0,0 +1,162 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+// Outcomes of copying a session to another data directory.
+const (
+	CopyCopied  = "copied"  // the session wasn't there
+	CopyUpdated = "updated" // the copy there was behind and was extended
+	CopyExists  = "exists"  // the copy there was the same or further on
+)
+
+// Copy copies a session into the same project in another data directory:
+// its transcript, its subagents' transcripts, its Artifacts and its index
+// entry, keeping the session ID and modification times. A copy that is already there is
+// extended if it is behind and left alone if it is the same or further
+// on; a different session with the same ID is an error. It returns the
+// session as it is in dst and one of the Copy outcomes.
+func Copy(s Session, dst *claude.Root) (Session, string, error) {
+	copied, status, err := copySession(s, dst)
+	if err == nil && status != CopyExists {
+		audit.Record(audit.Entry{Op: audit.OpCopy, Kind: audit.KindSession, ID: s.SessionID, Paths: sessionPaths(copied), Before: s.FullPath, After: copied.FullPath})
+	}
+	return copied, status, err
+}
+
+func copySession(s Session, dst *claude.Root) (Session, string, error) {
+	srcDir := filepath.Dir(s.FullPath)
+	dstDir := filepath.Join(dst.ProjectsDir(), s.Project)
+	if filepath.Clean(srcDir) == filepath.Clean(dstDir) {
+		return Session{}, "", fmt.Errorf("session %s is already in %s", s.SessionID, dst.Dir)
+	}
+	src := claude.For(s.Profile)
+
+	data, err := src.ReadFile(s.FullPath)
+	if err != nil {
+		return Session{}, "", err
+	}
+	if err := CheckIndex(dst, filepath.Join(dstDir, IndexFileName)); err != nil {
+		return Session{}, "", err
+	}
+	dstPath := filepath.Join(dstDir, filepath.Base(s.FullPath))
+	status := CopyCopied
+	old, err := dst.ReadFile(dstPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return Session{}, "", err
+	case bytes.HasPrefix(old, data):
+		status = CopyExists
+	case bytes.HasPrefix(data, old):
+		status = CopyUpdated
+	default:
+		return Session{}, "", fmt.Errorf("%s already has a different session %s", dst.Dir, s.SessionID)
+	}
+
+	copied := s
+	copied.Profile = dst.Name
+	copied.FullPath = dstPath
+	copied.Subagents = nil
+	for _, a := range findSubagents(srcDir)[s.SessionID] {
+		rel, err := filepath.Rel(srcDir, a.FullPath)
+		if err != nil {
+			return Session{}, "", err
+		}
+		a.FullPath = filepath.Join(dstDir, rel)
+		copied.Subagents = append(copied.Subagents, a)
+		if status == CopyExists {
+			continue
+		}
+		if err := copyFile(src, filepath.Join(srcDir, rel), dst, a.FullPath); err != nil {
+			return Session{}, "", err
+		}
+	}
+	// Artifacts are copied even when the transcript is already there, as
+	// Move deletes them next.
+	if err := copyArtifacts(Artifacts(s), dst); err != nil {
+		return Session{}, "", err
+	}
+	if status == CopyExists {
+		return copied, status, nil
+	}
+	if err := copyFile(src, s.FullPath, dst, dstPath); err != nil {
+		return Session{}, "", err
+	}
+
+	entries, err := IndexEntries(src, filepath.Join(srcDir, IndexFileName))
+	if err != nil {
+		return Session{}, "", fmt.Errorf("reading index: %w", err)
+	}
+	if entry, ok := entries[s.SessionID]; ok {
+		var obj rawObject
+		if err := json.Unmarshal(entry, &obj); err != nil {
+			return Session{}, "", fmt.Errorf("parsing index entry: %w", err)
+		}
+		path, _ := json.Marshal(dstPath)
+		obj.set("fullPath", path)
+		raw, err := marshalRaw(obj, "")
+		if err != nil {
+			return Session{}, "", err
+		}
+		if err := MergeIndexEntry(dst, filepath.Join(dstDir, IndexFileName), raw, s.ProjectPath); err != nil {
+			return Session{}, "", fmt.Errorf("updating index: %w", err)
+		}
+	}
+	return copied, status, nil
+}
+
+// Move copies a session to dst as Copy does and then deletes it from the
+// data directory it was in.
+func Move(s Session, dst *claude.Root) (Session, string, error) {
+	moved, status, err := copySession(s, dst)
+	if err != nil {
+		return Session{}, "", err
+	}
+	e := audit.Entry{Op: audit.OpMove, Kind: audit.KindSession, ID: s.SessionID, Paths: append(sessionPaths(s), sessionPaths(moved)...), Before: s.FullPath, After: moved.FullPath}
+	if r := deleteSessions([]Session{s}, "")[0]; !r.Success {
+		e.Op, e.Paths = audit.OpCopy, sessionPaths(moved)
+		audit.Record(e)
+		return moved, status, fmt.Errorf("copied to %s but not deleted: %s", dst.Dir, r.Error)
+	}
+	audit.Record(e)
+	return moved, status, nil
+}
+
+// sessionPaths returns a session's transcript and its subagents'.
+func sessionPaths(s Session) []string {
+	paths := []string{s.FullPath}
+	for _, a := range s.Subagents {
+		paths = append(paths, a.FullPath)
+	}
+	return paths
+}
+
+// copyFile copies a file from src into dst, creating its directory and
+// keeping its mode and modification time.
+func copyFile(src *claude.Root, from string, dst *claude.Root, to string) error {
+	info, err := src.Stat(from)
+	if err != nil {
+		return err
+	}
+	data, err := src.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	if err := dst.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		return err
+	}
+	if err := dst.WriteFile(to, data, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return dst.Chtimes(to, info.ModTime(), info.ModTime())
+}
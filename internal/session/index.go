@@ -0,0 +1,264 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/baz-sh/clsm/internal/backup"
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+// IndexFileName is the name of a project's session index.
+const IndexFileName = "sessions-index.json"
+
+// rawField is one field of a JSON object, with its value as written.
+type rawField struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// rawObject is a JSON object that keeps its fields in order and their
+// values as written, so fields clsm doesn't know about survive a rewrite.
+type rawObject []rawField
+
+func (o *rawObject) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return errors.New("not a JSON object")
+	}
+	*o = nil
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		*o = append(*o, rawField{Key: t.(string), Value: v})
+	}
+	return nil
+}
+
+func (o rawObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.Key)
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(f.Value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// get returns the value of key.
+func (o rawObject) get(key string) (json.RawMessage, bool) {
+	for _, f := range o {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// set replaces the value of key, or adds the field at the end.
+func (o *rawObject) set(key string, value json.RawMessage) {
+	for i, f := range *o {
+		if f.Key == key {
+			(*o)[i].Value = value
+			return
+		}
+	}
+	*o = append(*o, rawField{Key: key, Value: value})
+}
+
+// IndexVersion is the sessions-index.json version clsm knows how to
+// rewrite. Indexes with another version are read as far as their fields
+// allow but never written.
+const IndexVersion = 1
+
+// indexDoc is a sessions-index.json read for rewriting. Everything but the
+// entries clsm adds or removes is written back as it was read.
+type indexDoc struct {
+	obj     rawObject
+	entries []json.RawMessage
+	newline bool // whether the file ended with a newline
+}
+
+// readIndex reads the sessions-index.json at idxPath in r for rewriting. It
+// returns an error wrapping fs.ErrNotExist if there is none, and refuses
+// an index whose version clsm doesn't know.
+func readIndex(r *claude.Root, idxPath string) (*indexDoc, error) {
+	data, err := r.ReadFile(idxPath)
+	if err != nil {
+		return nil, err
+	}
+	doc := &indexDoc{newline: bytes.HasSuffix(data, []byte("\n"))}
+	if err := json.Unmarshal(data, &doc.obj); err != nil {
+		return nil, fmt.Errorf("parsing index: %w", err)
+	}
+	if raw, ok := doc.obj.get("version"); ok {
+		var v int
+		if err := json.Unmarshal(raw, &v); err != nil || v != IndexVersion {
+			return nil, fmt.Errorf("%s has version %s; this clsm only understands version %d, so it won't change it", idxPath, raw, IndexVersion)
+		}
+	}
+	if raw, ok := doc.obj.get("entries"); ok {
+		if err := json.Unmarshal(raw, &doc.entries); err != nil {
+			return nil, fmt.Errorf("parsing index entries: %w", err)
+		}
+	}
+	return doc, nil
+}
+
+// CheckIndex returns an error if the sessions-index.json at idxPath in r
+// exists but couldn't be rewritten, so callers can stop before changing
+// the files it lists.
+func CheckIndex(r *claude.Root, idxPath string) error {
+	if _, err := readIndex(r, idxPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// entryID returns the session ID of a raw index entry, or "" if it has
+// none.
+func entryID(entry json.RawMessage) string {
+	var id struct {
+		SessionID string `json:"sessionId"`
+	}
+	json.Unmarshal(entry, &id)
+	return id.SessionID
+}
+
+// write replaces the index at idxPath in r with doc, backing up the one it
+// replaces first.
+func (doc *indexDoc) write(r *claude.Root, idxPath string) error {
+	raw, err := marshalRaw(doc.entries, "")
+	if err != nil {
+		return err
+	}
+	doc.obj.set("entries", raw)
+	out, err := marshalRaw(doc.obj, "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling index: %w", err)
+	}
+	if doc.newline {
+		out = append(out, '\n')
+	}
+	if err := backup.Save(r, idxPath); err != nil {
+		return err
+	}
+	return r.WriteFile(idxPath, out, 0644)
+}
+
+// MergeIndexEntry adds a raw entry to the sessions-index.json at idxPath
+// in r, replacing any entry for the same session, and creates the index
+// if there is none. Other entries and fields are kept as they are.
+func MergeIndexEntry(r *claude.Root, idxPath string, entry json.RawMessage, projectPath string) error {
+	id := entryID(entry)
+	if id == "" {
+		return errors.New("index entry has no sessionId")
+	}
+
+	doc, err := readIndex(r, idxPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		path, _ := json.Marshal(projectPath)
+		doc = &indexDoc{obj: rawObject{{"version", json.RawMessage(strconv.Itoa(IndexVersion))}, {"entries", json.RawMessage("[]")}, {"originalPath", path}}}
+	case err != nil:
+		return err
+	}
+
+	replaced := false
+	for i, e := range doc.entries {
+		if entryID(e) == id {
+			if bytes.Equal(e, entry) {
+				return nil
+			}
+			doc.entries[i] = entry
+			replaced = true
+		}
+	}
+	if !replaced {
+		doc.entries = append(doc.entries, entry)
+	}
+	return doc.write(r, idxPath)
+}
+
+// IndexEntries returns the raw entries of the sessions-index.json at
+// idxPath in r by session ID. A missing index has no entries.
+func IndexEntries(r *claude.Root, idxPath string) (map[string]json.RawMessage, error) {
+	data, err := r.ReadFile(idxPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var idx struct {
+		Entries []json.RawMessage `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("parsing index: %w", err)
+	}
+	entries := make(map[string]json.RawMessage, len(idx.Entries))
+	for _, e := range idx.Entries {
+		if id := entryID(e); id != "" {
+			entries[id] = e
+		}
+	}
+	return entries, nil
+}
+
+// RemoveIndexEntry removes a session's entry from the sessions-index.json
+// at idxPath in r. A missing index is left missing.
+func RemoveIndexEntry(r *claude.Root, idxPath, sessionID string) error {
+	return removeFromIndex(r, idxPath, sessionID)
+}
+
+// removeFromIndex removes a session's entries from the index at idxPath,
+// keeping every other entry and field as it was.
+func removeFromIndex(r *claude.Root, idxPath, sessionID string) error {
+	doc, err := readIndex(r, idxPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil // no index to update
+	}
+	if err != nil {
+		return err
+	}
+	kept := doc.entries[:0]
+	for _, e := range doc.entries {
+		if entryID(e) != sessionID {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(doc.entries) {
+		return nil
+	}
+	doc.entries = kept
+	return doc.write(r, idxPath)
+}
+
+// marshalRaw marshals v like Claude Code does, leaving <, > and &
+// unescaped, indented by indent if it is not empty.
+func marshalRaw(v any, indent string) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
@@ -0,0 +1,167 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+func TestRawObjectRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", `{}`, `{}`},
+		{"field order", `{"z":1,"a":2,"m":3}`, `{"z":1,"a":2,"m":3}`},
+		{"values as written", `{"n":1.50,"e":1e3,"s":"é"}`, `{"n":1.50,"e":1e3,"s":"é"}`},
+		{"nested", `{"b":{"y":[1,2],"x":null},"a":true}`, `{"b":{"y":[1,2],"x":null},"a":true}`},
+		{"spacing between fields dropped", `{ "b" : 1 , "a" : 2 }`, `{"b":1,"a":2}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var o rawObject
+			if err := json.Unmarshal([]byte(tt.in), &o); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			got, err := json.Marshal(o)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRawObjectSet(t *testing.T) {
+	var o rawObject
+	if err := json.Unmarshal([]byte(`{"version":1,"entries":[],"extra":"x"}`), &o); err != nil {
+		t.Fatal(err)
+	}
+	o.set("entries", json.RawMessage(`[{}]`))
+	o.set("added", json.RawMessage(`2`))
+	got, _ := json.Marshal(o)
+	if want := `{"version":1,"entries":[{}],"extra":"x","added":2}`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestRawObjectRejectsNonObject(t *testing.T) {
+	for _, in := range []string{`[]`, `"x"`, `1`, `null`} {
+		var o rawObject
+		if err := json.Unmarshal([]byte(in), &o); err == nil {
+			t.Errorf("Unmarshal(%s): want error", in)
+		}
+	}
+}
+
+func TestReadIndexVersion(t *testing.T) {
+	dir := t.TempDir()
+	claude.Use(claude.New(dir))
+	t.Cleanup(func() { claude.UseAll(nil) })
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"current version", `{"version":1,"entries":[{"sessionId":"a"}]}`, ""},
+		{"no version", `{"entries":[]}`, ""},
+		{"newer version", `{"version":2,"entries":[]}`, "only understands version 1"},
+		{"version not a number", `{"version":"1","entries":[]}`, "only understands version 1"},
+		{"not an object", `[]`, "parsing index"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, IndexFileName)
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := readIndex(claude.Current(), path)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("readIndex: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("readIndex: got %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRemoveIndexEntryKeepsUnknownFields(t *testing.T) {
+	dir := t.TempDir()
+	claude.Use(claude.New(dir))
+	t.Cleanup(func() { claude.UseAll(nil) })
+	t.Setenv("CLSM_CONFIG_DIR", t.TempDir())
+
+	tests := []struct {
+		name     string
+		data     string
+		wantKeys []string
+		want     map[string]string // field -> compacted value
+		wantErr  bool
+	}{
+		{
+			name:     "unknown fields kept in order",
+			data:     `{"version":1,"future":{"b":1,"a":2},"entries":[{"sessionId":"a","x":1},{"sessionId":"b","zz":true,"aa":1.0}],"originalPath":"/p"}`,
+			wantKeys: []string{"version", "future", "entries", "originalPath"},
+			want: map[string]string{
+				"future":  `{"b":1,"a":2}`,
+				"entries": `[{"sessionId":"b","zz":true,"aa":1.0}]`,
+			},
+		},
+		{
+			name:    "unknown version left alone",
+			data:    `{"version":2,"entries":[{"sessionId":"a"}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, IndexFileName)
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err := RemoveIndexEntry(claude.Current(), path, "a")
+			data, _ := os.ReadFile(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("RemoveIndexEntry: want error")
+				}
+				if string(data) != tt.data {
+					t.Errorf("index changed to %s", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RemoveIndexEntry: %v", err)
+			}
+			var o rawObject
+			if err := json.Unmarshal(data, &o); err != nil {
+				t.Fatal(err)
+			}
+			var keys []string
+			for _, f := range o {
+				keys = append(keys, f.Key)
+			}
+			if strings.Join(keys, ",") != strings.Join(tt.wantKeys, ",") {
+				t.Errorf("keys %v, want %v", keys, tt.wantKeys)
+			}
+			for key, want := range tt.want {
+				raw, _ := o.get(key)
+				var b bytes.Buffer
+				json.Compact(&b, raw)
+				if b.String() != want {
+					t.Errorf("%s = %s, want %s", key, b.String(), want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+// LiveWindow is how recently a transcript must have been written to count
+// as being written, unless the running processes show no claude process
+// that could be writing it.
+const LiveWindow = 2 * time.Minute
+
+// markLive fills Live for each session from a fresh look at the running
+// processes.
+func markLive(sessions []Session) {
+	if len(sessions) == 0 {
+		return
+	}
+	p := scanProcs()
+	now := time.Now()
+	for i := range sessions {
+		sessions[i].Live = p.liveReason(sessions[i], now)
+	}
+}
+
+// SplitLive separates sessions that are safe to change from those Claude
+// Code is writing. It looks at the running processes afresh, so a session
+// resumed since the sessions were listed still counts.
+func SplitLive(sessions []Session) (idle, live []Session) {
+	// Marked on a copy, as callers may be showing the slice they pass.
+	sessions = append([]Session(nil), sessions...)
+	markLive(sessions)
+	for _, s := range sessions {
+		if s.Live != "" {
+			live = append(live, s)
+		} else {
+			idle = append(idle, s)
+		}
+	}
+	return idle, live
+}
+
+// CheckLive returns an error if Claude Code is writing the session, for
+// commands that change one session at a time.
+func CheckLive(s Session) error {
+	if _, live := SplitLive([]Session{s}); len(live) > 0 {
+		return fmt.Errorf("session %s is in use by Claude Code (%s)", s.SessionID, live[0].Live)
+	}
+	return nil
+}
+
+// procs is what the running processes say about sessions: the transcripts
+// they have open and the projects claude runs in.
+type procs struct {
+	visible bool           // whether /proc could be read at all
+	hidden  bool           // whether a claude process's directory couldn't be read
+	open    map[string]int // open .jsonl file -> pid
+	claude  map[string]int // working directory of a claude process -> pid
+}
+
+// scanProcs reads /proc. On systems without it nothing is found, and for
+// other users' processes the working directory can't be read; liveness
+// then falls back to recent writes.
+func scanProcs() procs {
+	p := procs{open: make(map[string]int), claude: make(map[string]int)}
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return p
+	}
+	p.visible = true
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		dir := filepath.Join("/proc", e.Name())
+		if isClaudeProcess(dir) {
+			if cwd, err := os.Readlink(filepath.Join(dir, "cwd")); err == nil {
+				p.claude[cwd] = pid
+			} else {
+				p.hidden = true
+			}
+		}
+		fds, _ := os.ReadDir(filepath.Join(dir, "fd"))
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(dir, "fd", fd.Name()))
+			if err == nil && strings.HasSuffix(target, ".jsonl") {
+				p.open[target] = pid
+			}
+		}
+	}
+	return p
+}
+
+// isClaudeProcess reports whether the process in /proc dir is Claude Code:
+// a claude executable, or node running the claude-code package.
+func isClaudeProcess(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "cmdline"))
+	if err != nil {
+		return false
+	}
+	args := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+	for _, a := range args[:min(2, len(args))] {
+		if filepath.Base(a) == "claude" || strings.Contains(a, "claude-code") {
+			return true
+		}
+	}
+	return false
+}
+
+// liveReason says why Claude Code seems to be writing s, or returns ""
+// if it isn't.
+func (p procs) liveReason(s Session, now time.Time) string {
+	path := s.FullPath
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		path = real
+	}
+	if pid, ok := p.open[path]; ok {
+		return fmt.Sprintf("open in process %d", pid)
+	}
+	if pid, ok := lockPID(s.FullPath); ok && (pid == 0 || !p.visible || processExists(pid)) {
+		if pid == 0 {
+			return "lock file present"
+		}
+		return fmt.Sprintf("locked by process %d", pid)
+	}
+
+	info, err := claude.For(s.Profile).Stat(s.FullPath)
+	if err != nil {
+		return ""
+	}
+	age := now.Sub(info.ModTime())
+	if age > LiveWindow {
+		return ""
+	}
+	// A recent write counts unless /proc shows no claude process that
+	// could have made it.
+	ago := age.Round(time.Second)
+	project := s.ProjectPath
+	if real, err := filepath.EvalSymlinks(project); err == nil {
+		project = real
+	}
+	if pid, ok := p.claude[project]; ok {
+		return fmt.Sprintf("written %s ago, claude running in its project as process %d", ago, pid)
+	}
+	if !p.visible || p.hidden {
+		return fmt.Sprintf("written %s ago", ago)
+	}
+	if pid, ok := p.anyClaude(); ok {
+		return fmt.Sprintf("written %s ago, claude running as process %d", ago, pid)
+	}
+	return ""
+}
+
+// anyClaude returns the lowest ID of a running claude process, if any.
+func (p procs) anyClaude() (int, bool) {
+	lowest, found := 0, false
+	for _, pid := range p.claude {
+		if !found || pid < lowest {
+			lowest, found = pid, true
+		}
+	}
+	return lowest, found
+}
+
+// lockPID looks for a lock or PID file beside a transcript and returns the
+// process ID it holds, or 0 if it holds none.
+func lockPID(transcript string) (int, bool) {
+	names := []string{transcript + ".lock", strings.TrimSuffix(transcript, ".jsonl") + ".pid"}
+	for _, name := range names {
+		data, err := claude.RootOf(name).ReadFile(name)
+		if err != nil {
+			continue
+		}
+		pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+		return pid, true
+	}
+	return 0, false
+}
+
+// processExists reports whether a process with the given ID is running.
+func processExists(pid int) bool {
+	_, err := os.Stat(filepath.Join("/proc", strconv.Itoa(pid)))
+	return err == nil
+}
+
+// DeleteIdle deletes the sessions Claude Code isn't writing with del,
+// Delete or Prune, and reports the others as failures, for callers with
+// no way to force the issue.
+func DeleteIdle(sessions []Session, del func([]Session) []DeleteResult) []DeleteResult {
+	idle, live := SplitLive(sessions)
+	results := del(idle)
+	for _, s := range live {
+		results = append(results, DeleteResult{
+			SessionID: s.SessionID,
+			Profile:   s.Profile,
+			Error:     "in use by Claude Code (" + s.Live + ")",
+		})
+	}
+	return results
+}
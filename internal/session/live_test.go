@@ -0,0 +1,70 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+func TestLiveReason(t *testing.T) {
+	dir := t.TempDir()
+	claude.Use(claude.New(dir))
+	t.Cleanup(func() { claude.UseAll(nil) })
+
+	project := filepath.Join(dir, "project")
+	link := filepath.Join(dir, "link")
+	if err := os.Mkdir(project, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(project, link); err != nil {
+		t.Fatal(err)
+	}
+	transcript := filepath.Join(dir, "s.jsonl")
+	if err := os.WriteFile(transcript, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	real := mustReal(t, project)
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		p       procs
+		project string
+		age     time.Duration
+		want    string // a substring of the reason; "" for not live
+	}{
+		{"open", procs{visible: true, open: map[string]int{mustReal(t, transcript): 7}}, project, time.Hour, "open in process 7"},
+		{"old write", procs{visible: true, claude: map[string]int{real: 7}}, project, time.Hour, ""},
+		{"recent, no claude running", procs{visible: true}, project, time.Second, ""},
+		{"recent, no /proc", procs{}, project, time.Second, "written"},
+		{"recent, claude hidden", procs{visible: true, hidden: true}, project, time.Second, "written"},
+		{"recent, claude in project", procs{visible: true, claude: map[string]int{real: 7}}, project, time.Second, "in its project as process 7"},
+		{"recent, claude in project through a symlink", procs{visible: true, claude: map[string]int{real: 7}}, link, time.Second, "in its project as process 7"},
+		{"recent, claude elsewhere", procs{visible: true, claude: map[string]int{"/elsewhere": 9, "/other": 8}}, project, time.Second, "claude running as process 8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mtime := now.Add(-tt.age)
+			if err := os.Chtimes(transcript, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+			got := tt.p.liveReason(Session{FullPath: transcript, ProjectPath: tt.project}, now)
+			if tt.want == "" && got != "" || tt.want != "" && !strings.Contains(got, tt.want) {
+				t.Errorf("liveReason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustReal(t *testing.T, path string) string {
+	t.Helper()
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return real
+}
@@ -0,0 +1,119 @@
+package session
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/baz-sh/clsm/internal/git"
+)
+
+// Repository is a git repository whose worktrees and clones Claude Code
+// recorded as separate projects.
+type Repository struct {
+	Key      string    // identity shared by its worktrees and clones; see git.Repo.Key
+	Name     string    // owner/repo from the remote, or the work tree's directory name
+	Remote   string    // origin URL, if any
+	Projects []Project // one per worktree, clone or subdirectory; main worktree first
+}
+
+// Project returns the repository as a single project: counts and sizes
+// summed, the most recent activity, and the path and directory of its
+// first project.
+func (r Repository) Project() Project {
+	p := r.Projects[0]
+	for _, q := range r.Projects[1:] {
+		p.SessionCount += q.SessionCount
+		p.TotalSize += q.TotalSize
+		if q.LastModified > p.LastModified {
+			p.LastModified = q.LastModified
+			p.LastPrompt = q.LastPrompt
+		}
+	}
+	return p
+}
+
+// Matches reports whether the repository name or any of its project paths
+// contains the term, ignoring case.
+func (r Repository) Matches(term string) bool {
+	if strings.Contains(strings.ToLower(r.Name), strings.ToLower(term)) {
+		return true
+	}
+	for _, p := range r.Projects {
+		if p.Matches(term) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListSessions lists the sessions of every project in the repository.
+func (r Repository) ListSessions() ([]Session, error) {
+	var all []Session
+	for _, p := range r.Projects {
+		sessions, err := ListSessions(p.Profile, p.DirName)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, sessions...)
+	}
+	return all, nil
+}
+
+// GroupByRepository merges projects that are worktrees or clones of the
+// same repository. Projects outside a repository, or whose path no longer
+// exists, each form a repository of their own with an empty Key. The
+// result keeps the order of the first project of each repository.
+func GroupByRepository(projects []Project) []Repository {
+	var repos []Repository
+	byKey := make(map[string]int)
+	infos := make(map[string]git.Repo) // by project DirName
+	for _, p := range projects {
+		info, err := git.Identify(p.Path)
+		if err != nil {
+			repos = append(repos, Repository{Name: filepath.Base(p.Path), Projects: []Project{p}})
+			continue
+		}
+		infos[p.DirName] = info
+		key := info.Key()
+		if i, ok := byKey[key]; ok {
+			repos[i].Projects = append(repos[i].Projects, p)
+			continue
+		}
+		byKey[key] = len(repos)
+		repos = append(repos, Repository{
+			Key:      key,
+			Projects: []Project{p},
+		})
+	}
+
+	for i := range repos {
+		if repos[i].Key == "" {
+			continue
+		}
+		ps := repos[i].Projects
+		sort.SliceStable(ps, func(a, b int) bool {
+			mainA, mainB := infos[ps[a].DirName].Main(), infos[ps[b].DirName].Main()
+			if mainA != mainB {
+				return mainA
+			}
+			return ps[a].Path < ps[b].Path
+		})
+		info := infos[ps[0].DirName]
+		repos[i].Name = repoName(info)
+		repos[i].Remote = info.Remote
+	}
+	return repos
+}
+
+// repoName returns owner/repo for a remote on a hosting service, or the
+// name of the work tree's directory.
+func repoName(info git.Repo) string {
+	if info.Remote != "" {
+		return git.RemoteName(info.Remote)
+	}
+	if info.Main() {
+		return filepath.Base(info.Root)
+	}
+	return filepath.Base(filepath.Dir(info.CommonDir))
+}
@@ -0,0 +1,148 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortKey is a session list sort order. Each key has a natural direction:
+// newest, largest or alphabetical first.
+type SortKey string
+
+const (
+	SortModified SortKey = "modified" // most recently modified first
+	SortCreated  SortKey = "created"  // most recently created first
+	SortMessages SortKey = "messages" // most messages first
+	SortSize     SortKey = "size"     // largest file first
+	SortTitle    SortKey = "title"    // A-Z by display title
+	SortProject  SortKey = "project"  // A-Z by project path, then newest
+)
+
+// SortKeys lists the session sort keys in cycling order.
+var SortKeys = []SortKey{SortModified, SortCreated, SortMessages, SortSize, SortTitle, SortProject}
+
+// ProjectSortKey is a project list sort order.
+type ProjectSortKey string
+
+const (
+	ProjectSortRecent   ProjectSortKey = "recent"   // most recent activity first
+	ProjectSortSessions ProjectSortKey = "sessions" // most sessions first
+	ProjectSortSize     ProjectSortKey = "size"     // largest total size first
+	ProjectSortPath     ProjectSortKey = "path"     // A-Z by project path
+)
+
+// ProjectSortKeys lists the project sort keys in cycling order.
+var ProjectSortKeys = []ProjectSortKey{ProjectSortRecent, ProjectSortSessions, ProjectSortSize, ProjectSortPath}
+
+// ParseSortKey validates a session sort key given on the command line.
+func ParseSortKey(s string) (SortKey, error) {
+	for _, k := range SortKeys {
+		if string(k) == s {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("unknown session sort %q (available: %s)", s, JoinKeys(SortKeys))
+}
+
+// ParseProjectSortKey validates a project sort key given on the command line.
+func ParseProjectSortKey(s string) (ProjectSortKey, error) {
+	for _, k := range ProjectSortKeys {
+		if string(k) == s {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("unknown project sort %q (available: %s)", s, JoinKeys(ProjectSortKeys))
+}
+
+// Next returns the key after k in SortKeys, wrapping around.
+func (k SortKey) Next() SortKey {
+	for i, key := range SortKeys {
+		if key == k {
+			return SortKeys[(i+1)%len(SortKeys)]
+		}
+	}
+	return SortKeys[0]
+}
+
+// Next returns the key after k in ProjectSortKeys, wrapping around.
+func (k ProjectSortKey) Next() ProjectSortKey {
+	for i, key := range ProjectSortKeys {
+		if key == k {
+			return ProjectSortKeys[(i+1)%len(ProjectSortKeys)]
+		}
+	}
+	return ProjectSortKeys[0]
+}
+
+// LessSessions reports whether a sorts before b under the given key.
+// reverse flips the key's natural direction.
+func LessSessions(a, b Session, key SortKey, reverse bool) bool {
+	if reverse {
+		a, b = b, a
+	}
+	switch key {
+	case SortCreated:
+		return parseTime(a.Created).After(parseTime(b.Created))
+	case SortMessages:
+		return a.MsgCount > b.MsgCount
+	case SortSize:
+		return a.Size > b.Size
+	case SortTitle:
+		return strings.ToLower(a.Title()) < strings.ToLower(b.Title())
+	case SortProject:
+		if a.ProjectPath != b.ProjectPath {
+			return a.ProjectPath < b.ProjectPath
+		}
+	}
+	return parseTime(a.Modified).After(parseTime(b.Modified))
+}
+
+// LessProjects reports whether a sorts before b under the given key.
+// reverse flips the key's natural direction.
+func LessProjects(a, b Project, key ProjectSortKey, reverse bool) bool {
+	if reverse {
+		a, b = b, a
+	}
+	switch key {
+	case ProjectSortSessions:
+		return a.SessionCount > b.SessionCount
+	case ProjectSortSize:
+		return a.TotalSize > b.TotalSize
+	case ProjectSortPath:
+		return strings.ToLower(a.Path) < strings.ToLower(b.Path)
+	}
+	return parseTime(a.LastModified).After(parseTime(b.LastModified))
+}
+
+// SortSessions sorts sessions in place by the given key.
+func SortSessions(sessions []Session, key SortKey, reverse bool) {
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return LessSessions(sessions[i], sessions[j], key, reverse)
+	})
+}
+
+// SortProjects sorts projects in place by the given key.
+func SortProjects(projects []Project, key ProjectSortKey, reverse bool) {
+	sort.SliceStable(projects, func(i, j int) bool {
+		return LessProjects(projects[i], projects[j], key, reverse)
+	})
+}
+
+func parseTime(ts string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		t, _ = time.Parse(time.RFC3339, ts)
+	}
+	return t
+}
+
+// JoinKeys lists sort keys for help and error messages.
+func JoinKeys[K ~string](keys []K) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = string(k)
+	}
+	return strings.Join(names, ", ")
+}
@@ -9,13 +9,11 @@ import (
 	"sort"
 	"strings"
 	"time"
-)
 
-// ClaudeDir returns the path to the Claude projects directory.
-func ClaudeDir() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".claude", "projects")
-}
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/meta"
+)
 
 // SearchProgress reports the current state of a search operation.
 type SearchProgress struct {
@@ -47,11 +45,11 @@ func SearchWithProgress(term string, progress chan<- SearchProgress) ([]Session,
 	if progress != nil {
 		defer close(progress)
 	}
-	base := ClaudeDir()
 	lower := strings.ToLower(term)
 
-	// Map of sessionID -> Session for deduplication.
+	// Map of profile and sessionID -> Session for deduplication.
 	found := make(map[string]Session)
+	key := Key
 
 	report := func(p SearchProgress) {
 		if progress != nil {
@@ -60,12 +58,13 @@ func SearchWithProgress(term string, progress chan<- SearchProgress) ([]Session,
 	}
 
 	// 1. Search index files for summary and firstPrompt matches.
-	indexes, err := filepath.Glob(filepath.Join(base, "*", "sessions-index.json"))
+	indexes, err := globRoots("*", "sessions-index.json")
 	if err != nil {
 		return nil, fmt.Errorf("globbing index files: %w", err)
 	}
 
-	for i, idxPath := range indexes {
+	for i, idx := range indexes {
+		idxPath := idx.path
 		report(SearchProgress{
 			Phase:   "indexes",
 			Current: i + 1,
@@ -75,19 +74,22 @@ func SearchWithProgress(term string, progress chan<- SearchProgress) ([]Session,
 
 		projectDir := filepath.Base(filepath.Dir(idxPath))
 
-		data, err := os.ReadFile(idxPath)
+		data, err := claude.RootOf(idxPath).ReadFile(idxPath)
 		if err != nil {
 			continue
 		}
 
-		var idx IndexFile
-		if err := json.Unmarshal(data, &idx); err != nil {
+		var file IndexFile
+		if err := json.Unmarshal(data, &file); err != nil {
 			continue
 		}
 
 		projectPath := decodeDirName(projectDir)
 
-		for _, entry := range idx.Entries {
+		for _, entry := range file.Entries {
+			if entry.IsSidechain {
+				continue
+			}
 			var matchSource, matchValue string
 
 			switch {
@@ -101,11 +103,12 @@ func SearchWithProgress(term string, progress chan<- SearchProgress) ([]Session,
 				continue
 			}
 
-			found[entry.SessionID] = Session{
+			found[key(idx.profile, entry.SessionID)] = Session{
 				SessionID:   entry.SessionID,
+				Profile:     idx.profile,
 				Project:     projectDir,
 				ProjectPath: entry.ProjectPath,
-				FullPath:    entry.FullPath,
+				FullPath:    transcriptPath(filepath.Dir(idxPath), entry),
 				Summary:     entry.Summary,
 				FirstPrompt: entry.FirstPrompt,
 				MatchSource: matchSource,
@@ -118,13 +121,15 @@ func SearchWithProgress(term string, progress chan<- SearchProgress) ([]Session,
 		}
 	}
 
-	// 2. Scan JSONL files for custom-title matches.
-	jsonlFiles, err := filepath.Glob(filepath.Join(base, "*", "*.jsonl"))
+	// 2. Scan JSONL files for custom-title matches, and check clsm notes.
+	notes, _ := meta.Notes(meta.NoteSession)
+	jsonlFiles, err := globRoots("*", "*.jsonl")
 	if err != nil {
 		return nil, fmt.Errorf("globbing jsonl files: %w", err)
 	}
 
-	for i, jpath := range jsonlFiles {
+	for i, jf := range jsonlFiles {
+		jpath := jf.path
 		report(SearchProgress{
 			Phase:   "sessions",
 			Current: i + 1,
@@ -132,22 +137,29 @@ func SearchWithProgress(term string, progress chan<- SearchProgress) ([]Session,
 			Percent: 0.3 + float64(i+1)/float64(len(jsonlFiles))*0.7, // sessions = 30-100%
 		})
 
+		if isAgentFile(jpath) {
+			continue
+		}
 		title, sessionID := findCustomTitle(jpath)
+		if sessionID == "" {
+			sessionID = strings.TrimSuffix(filepath.Base(jpath), ".jsonl")
+		}
 
 		projectDir := filepath.Base(filepath.Dir(jpath))
 		titleMatch := title != "" && strings.Contains(strings.ToLower(title), lower)
 
 		if titleMatch {
 			// Custom-title takes precedence — overwrite if already found.
-			existing, ok := found[sessionID]
+			existing, ok := found[key(jf.profile, sessionID)]
 			if ok {
 				existing.CustomTitle = title
 				existing.MatchSource = "custom-title"
 				existing.MatchValue = title
-				found[sessionID] = existing
+				found[key(jf.profile, sessionID)] = existing
 			} else {
 				s := Session{
 					SessionID:   sessionID,
+					Profile:     jf.profile,
 					Project:     projectDir,
 					FullPath:    jpath,
 					CustomTitle: title,
@@ -155,14 +167,15 @@ func SearchWithProgress(term string, progress chan<- SearchProgress) ([]Session,
 					MatchValue:  title,
 				}
 				enrichFromIndex(&s, filepath.Dir(jpath))
-				found[sessionID] = s
+				found[key(jf.profile, sessionID)] = s
 			}
-		} else if _, ok := found[sessionID]; !ok {
+		} else if _, ok := found[key(jf.profile, sessionID)]; !ok {
 			// Not already found — check project path.
 			projPath := decodeDirName(projectDir)
 			if strings.Contains(strings.ToLower(projPath), lower) {
 				s := Session{
 					SessionID:   sessionID,
+					Profile:     jf.profile,
 					Project:     projectDir,
 					FullPath:    jpath,
 					MatchSource: "project",
@@ -172,7 +185,19 @@ func SearchWithProgress(term string, progress chan<- SearchProgress) ([]Session,
 					s.CustomTitle = title
 				}
 				enrichFromIndex(&s, filepath.Dir(jpath))
-				found[sessionID] = s
+				found[key(jf.profile, sessionID)] = s
+			} else if note := notes[sessionID]; strings.Contains(strings.ToLower(note), lower) {
+				s := Session{
+					SessionID:   sessionID,
+					Profile:     jf.profile,
+					Project:     projectDir,
+					FullPath:    jpath,
+					CustomTitle: title,
+					MatchSource: "note",
+					MatchValue:  matchingLine(note, lower),
+				}
+				enrichFromIndex(&s, filepath.Dir(jpath))
+				found[key(jf.profile, sessionID)] = s
 			}
 		}
 	}
@@ -181,13 +206,30 @@ func SearchWithProgress(term string, progress chan<- SearchProgress) ([]Session,
 	for _, s := range found {
 		results = append(results, s)
 	}
+	attachMeta(results)
+	markLive(results)
 
 	// Enrich results with missing data.
+	subagents := make(map[string]map[string][]Subagent) // project dir path -> parent ID -> subagents
 	for i := range results {
 		// Fill ProjectPath from directory name if missing.
 		if results[i].ProjectPath == "" && results[i].Project != "" {
 			results[i].ProjectPath = decodeDirName(results[i].Project)
 		}
+		root := claude.For(results[i].Profile)
+		if info, err := root.Stat(results[i].FullPath); err == nil {
+			results[i].Size = info.Size()
+		}
+		dir := filepath.Join(root.ProjectsDir(), results[i].Project)
+		projSubs, ok := subagents[dir]
+		if !ok {
+			projSubs = findSubagents(dir)
+			subagents[dir] = projSubs
+		}
+		results[i].Subagents = projSubs[results[i].SessionID]
+		for _, a := range results[i].Subagents {
+			results[i].Size += a.Size
+		}
 		// Fill MsgCount and FirstPrompt from JSONL if missing.
 		if results[i].MsgCount == 0 || results[i].FirstPrompt == "" {
 			prompt, count := scanSession(results[i].FullPath)
@@ -203,10 +245,21 @@ func SearchWithProgress(term string, progress chan<- SearchProgress) ([]Session,
 	return results, nil
 }
 
+// matchingLine returns the first line of text that contains lower, ignoring
+// case.
+func matchingLine(text, lower string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.Contains(strings.ToLower(line), lower) {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
+
 // findCustomTitle scans a JSONL file for custom-title entries and returns
 // the last one (most recent rename). Returns empty strings if not found.
 func findCustomTitle(path string) (string, string) {
-	f, err := os.Open(path)
+	f, err := claude.RootOf(path).Open(path)
 	if err != nil {
 		return "", ""
 	}
@@ -236,7 +289,7 @@ func findCustomTitle(path string) (string, string) {
 // enrichFromIndex fills in missing Session fields from the project's index file.
 func enrichFromIndex(s *Session, projectDir string) {
 	idxPath := filepath.Join(projectDir, "sessions-index.json")
-	data, err := os.ReadFile(idxPath)
+	data, err := claude.RootOf(idxPath).ReadFile(idxPath)
 	if err != nil {
 		return
 	}
@@ -256,34 +309,92 @@ func enrichFromIndex(s *Session, projectDir string) {
 			s.MsgCount = entry.MessageCount
 			s.GitBranch = entry.GitBranch
 			if s.FullPath == "" {
-				s.FullPath = entry.FullPath
+				s.FullPath = transcriptPath(projectDir, entry)
 			}
 			return
 		}
 	}
 }
 
-// Delete removes the given sessions: deletes the JSONL file and removes
-// the entry from the project's sessions-index.json.
+// Delete removes the given sessions: deletes the JSONL file, its
+// subagents' transcripts and its Artifacts, and removes the entry from the
+// project's sessions-index.json.
 func Delete(sessions []Session) []DeleteResult {
+	return deleteSessions(sessions, audit.OpDelete)
+}
+
+// Prune deletes empty sessions as Delete does, recording them in the
+// audit log as pruned.
+func Prune(sessions []Session) []DeleteResult {
+	return deleteSessions(sessions, audit.OpPrune)
+}
+
+// deleteSessions deletes sessions, recording each one deleted in the
+// audit log under op. An empty op records nothing, for callers that record
+// the change themselves.
+func deleteSessions(sessions []Session, op string) []DeleteResult {
 	results := make([]DeleteResult, 0, len(sessions))
+	subagents := make(map[string]map[string][]Subagent) // project path -> parent ID -> subagents
 
 	for _, s := range sessions {
-		r := DeleteResult{SessionID: s.SessionID, Success: true}
+		r := DeleteResult{SessionID: s.SessionID, Profile: s.Profile, Success: true}
+		idxPath := filepath.Join(filepath.Dir(s.FullPath), "sessions-index.json")
+
+		root := claude.For(s.Profile)
 
-		// 1. Remove the JSONL file.
-		if err := os.Remove(s.FullPath); err != nil && !os.IsNotExist(err) {
+		// 1. Check the index can be updated, then remove the JSONL file.
+		if err := CheckIndex(root, idxPath); err != nil {
+			r.Success = false
+			r.Error = err.Error()
+			results = append(results, r)
+			continue
+		}
+		// Found first, as shell snapshots are found through the transcript.
+		artifacts := Artifacts(s)
+		if err := root.Remove(s.FullPath); err != nil && !os.IsNotExist(err) {
 			r.Success = false
 			r.Error = fmt.Sprintf("removing session file: %v", err)
 			results = append(results, r)
 			continue
 		}
 
-		// 2. Update the index file.
-		idxPath := filepath.Join(filepath.Dir(s.FullPath), "sessions-index.json")
-		if err := removeFromIndex(idxPath, s.SessionID); err != nil {
+		// 2. Remove the subagents. They are looked up again rather than
+		// taken from s so that agents started since listing go too.
+		projPath := filepath.Dir(s.FullPath)
+		projSubs, ok := subagents[projPath]
+		if !ok {
+			projSubs = findSubagents(projPath)
+			subagents[projPath] = projSubs
+		}
+		subs := projSubs[s.SessionID]
+		err := removeSubagents(projPath, s.SessionID, subs)
+
+		// 3. Remove the todos, file history and the like kept for it.
+		artErr := removeArtifacts(artifacts)
+		if op != "" {
+			paths := []string{s.FullPath}
+			for _, a := range subs {
+				paths = append(paths, a.FullPath)
+			}
+			for _, a := range artifacts {
+				paths = append(paths, a.Path)
+			}
+			audit.Record(audit.Entry{Op: op, Kind: audit.KindSession, ID: s.SessionID, Paths: paths, Before: s.Title()})
+		}
+		if err != nil {
+			r.Success = false
+			r.Error = fmt.Sprintf("removing subagents: %v", err)
+			results = append(results, r)
+			continue
+		}
+
+		// 4. Update the index file.
+		if err := removeFromIndex(root, idxPath, s.SessionID); err != nil {
 			r.Success = false
 			r.Error = fmt.Sprintf("updating index: %v", err)
+		} else if artErr != nil {
+			r.Success = false
+			r.Error = artErr.Error()
 		}
 
 		results = append(results, r)
@@ -305,15 +416,10 @@ func Rename(s Session, newTitle string) error {
 		return fmt.Errorf("marshaling custom-title: %w", err)
 	}
 
-	f, err := os.OpenFile(s.FullPath, os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("opening session file: %w", err)
-	}
-	defer f.Close()
-
-	if _, err := f.Write(append([]byte("\n"), data...)); err != nil {
+	if err := claude.For(s.Profile).AppendFile(s.FullPath, append([]byte("\n"), data...)); err != nil {
 		return fmt.Errorf("writing custom-title: %w", err)
 	}
+	audit.Record(audit.Entry{Op: audit.OpRename, Kind: audit.KindSession, ID: s.SessionID, Paths: []string{s.FullPath}, Before: s.Title(), After: newTitle})
 
 	return nil
 }
@@ -331,18 +437,9 @@ func ListProjectsWithProgress(progress chan<- LoadProgress) ([]Project, error) {
 	if progress != nil {
 		defer close(progress)
 	}
-	base := ClaudeDir()
-
-	entries, err := os.ReadDir(base)
+	dirs, err := claude.ProjectDirs()
 	if err != nil {
-		return nil, fmt.Errorf("reading projects dir: %w", err)
-	}
-
-	var dirs []os.DirEntry
-	for _, e := range entries {
-		if e.IsDir() {
-			dirs = append(dirs, e)
-		}
+		return nil, err
 	}
 
 	report := func(current, total int) {
@@ -358,16 +455,21 @@ func ListProjectsWithProgress(progress chan<- LoadProgress) ([]Project, error) {
 	var projects []Project
 	for i, dir := range dirs {
 		report(i+1, len(dirs))
-		dirName := dir.Name()
-		dirPath := filepath.Join(base, dirName)
+		dirName := dir.Name
+		dirPath := filepath.Join(dir.Root.ProjectsDir(), dirName)
 
 		// Try to read the index file first.
 		idxPath := filepath.Join(dirPath, "sessions-index.json")
-		if data, err := os.ReadFile(idxPath); err == nil {
+		if data, err := dir.Root.ReadFile(idxPath); err == nil {
 			var idx IndexFile
 			if err := json.Unmarshal(data, &idx); err == nil && len(idx.Entries) > 0 {
 				var projectPath, lastModified, lastPrompt string
+				var count int
 				for _, e := range idx.Entries {
+					if e.IsSidechain {
+						continue
+					}
+					count++
 					if projectPath == "" && e.ProjectPath != "" {
 						projectPath = e.ProjectPath
 					}
@@ -384,9 +486,11 @@ func ListProjectsWithProgress(progress chan<- LoadProgress) ([]Project, error) {
 					projectPath = decodeDirName(dirName)
 				}
 				projects = append(projects, Project{
+					Profile:      dir.Root.Name,
 					DirName:      dirName,
 					Path:         projectPath,
-					SessionCount: len(idx.Entries),
+					SessionCount: count,
+					TotalSize:    sessionFilesSize(dirPath),
 					LastModified: lastModified,
 					LastPrompt:   lastPrompt,
 				})
@@ -395,14 +499,16 @@ func ListProjectsWithProgress(progress chan<- LoadProgress) ([]Project, error) {
 		}
 
 		// No index or empty — fall back to counting .jsonl files.
-		jsonlFiles, _ := filepath.Glob(filepath.Join(dirPath, "*.jsonl"))
+		allFiles, _ := dir.Root.Glob(filepath.Join(dirPath, "*.jsonl"))
+		jsonlFiles, _ := splitAgentFiles(allFiles)
 		if len(jsonlFiles) == 0 {
 			continue
 		}
 
 		var lastModified time.Time
+		totalSize := sessionFilesSize(dirPath)
 		for _, jpath := range jsonlFiles {
-			info, err := os.Stat(jpath)
+			info, err := dir.Root.Stat(jpath)
 			if err != nil {
 				continue
 			}
@@ -415,8 +521,8 @@ func ListProjectsWithProgress(progress chan<- LoadProgress) ([]Project, error) {
 		var lastPrompt string
 		// Sort by mod time descending so we check newest first.
 		sort.Slice(jsonlFiles, func(a, b int) bool {
-			ai, _ := os.Stat(jsonlFiles[a])
-			bi, _ := os.Stat(jsonlFiles[b])
+			ai, _ := dir.Root.Stat(jsonlFiles[a])
+			bi, _ := dir.Root.Stat(jsonlFiles[b])
 			if ai == nil || bi == nil {
 				return false
 			}
@@ -430,9 +536,11 @@ func ListProjectsWithProgress(progress chan<- LoadProgress) ([]Project, error) {
 		}
 
 		projects = append(projects, Project{
+			Profile:      dir.Root.Name,
 			DirName:      dirName,
 			Path:         decodeDirName(dirName),
 			SessionCount: len(jsonlFiles),
+			TotalSize:    totalSize,
 			LastModified: lastModified.Format(time.RFC3339),
 			LastPrompt:   lastPrompt,
 		})
@@ -446,16 +554,85 @@ func ListProjectsWithProgress(progress chan<- LoadProgress) ([]Project, error) {
 	return projects, nil
 }
 
+// rootFile is a file in one of the active Roots.
+type rootFile struct {
+	profile string
+	path    string
+}
+
+// globRoots returns the files matching a pattern under the projects
+// directory of every active Root.
+func globRoots(pattern ...string) ([]rootFile, error) {
+	return globIn(claude.Roots(), pattern...)
+}
+
+// globIn returns the files matching a pattern under the projects
+// directory of each of roots.
+func globIn(roots []*claude.Root, pattern ...string) ([]rootFile, error) {
+	var files []rootFile
+	for _, r := range roots {
+		matches, err := r.Glob(filepath.Join(append([]string{r.ProjectsDir()}, pattern...)...))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			files = append(files, rootFile{profile: r.Name, path: m})
+		}
+	}
+	return files, nil
+}
+
+// sessionFilesSize returns the combined size of the session and subagent
+// .jsonl files in a project directory.
+func sessionFilesSize(dirPath string) int64 {
+	root := claude.RootOf(dirPath)
+	files, _ := root.Glob(filepath.Join(dirPath, "*.jsonl"))
+	nested, _ := root.Glob(filepath.Join(dirPath, "*", "subagents", "*.jsonl"))
+	files = append(files, nested...)
+	var total int64
+	for _, f := range files {
+		if info, err := root.Stat(f); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
 // ListSessions returns all sessions for a given project directory,
 // sorted by modified date descending. It also enriches sessions with
-// custom titles from JSONL files.
-func ListSessions(projectDir string) ([]Session, error) {
-	base := ClaudeDir()
-	projPath := filepath.Join(base, projectDir)
+// custom titles from JSONL files. profile names the data directory the
+// project is in; it is empty without profiles.
+func ListSessions(profile, projectDir string) ([]Session, error) {
+	r := claude.Lookup(profile)
+	if r == nil {
+		return nil, fmt.Errorf("no profile named %q", profile)
+	}
+	sessions, err := listSessions(r, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	attachMeta(sessions)
+	markLive(sessions)
+	return sessions, nil
+}
+
+// listSessions is ListSessions without clsm's tags and pins.
+func listSessions(r *claude.Root, projectDir string) ([]Session, error) {
+	sessions, err := listRootSessions(r, projectDir)
+	for i := range sessions {
+		sessions[i].Profile = r.Name
+	}
+	return sessions, err
+}
+
+// listRootSessions is listSessions without the profile.
+func listRootSessions(r *claude.Root, projectDir string) ([]Session, error) {
+	projPath := filepath.Join(r.ProjectsDir(), projectDir)
 
 	// Build a map of custom titles from JSONL files.
 	customTitles := make(map[string]string)
-	jsonlFiles, _ := filepath.Glob(filepath.Join(projPath, "*.jsonl"))
+	allFiles, _ := r.Glob(filepath.Join(projPath, "*.jsonl"))
+	jsonlFiles, _ := splitAgentFiles(allFiles)
 	for _, jpath := range jsonlFiles {
 		title, sessionID := findCustomTitle(jpath)
 		if title != "" {
@@ -465,16 +642,19 @@ func ListSessions(projectDir string) ([]Session, error) {
 
 	// Try to read the index file.
 	idxPath := filepath.Join(projPath, "sessions-index.json")
-	if data, err := os.ReadFile(idxPath); err == nil {
+	if data, err := r.ReadFile(idxPath); err == nil {
 		var idx IndexFile
 		if err := json.Unmarshal(data, &idx); err == nil && len(idx.Entries) > 0 {
 			sessions := make([]Session, 0, len(idx.Entries))
 			for _, e := range idx.Entries {
+				if e.IsSidechain {
+					continue
+				}
 				s := Session{
 					SessionID:   e.SessionID,
 					Project:     projectDir,
 					ProjectPath: e.ProjectPath,
-					FullPath:    e.FullPath,
+					FullPath:    transcriptPath(projPath, e),
 					Summary:     e.Summary,
 					FirstPrompt: e.FirstPrompt,
 					Created:     e.Created,
@@ -488,14 +668,11 @@ func ListSessions(projectDir string) ([]Session, error) {
 				sessions = append(sessions, s)
 			}
 
-			sort.Slice(sessions, func(i, j int) bool {
-				ti, _ := time.Parse(time.RFC3339, sessions[i].Modified)
-				tj, _ := time.Parse(time.RFC3339, sessions[j].Modified)
-				return ti.After(tj)
-			})
-
 			// Enrich sessions with missing data from JSONL files.
 			for i := range sessions {
+				if info, err := r.Stat(sessions[i].FullPath); err == nil {
+					sessions[i].Size = info.Size()
+				}
 				if sessions[i].MsgCount == 0 || sessions[i].FirstPrompt == "" {
 					prompt, count := scanSession(sessions[i].FullPath)
 					if sessions[i].MsgCount == 0 {
@@ -507,6 +684,13 @@ func ListSessions(projectDir string) ([]Session, error) {
 				}
 			}
 
+			sessions = linkSubagents(projectDir, projPath, sessions)
+			sort.Slice(sessions, func(i, j int) bool {
+				ti, _ := time.Parse(time.RFC3339, sessions[i].Modified)
+				tj, _ := time.Parse(time.RFC3339, sessions[j].Modified)
+				return ti.After(tj)
+			})
+
 			return sessions, nil
 		}
 	}
@@ -514,30 +698,13 @@ func ListSessions(projectDir string) ([]Session, error) {
 	// No index — build sessions from .jsonl files.
 	sessions := make([]Session, 0, len(jsonlFiles))
 	for _, jpath := range jsonlFiles {
-		fname := filepath.Base(jpath)
-		sessionID := strings.TrimSuffix(fname, ".jsonl")
-
-		var modified string
-		if info, err := os.Stat(jpath); err == nil {
-			modified = info.ModTime().Format(time.RFC3339)
-		}
-
-		firstPrompt, msgCount := scanSession(jpath)
-
-		s := Session{
-			SessionID:   sessionID,
-			Project:     projectDir,
-			ProjectPath: decodeDirName(projectDir),
-			FullPath:    jpath,
-			Modified:    modified,
-			MsgCount:    msgCount,
-			FirstPrompt: firstPrompt,
-		}
-		if t, ok := customTitles[sessionID]; ok {
+		s := sessionFromFile(projectDir, jpath)
+		if t, ok := customTitles[s.SessionID]; ok {
 			s.CustomTitle = t
 		}
 		sessions = append(sessions, s)
 	}
+	sessions = linkSubagents(projectDir, projPath, sessions)
 
 	sort.Slice(sessions, func(i, j int) bool {
 		ti, _ := time.Parse(time.RFC3339, sessions[i].Modified)
@@ -548,6 +715,41 @@ func ListSessions(projectDir string) ([]Session, error) {
 	return sessions, nil
 }
 
+// transcriptPath returns the transcript of an index entry in the project
+// directory projPath. The entry's fullPath is only used if it is in that
+// directory, since an index copied from elsewhere, such as a backup or
+// another profile, still names the transcript it was copied from.
+func transcriptPath(projPath string, e IndexEntry) string {
+	if filepath.Dir(e.FullPath) == filepath.Clean(projPath) {
+		return e.FullPath
+	}
+	return filepath.Join(projPath, e.SessionID+".jsonl")
+}
+
+// sessionFromFile builds a Session from a JSONL file alone, for sessions
+// that have no index entry. It does not look up the custom title.
+func sessionFromFile(projectDir, jpath string) Session {
+	var modified string
+	var size int64
+	if info, err := claude.RootOf(jpath).Stat(jpath); err == nil {
+		modified = info.ModTime().Format(time.RFC3339)
+		size = info.Size()
+	}
+
+	firstPrompt, msgCount := scanSession(jpath)
+
+	return Session{
+		SessionID:   strings.TrimSuffix(filepath.Base(jpath), ".jsonl"),
+		Project:     projectDir,
+		ProjectPath: decodeDirName(projectDir),
+		FullPath:    jpath,
+		Modified:    modified,
+		MsgCount:    msgCount,
+		FirstPrompt: firstPrompt,
+		Size:        size,
+	}
+}
+
 // extractFirstPrompt reads a JSONL session file and returns the content of
 // the first user message. Returns empty string if none found.
 func extractFirstPrompt(path string) string {
@@ -559,7 +761,7 @@ func extractFirstPrompt(path string) string {
 // session file in a single pass. Messages are lines with type "user" or
 // "assistant".
 func scanSession(path string) (firstPrompt string, msgCount int) {
-	f, err := os.Open(path)
+	f, err := claude.RootOf(path).Open(path)
 	if err != nil {
 		return "", 0
 	}
@@ -607,6 +809,26 @@ func decodeDirName(name string) string {
 	return "/" + s
 }
 
+// EncodeDirName returns the directory name Claude Code keeps a project's
+// sessions under: the path with every character other than an ASCII
+// letter or digit replaced by "-". Like Claude Code, it counts characters
+// in UTF-16 units, so a character outside the BMP becomes "--".
+// e.g. "/Users/barryhall/.config" -> "-Users-barryhall--config"
+func EncodeDirName(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			b.WriteRune(r)
+		case r > 0xFFFF:
+			b.WriteString("--")
+		default:
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
 // ListAllSessions returns all sessions across all projects, sorted by
 // most recently modified.
 func ListAllSessions() ([]Session, error) {
@@ -620,18 +842,9 @@ func ListAllSessionsWithProgress(progress chan<- LoadProgress) ([]Session, error
 	if progress != nil {
 		defer close(progress)
 	}
-	base := ClaudeDir()
-
-	entries, err := os.ReadDir(base)
+	dirs, err := claude.ProjectDirs()
 	if err != nil {
-		return nil, fmt.Errorf("reading projects dir: %w", err)
-	}
-
-	var dirs []os.DirEntry
-	for _, e := range entries {
-		if e.IsDir() {
-			dirs = append(dirs, e)
-		}
+		return nil, err
 	}
 
 	report := func(current, total int) {
@@ -647,7 +860,7 @@ func ListAllSessionsWithProgress(progress chan<- LoadProgress) ([]Session, error
 	var allSessions []Session
 	for i, dir := range dirs {
 		report(i+1, len(dirs))
-		sessions, err := ListSessions(dir.Name())
+		sessions, err := listSessions(dir.Root, dir.Name)
 		if err != nil {
 			continue
 		}
@@ -657,38 +870,97 @@ func ListAllSessionsWithProgress(progress chan<- LoadProgress) ([]Session, error
 	sort.Slice(allSessions, func(i, j int) bool {
 		return allSessions[i].Modified > allSessions[j].Modified
 	})
+	attachMeta(allSessions)
+	markLive(allSessions)
 
 	return allSessions, nil
 }
 
-// removeFromIndex reads the index file, filters out the given session ID,
-// and writes it back.
-func removeFromIndex(idxPath, sessionID string) error {
-	data, err := os.ReadFile(idxPath)
+// Find returns the session whose ID equals idOrPrefix or is the only one
+// that starts with it. If project is non-empty, only sessions whose project
+// directory name equals it or whose project path contains it are considered.
+func Find(idOrPrefix, project string) (Session, error) {
+	return FindIn(claude.Roots(), idOrPrefix, project)
+}
+
+// FindIn is Find over only some of the active Roots.
+func FindIn(roots []*claude.Root, idOrPrefix, project string) (Session, error) {
+	if idOrPrefix == "" {
+		return Session{}, fmt.Errorf("empty session ID")
+	}
+	// Narrow down by file name first so only the projects that can contain
+	// a match are loaded. The prefix is compared rather than globbed, as
+	// it may hold pattern characters.
+	files, err := globIn(roots, "*", "*.jsonl")
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil // no index to update
-		}
-		return err
+		return Session{}, fmt.Errorf("globbing session files: %w", err)
 	}
-
-	var idx IndexFile
-	if err := json.Unmarshal(data, &idx); err != nil {
-		return fmt.Errorf("parsing index: %w", err)
+	dirFiles := make(map[claude.ProjectDir][]string) // project dir -> matching files
+	for _, f := range files {
+		if !strings.HasPrefix(filepath.Base(f.path), idOrPrefix) {
+			continue
+		}
+		dir := claude.ProjectDir{Root: claude.Lookup(f.profile), Name: filepath.Base(filepath.Dir(f.path))}
+		dirFiles[dir] = append(dirFiles[dir], f.path)
 	}
 
-	filtered := make([]IndexEntry, 0, len(idx.Entries))
-	for _, e := range idx.Entries {
-		if e.SessionID != sessionID {
-			filtered = append(filtered, e)
+	var exact, matches []Session
+	for pd, files := range dirFiles {
+		dir := pd.Name
+		sessions, err := listSessions(pd.Root, dir)
+		if err != nil {
+			continue
+		}
+		// Files that the project's index doesn't know about yet.
+		listed := make(map[string]bool, len(sessions))
+		for _, s := range sessions {
+			listed[s.SessionID] = true
+		}
+		for _, f := range files {
+			if isAgentFile(f) {
+				continue // linked to its parent by listSessions
+			}
+			if s := sessionFromFile(dir, f); !listed[s.SessionID] {
+				s.Profile = pd.Root.Name
+				s.CustomTitle, _ = findCustomTitle(f)
+				sessions = append(sessions, s)
+			}
+		}
+		for _, s := range sessions {
+			if !strings.HasPrefix(s.SessionID, idOrPrefix) {
+				continue
+			}
+			if project != "" && s.Project != project &&
+				!strings.Contains(strings.ToLower(s.ProjectPath), strings.ToLower(project)) {
+				continue
+			}
+			if s.SessionID == idOrPrefix {
+				exact = append(exact, s)
+			}
+			matches = append(matches, s)
 		}
 	}
-	idx.Entries = filtered
 
-	out, err := json.MarshalIndent(idx, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling index: %w", err)
+	// A full ID is unambiguous unless the session is in several profiles.
+	if len(exact) > 0 {
+		matches = exact
+	}
+	switch len(matches) {
+	case 0:
+		return Session{}, fmt.Errorf("no session matches %q", idOrPrefix)
+	case 1:
+		found := []Session{withMeta(matches[0])}
+		markLive(found)
+		return found[0], nil
 	}
 
-	return os.WriteFile(idxPath, out, 0644)
+	ids := make([]string, len(matches))
+	for i, s := range matches {
+		ids[i] = s.SessionID
+		if s.Profile != "" {
+			ids[i] = s.Profile + ":" + s.SessionID
+		}
+	}
+	sort.Strings(ids)
+	return Session{}, fmt.Errorf("%q matches %d sessions: %s", idOrPrefix, len(matches), strings.Join(ids, ", "))
 }
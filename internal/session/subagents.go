@@ -0,0 +1,153 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+// Claude Code writes a subagent's transcript either as an agent-<id>.jsonl
+// sidechain file beside the sessions, whose entries carry the parent's
+// sessionId, or as <session-id>/subagents/agent-<id>.jsonl.
+const agentFilePrefix = "agent-"
+
+// isAgentFile reports whether path is a top-level sidechain transcript
+// rather than a session.
+func isAgentFile(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), agentFilePrefix)
+}
+
+// splitAgentFiles separates a project's top-level JSONL files into session
+// transcripts and sidechain transcripts.
+func splitAgentFiles(files []string) (sessions, agents []string) {
+	for _, f := range files {
+		if isAgentFile(f) {
+			agents = append(agents, f)
+		} else {
+			sessions = append(sessions, f)
+		}
+	}
+	return sessions, agents
+}
+
+// findSubagents returns the subagent transcripts in a project directory,
+// keyed by the ID of the parent session. Sidechain files whose parent can't
+// be read are keyed by their own file name.
+func findSubagents(projPath string) map[string][]Subagent {
+	subs := make(map[string][]Subagent)
+
+	root := claude.RootOf(projPath)
+	agentFiles, _ := root.Glob(filepath.Join(projPath, agentFilePrefix+"*.jsonl"))
+	for _, f := range agentFiles {
+		parent := sidechainParent(f)
+		if parent == "" {
+			parent = strings.TrimSuffix(filepath.Base(f), ".jsonl")
+		}
+		subs[parent] = append(subs[parent], subagentFromFile(f))
+	}
+
+	nested, _ := root.Glob(filepath.Join(projPath, "*", "subagents", "*.jsonl"))
+	for _, f := range nested {
+		parent := filepath.Base(filepath.Dir(filepath.Dir(f)))
+		subs[parent] = append(subs[parent], subagentFromFile(f))
+	}
+
+	for _, list := range subs {
+		sort.Slice(list, func(i, j int) bool { return list[i].Modified < list[j].Modified })
+	}
+	return subs
+}
+
+// linkSubagents attaches subagent transcripts to their parent sessions and
+// adds their size to the parent's. Sidechain files whose parent session
+// file is gone are returned as sessions of their own so they can still be found
+// and deleted.
+func linkSubagents(projectDir, projPath string, sessions []Session) []Session {
+	subs := findSubagents(projPath)
+	if len(subs) == 0 {
+		return sessions
+	}
+	for i := range sessions {
+		list, ok := subs[sessions[i].SessionID]
+		if !ok {
+			continue
+		}
+		sessions[i].Subagents = list
+		for _, a := range list {
+			sessions[i].Size += a.Size
+		}
+		delete(subs, sessions[i].SessionID)
+	}
+	for parent, list := range subs {
+		if _, err := claude.RootOf(projPath).Stat(filepath.Join(projPath, parent+".jsonl")); err == nil {
+			continue // the parent exists but isn't listed
+		}
+		for _, a := range list {
+			if isAgentFile(a.FullPath) {
+				sessions = append(sessions, sessionFromFile(projectDir, a.FullPath))
+			}
+		}
+	}
+	return sessions
+}
+
+// sidechainParent returns the sessionId recorded in a sidechain file, which
+// is the ID of the session that started the agent.
+func sidechainParent(path string) string {
+	f, err := claude.RootOf(path).Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry struct {
+			SessionID string `json:"sessionId"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil && entry.SessionID != "" {
+			return entry.SessionID
+		}
+	}
+	return ""
+}
+
+// subagentFromFile builds a Subagent from its transcript file.
+func subagentFromFile(path string) Subagent {
+	a := Subagent{
+		AgentID:  strings.TrimPrefix(strings.TrimSuffix(filepath.Base(path), ".jsonl"), agentFilePrefix),
+		FullPath: path,
+	}
+	if info, err := claude.RootOf(path).Stat(path); err == nil {
+		a.Modified = info.ModTime().Format(time.RFC3339)
+		a.Size = info.Size()
+	}
+	a.Description, a.MsgCount = scanSession(path)
+	return a
+}
+
+// removeSubagents deletes the subagent transcripts of a session and the
+// session's subagents directory, and then the session's own directory if
+// nothing else is left in it.
+func removeSubagents(projPath, sessionID string, subs []Subagent) error {
+	root := claude.RootOf(projPath)
+	for _, a := range subs {
+		if err := root.Remove(a.FullPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if sessionID == "" {
+		return nil
+	}
+	dir := filepath.Join(projPath, sessionID)
+	root.Remove(filepath.Join(dir, "subagents"))
+	root.Remove(dir)
+	return nil
+}
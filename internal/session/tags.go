@@ -0,0 +1,53 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/baz-sh/clsm/internal/meta"
+)
+
+// attachMeta fills Tags, Pinned and Note from clsm's sidecar store.
+// Listing should keep working if the store can't be read, so errors leave
+// the sessions untagged; SplitProtected reports them instead.
+func attachMeta(sessions []Session) {
+	notes, _ := meta.Notes(meta.NoteSession)
+	for i := range sessions {
+		sessions[i].Note = notes[sessions[i].SessionID]
+	}
+
+	st, err := meta.Load()
+	if err != nil {
+		return
+	}
+	for i := range sessions {
+		m := st.Get(sessions[i].SessionID)
+		sessions[i].Tags = m.Tags
+		sessions[i].Pinned = m.Pinned
+	}
+}
+
+// withMeta returns s with its tags and pin attached.
+func withMeta(s Session) Session {
+	one := []Session{s}
+	attachMeta(one)
+	return one[0]
+}
+
+// SplitProtected separates sessions that may be bulk-deleted from those
+// that are tagged or pinned. It reads the sidecar store afresh, so tags
+// added since the sessions were listed still count, and fails if the store
+// can't be read rather than risk deleting protected sessions.
+func SplitProtected(sessions []Session) (deletable, protected []Session, err error) {
+	st, err := meta.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("checking tags: %w", err)
+	}
+	for _, s := range sessions {
+		if st.Get(s.SessionID).Protected() {
+			protected = append(protected, s)
+		} else {
+			deletable = append(deletable, s)
+		}
+	}
+	return deletable, protected, nil
+}
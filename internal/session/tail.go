@@ -0,0 +1,153 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+// Entry is a transcript line reduced to what a reader follows: who spoke,
+// when, what they said and which tools were called.
+type Entry struct {
+	Type    string    // "user", "assistant", "summary" or "custom-title"
+	Time    time.Time // zero if the line has no timestamp
+	Text    string    // message text, summary or title
+	Tools   []string  // tool calls, e.g. `Bash(go test ./...)`
+	Results int       // tool results carried by a user entry
+}
+
+// ParseEntry decodes one JSONL transcript line. It reports false for lines
+// that carry nothing worth showing.
+func ParseEntry(data []byte) (Entry, bool) {
+	var line transcriptLine
+	if err := json.Unmarshal(data, &line); err != nil {
+		return Entry{}, false
+	}
+	e := Entry{Type: line.Type}
+	if t, err := time.Parse(time.RFC3339Nano, line.Timestamp); err == nil {
+		e.Time = t
+	}
+
+	switch line.Type {
+	case "summary":
+		e.Text = line.Summary
+	case "custom-title":
+		var ct CustomTitle
+		json.Unmarshal(data, &ct)
+		e.Text = ct.CustomTitle
+	case "user", "assistant":
+		var text []string
+		for _, b := range line.blocks() {
+			switch b.Type {
+			case "text":
+				if t := strings.TrimSpace(b.Text); t != "" {
+					text = append(text, t)
+				}
+			case "tool_use":
+				e.Tools = append(e.Tools, b.Name+"("+toolArg(b.Input)+")")
+			case "tool_result":
+				e.Results++
+			}
+		}
+		e.Text = strings.Join(text, "\n")
+	default:
+		return Entry{}, false
+	}
+	if e.Text == "" && len(e.Tools) == 0 && e.Results == 0 {
+		return Entry{}, false
+	}
+	return e, true
+}
+
+// toolArg picks the most telling argument of a tool call for display: a
+// command, path, pattern or description, cut to one short line.
+func toolArg(input json.RawMessage) string {
+	var args map[string]any
+	if json.Unmarshal(input, &args) != nil {
+		return ""
+	}
+	for _, k := range []string{"command", "file_path", "path", "pattern", "url", "query", "description", "prompt"} {
+		if v, ok := args[k].(string); ok && v != "" {
+			v = strings.Join(strings.Fields(v), " ")
+			if r := []rune(v); len(r) > 60 {
+				v = string(r[:59]) + "…"
+			}
+			return v
+		}
+	}
+	return ""
+}
+
+// Tailer reads the entries appended to a transcript since the last read.
+type Tailer struct {
+	path    string
+	offset  int64
+	partial []byte // an incomplete last line, kept until its newline arrives
+}
+
+// NewTailer opens a transcript for following. It returns up to last
+// existing entries (all of them if last is negative) and a Tailer
+// positioned at the end of the file.
+func NewTailer(path string, last int) (*Tailer, []Entry, error) {
+	t := &Tailer{path: path}
+	entries, err := t.Poll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if last >= 0 && len(entries) > last {
+		entries = entries[len(entries)-last:]
+	}
+	return t, entries, nil
+}
+
+// Poll returns the entries written since the previous call. If the file has
+// shrunk, for example because it was rewritten, it is read again from the
+// start.
+func (t *Tailer) Poll() ([]Entry, error) {
+	f, err := claude.RootOf(t.path).Open(t.path)
+	if err != nil {
+		return nil, fmt.Errorf("opening session file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < t.offset {
+		t.offset, t.partial = 0, nil
+	}
+	if info.Size() == t.offset {
+		return nil, nil
+	}
+	if seeker, ok := f.(io.Seeker); ok {
+		_, err = seeker.Seek(t.offset, io.SeekStart)
+	} else {
+		_, err = io.CopyN(io.Discard, f, t.offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading session file: %w", err)
+	}
+	t.offset += int64(len(data))
+
+	data = append(t.partial, data...)
+	end := bytes.LastIndexByte(data, '\n')
+	t.partial = append([]byte(nil), data[end+1:]...)
+
+	var entries []Entry
+	for _, line := range bytes.Split(data[:end+1], []byte("\n")) {
+		if e, ok := ParseEntry(line); ok {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,130 @@
+package session
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TemplateFields lists the placeholders ExpandTitle understands, for help
+// text.
+const TemplateFields = "{title} {summary} {prompt} {project} {branch} {date} {id}"
+
+var placeholderRe = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// titleSeparators are the characters dropped around an empty placeholder.
+const titleSeparators = ":-–—/|,; "
+
+// titleOpeners and titleClosers are the brackets dropped around an empty
+// placeholder, such as the ones in "[{branch}]".
+const (
+	titleOpeners = "([<"
+	titleClosers = ")]>"
+)
+
+// ExpandTitle fills a title template such as "{branch}: {title}" or
+// "[{date}] {summary}" with the session's values:
+//
+//	{title}    the current title (custom title, summary or first prompt)
+//	{summary}  Claude Code's summary, or the first prompt if there is none
+//	{prompt}   the first line of the first prompt
+//	{project}  the last element of the project path
+//	{branch}   the git branch
+//	{date}     the creation date, YYYY-MM-DD
+//	{id}       the first block of the session ID
+//
+// Empty values leave their placeholder out along with any brackets around
+// it and one separator, so "{branch}: {title}" and "[{branch}] {title}"
+// give just the title on sessions without a branch.
+// Unknown placeholders are an error.
+func ExpandTitle(template string, s Session) (string, error) {
+	var unknown string
+	values := make(map[string]string)
+	for _, m := range placeholderRe.FindAllStringSubmatch(template, -1) {
+		v, ok := templateValue(m[1], s)
+		if !ok {
+			unknown = m[0]
+			break
+		}
+		values[m[0]] = v
+	}
+	if unknown != "" {
+		return "", fmt.Errorf("unknown placeholder %s (use %s)", unknown, TemplateFields)
+	}
+
+	var b strings.Builder
+	rest := template
+	for {
+		loc := placeholderRe.FindStringIndex(rest)
+		if loc == nil {
+			b.WriteString(rest)
+			break
+		}
+		b.WriteString(rest[:loc[0]])
+		v := values[rest[loc[0]:loc[1]]]
+		rest = rest[loc[1]:]
+		if v == "" {
+			// Drop brackets around the placeholder, then the separator that
+			// joined it to what came before it, or failing that the one
+			// that follows it.
+			done := b.String()
+			if n := len(done); n > 0 {
+				if i := strings.IndexByte(titleOpeners, done[n-1]); i >= 0 && strings.HasPrefix(rest, titleClosers[i:i+1]) {
+					done, rest = done[:n-1], rest[1:]
+					b.Reset()
+					b.WriteString(done)
+				}
+			}
+			if trimmed := strings.TrimRight(done, titleSeparators); trimmed != done && trimmed != "" {
+				b.Reset()
+				b.WriteString(trimmed)
+			} else {
+				rest = strings.TrimLeft(rest, titleSeparators)
+			}
+			continue
+		}
+		b.WriteString(v)
+	}
+	return strings.Join(strings.Fields(b.String()), " "), nil
+}
+
+func templateValue(name string, s Session) (string, bool) {
+	switch name {
+	case "title":
+		return firstLineOf(s.Title()), true
+	case "summary":
+		if s.Summary != "" {
+			return s.Summary, true
+		}
+		return firstLineOf(s.FirstPrompt), true
+	case "prompt":
+		return firstLineOf(s.FirstPrompt), true
+	case "project":
+		if s.ProjectPath == "" {
+			return "", true
+		}
+		return filepath.Base(s.ProjectPath), true
+	case "branch":
+		return s.GitBranch, true
+	case "date":
+		t, err := time.Parse(time.RFC3339Nano, s.Created)
+		if err != nil {
+			if t, err = time.Parse(time.RFC3339Nano, s.Modified); err != nil {
+				return "", true
+			}
+		}
+		return t.Local().Format("2006-01-02"), true
+	case "id":
+		id, _, _ := strings.Cut(s.SessionID, "-")
+		return id, true
+	}
+	return "", false
+}
+
+// firstLineOf returns the first non-blank line of text, shortened like a
+// derived title.
+func firstLineOf(text string) string {
+	return shortenTitle(text, maxTitleLen)
+}
@@ -0,0 +1,62 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandTitle(t *testing.T) {
+	full := Session{
+		SessionID:   "3f2a8c1e-0b4d-4e5f-9a6b-7c8d9e0f1a2b",
+		Summary:     "Fix login redirect",
+		FirstPrompt: "the login page loops\nmore detail",
+		ProjectPath: "/home/alice/src/webapp",
+		GitBranch:   "fix/login",
+		Created:     "2026-03-04T12:00:00Z",
+	}
+	bare := Session{SessionID: full.SessionID, FirstPrompt: "hello"}
+
+	tests := []struct {
+		name     string
+		template string
+		s        Session
+		want     string
+		wantErr  bool
+	}{
+		{"all values", "{branch}: {title}", full, "fix/login: Fix login redirect", false},
+		{"summary", "{summary}", full, "Fix login redirect", false},
+		{"summary falls back to prompt", "{summary}", bare, "hello", false},
+		{"prompt first line", "{prompt}", full, "the login page loops", false},
+		{"project and id", "{project} {id}", full, "webapp 3f2a8c1e", false},
+		{"date", "[{date}] {summary}", full, "[2026-03-04] Fix login redirect", false},
+		{"empty before separator", "{branch}: {title}", bare, "hello", false},
+		{"empty after separator", "{title} - {branch}", bare, "hello", false},
+		{"empty in brackets", "[{branch}] {title}", bare, "hello", false},
+		{"empty in brackets after text", "{title} ({branch})", bare, "hello", false},
+		{"empty next to literal text", "WIP {branch}: {title}", bare, "WIP: hello", false},
+		{"empty glued to literal text", "v{branch}x {title}", bare, "vx hello", false},
+		{"empty between literal words", "before {branch} after", bare, "before after", false},
+		{"all empty", "{branch}", bare, "", false},
+		{"no placeholders", "Release notes", bare, "Release notes", false},
+		{"spaces collapsed", "  {title}   x ", full, "Fix login redirect x", false},
+		{"unknown placeholder", "{nope} {title}", full, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandTitle(tt.template, tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandTitle(%q): error %v, want error %v", tt.template, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandTitle(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandTitleUnknownNamesFields(t *testing.T) {
+	_, err := ExpandTitle("{nope}", Session{})
+	if err == nil || !strings.Contains(err.Error(), "{nope}") || !strings.Contains(err.Error(), TemplateFields) {
+		t.Errorf("got %v, want it to name {nope} and list %s", err, TemplateFields)
+	}
+}
@@ -0,0 +1,158 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+// transcriptLine is the subset of a JSONL transcript entry that clsm reads.
+type transcriptLine struct {
+	Type      string `json:"type"`
+	Timestamp string `json:"timestamp"`
+	Cwd       string `json:"cwd"`
+	Version   string `json:"version"`
+	Summary   string `json:"summary"`
+	Message   struct {
+		Role    string          `json:"role"`
+		Model   string          `json:"model"`
+		Content json.RawMessage `json:"content"`
+		Usage   struct {
+			InputTokens              int `json:"input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// contentBlock is one element of a message's content array.
+type contentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+// blocks returns the message content as content blocks. Plain string
+// content is returned as a single text block.
+func (l transcriptLine) blocks() []contentBlock {
+	if len(l.Message.Content) == 0 {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(l.Message.Content, &text); err == nil {
+		return []contentBlock{{Type: "text", Text: text}}
+	}
+	var blocks []contentBlock
+	json.Unmarshal(l.Message.Content, &blocks)
+	return blocks
+}
+
+// Summarize reads a session's JSONL file and aggregates message counts,
+// timestamps, models, token usage and tool calls.
+func Summarize(path string) (TranscriptSummary, error) {
+	f, err := claude.RootOf(path).Open(path)
+	if err != nil {
+		return TranscriptSummary{}, fmt.Errorf("opening session file: %w", err)
+	}
+	defer f.Close()
+
+	sum := TranscriptSummary{ToolUses: make(map[string]int)}
+	if info, err := f.Stat(); err == nil {
+		sum.Size = info.Size()
+	}
+	seenModels := make(map[string]bool)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		var line transcriptLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		sum.Entries++
+
+		if line.Timestamp != "" {
+			if sum.FirstTimestamp == "" {
+				sum.FirstTimestamp = line.Timestamp
+			}
+			sum.LastTimestamp = line.Timestamp
+		}
+		if sum.Cwd == "" {
+			sum.Cwd = line.Cwd
+		}
+		if sum.Version == "" {
+			sum.Version = line.Version
+		}
+
+		switch line.Type {
+		case "user":
+			sum.UserMessages++
+		case "assistant":
+			sum.AssistantMessages++
+			if m := line.Message.Model; m != "" && !seenModels[m] {
+				seenModels[m] = true
+				sum.Models = append(sum.Models, m)
+			}
+			u := line.Message.Usage
+			sum.InputTokens += u.InputTokens
+			sum.OutputTokens += u.OutputTokens
+			sum.CacheReadTokens += u.CacheReadInputTokens
+			sum.CacheCreationTokens += u.CacheCreationInputTokens
+			for _, b := range line.blocks() {
+				if b.Type == "tool_use" && b.Name != "" {
+					sum.ToolUses[b.Name]++
+				}
+			}
+		case "summary":
+			if line.Summary != "" {
+				sum.Summaries = append(sum.Summaries, line.Summary)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return sum, fmt.Errorf("reading session file: %w", err)
+	}
+
+	return sum, nil
+}
+
+// SummarizeSession is like Summarize but also summarizes the session's
+// subagents. Their tokens, tool calls, models and size are added to the
+// totals; message counts and timestamps are the session's own.
+func SummarizeSession(s Session) (TranscriptSummary, error) {
+	sum, err := Summarize(s.FullPath)
+	if err != nil {
+		return sum, err
+	}
+	for _, a := range s.Subagents {
+		sub, err := Summarize(a.FullPath)
+		if err != nil {
+			continue
+		}
+		sum.InputTokens += sub.InputTokens
+		sum.OutputTokens += sub.OutputTokens
+		sum.CacheReadTokens += sub.CacheReadTokens
+		sum.CacheCreationTokens += sub.CacheCreationTokens
+		sum.Size += sub.Size
+		for name, n := range sub.ToolUses {
+			sum.ToolUses[name] += n
+		}
+		for _, m := range sub.Models {
+			if !slices.Contains(sum.Models, m) {
+				sum.Models = append(sum.Models, m)
+			}
+		}
+		sum.Subagents = append(sum.Subagents, SubagentSummary{
+			AgentID:           a.AgentID,
+			Description:       a.Description,
+			TranscriptSummary: sub,
+		})
+	}
+	return sum, nil
+}
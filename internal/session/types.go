@@ -1,24 +1,126 @@
 package session
 
+import "strings"
+
 // Session represents a Claude Code session with metadata from
 // both the index file and the JSONL session file.
 type Session struct {
 	SessionID   string
+	Profile     string // profile of the data directory it is in; empty without profiles
 	Project     string // project directory name
 	ProjectPath string // original project path (e.g. /Users/<USERNAME>/.config)
 	FullPath    string // absolute path to .jsonl file
 	Summary     string
 	FirstPrompt string
 	CustomTitle string // from JSONL custom-title entry (if any)
-	MatchSource string // "custom-title", "summary", or "firstPrompt"
+	MatchSource string // "custom-title", "summary", "project" or "note"
 	MatchValue  string // the value that matched the search
 	Created     string
 	Modified    string
 	MsgCount    int
 	GitBranch   string
+	BranchGone  bool       // GitBranch no longer exists in the local repo (see MarkGoneBranches)
+	Size        int64      // size of the .jsonl file and its subagents' files in bytes
+	Tags        []string   // clsm tags from the sidecar store
+	Pinned      bool       // pinned in clsm's sidecar store
+	Note        string     // markdown note from clsm's sidecar store
+	Live        string     // why Claude Code seems to be writing it right now; empty if it isn't
+	Subagents   []Subagent // transcripts of agents started by this session, oldest first
+}
+
+// Subagent is the transcript of an agent (a Task tool call) started by a
+// session. Claude Code writes it to its own JSONL file.
+type Subagent struct {
+	AgentID     string
+	FullPath    string // absolute path to the agent's .jsonl file
+	Description string // the prompt the agent was given
+	Modified    string
+	MsgCount    int
+	Size        int64
+}
+
+// HasTag reports whether the session carries the tag. An empty tag matches
+// any tagged session.
+func (s Session) HasTag(tag string) bool {
+	if tag == "" {
+		return len(s.Tags) > 0
+	}
+	for _, t := range s.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Key identifies a session among those of every profile, since the same
+// session ID can be in more than one data directory.
+func (s Session) Key() string {
+	return Key(s.Profile, s.SessionID)
+}
+
+// Key returns the key of the session with the given ID in a profile.
+func Key(profile, id string) string {
+	return profile + "/" + id
+}
+
+// Protected reports whether the session is tagged or pinned. Protected
+// sessions are skipped by prune and bulk delete.
+func (s Session) Protected() bool {
+	return len(s.Tags) > 0 || s.Pinned
+}
+
+// Title returns the best human-readable title for the session: the custom
+// title, then the summary, then the first line of the first prompt, and
+// finally the session ID.
+func (s Session) Title() string {
+	switch {
+	case s.CustomTitle != "":
+		return s.CustomTitle
+	case s.Summary != "":
+		return s.Summary
+	}
+	for _, line := range strings.Split(s.FirstPrompt, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return s.SessionID
+}
+
+// Matches reports whether the session matches a list filter term. It is a
+// case-insensitive substring match over the summary, custom title, first
+// prompt and note. Words of the form tag:name additionally require the
+// session to carry that tag; a bare tag: requires any tag. Words of the
+// form @name require it to be in that profile.
+func (s Session) Matches(term string) bool {
+	term = strings.ToLower(term)
+	if strings.Contains(term, "tag:") || strings.Contains(term, "@") {
+		var words []string
+		for _, w := range strings.Fields(term) {
+			if tag, ok := strings.CutPrefix(w, "tag:"); ok {
+				if !s.HasTag(tag) {
+					return false
+				}
+				continue
+			}
+			if name, ok := strings.CutPrefix(w, "@"); ok && name != "" {
+				if !inProfile(s.Profile, name) {
+					return false
+				}
+				continue
+			}
+			words = append(words, w)
+		}
+		term = strings.Join(words, " ")
+	}
+	searchable := strings.ToLower(s.Summary + " " + s.CustomTitle + " " + s.FirstPrompt + " " + s.Note)
+	return strings.Contains(searchable, term)
 }
 
-// IndexFile represents the sessions-index.json structure.
+// IndexFile represents the sessions-index.json structure, for reading.
+// Rewrites go through the raw JSON instead, so fields clsm doesn't know
+// about are kept.
 type IndexFile struct {
 	Version int          `json:"version"`
 	Entries []IndexEntry `json:"entries"`
@@ -49,15 +151,81 @@ type CustomTitle struct {
 // DeleteResult tracks the outcome of deleting a single session.
 type DeleteResult struct {
 	SessionID string
+	Profile   string
 	Success   bool
 	Error     string
 }
 
+// Key returns the Key of the session the result is for.
+func (r DeleteResult) Key() string {
+	return Key(r.Profile, r.SessionID)
+}
+
+// TranscriptSummary aggregates the contents of a session's JSONL file.
+type TranscriptSummary struct {
+	Entries             int            `json:"entries"`           // lines in the file
+	UserMessages        int            `json:"userMessages"`      // entries with type "user"
+	AssistantMessages   int            `json:"assistantMessages"` // entries with type "assistant"
+	FirstTimestamp      string         `json:"firstTimestamp"`    // RFC3339 timestamp of the first entry
+	LastTimestamp       string         `json:"lastTimestamp"`     // RFC3339 timestamp of the last entry
+	Models              []string       `json:"models"`            // distinct assistant models, in order of first use
+	ToolUses            map[string]int `json:"toolUses"`          // tool name -> number of calls
+	InputTokens         int            `json:"inputTokens"`
+	OutputTokens        int            `json:"outputTokens"`
+	CacheReadTokens     int            `json:"cacheReadTokens"`
+	CacheCreationTokens int            `json:"cacheCreationTokens"`
+	Summaries           []string       `json:"summaries"` // summary entries written by Claude Code
+	Cwd                 string         `json:"cwd"`       // working directory of the first entry that has one
+	Version             string         `json:"version"`   // Claude Code version of the first entry that has one
+	Size                int64          `json:"size"`      // file size in bytes
+
+	// Subagents holds one summary per subagent transcript. Their tokens,
+	// tool calls, models and size are included in the totals above.
+	Subagents []SubagentSummary `json:"subagents,omitempty"`
+}
+
+// SubagentSummary is the transcript summary of one of a session's subagents.
+type SubagentSummary struct {
+	AgentID     string `json:"agentId"`
+	Description string `json:"description"`
+	TranscriptSummary
+}
+
 // Project represents a Claude Code project directory containing sessions.
 type Project struct {
+	Profile      string // profile of the data directory it is in; empty without profiles
 	DirName      string // encoded directory name (e.g. "-Users-barryhall-Dev-code")
 	Path         string // original project path (e.g. "/Users/barryhall/Dev/code")
 	SessionCount int
+	TotalSize    int64  // combined size of the project's session and subagent .jsonl files in bytes
 	LastModified string // most recent session modified date
 	LastPrompt   string // summary or first prompt from the most recent session
 }
+
+// Matches reports whether the project path contains the given filter term,
+// ignoring case. Words of the form @name require the project to be in that
+// profile.
+func (p Project) Matches(term string) bool {
+	term = strings.ToLower(term)
+	if strings.Contains(term, "@") {
+		var words []string
+		for _, w := range strings.Fields(term) {
+			if name, ok := strings.CutPrefix(w, "@"); ok && name != "" {
+				if !inProfile(p.Profile, name) {
+					return false
+				}
+				continue
+			}
+			words = append(words, w)
+		}
+		term = strings.Join(words, " ")
+	}
+	return strings.Contains(strings.ToLower(p.Path), term)
+}
+
+// inProfile reports whether profile is name, ignoring case, or one of the
+// profiles a glob registered as name matched.
+func inProfile(profile, name string) bool {
+	profile = strings.ToLower(profile)
+	return profile == name || strings.HasPrefix(profile, name+":")
+}
@@ -0,0 +1,115 @@
+package timeline
+
+import (
+	"strings"
+	"time"
+)
+
+// Grid lays out daily message counts as a GitHub-style calendar: one column
+// per week, Monday in the top row.
+type Grid struct {
+	Start  time.Time // Monday of the first week
+	Weeks  int
+	Counts [][7]int // [week][weekday], Monday = 0
+	Max    int      // highest count in the grid
+}
+
+// NewGrid builds a grid of the given number of weeks ending with the week
+// that contains end.
+func NewGrid(days []Day, end time.Time, weeks int) Grid {
+	if weeks < 1 {
+		weeks = 1
+	}
+	end = midnight(end)
+	start := end.AddDate(0, 0, -weekday(end)-7*(weeks-1))
+	g := Grid{Start: start, Weeks: weeks, Counts: make([][7]int, weeks)}
+	for _, d := range days {
+		w, wd, ok := g.Cell(d.Date)
+		if !ok {
+			continue
+		}
+		g.Counts[w][wd] += d.Messages
+		if g.Counts[w][wd] > g.Max {
+			g.Max = g.Counts[w][wd]
+		}
+	}
+	return g
+}
+
+// Cell returns the week and weekday of date in the grid, and whether it
+// falls inside it.
+func (g Grid) Cell(date time.Time) (week, wd int, ok bool) {
+	date = midnight(date.In(g.Start.Location()))
+	days := int(date.Sub(g.Start).Hours()+12) / 24 // +12 absorbs DST shifts
+	if date.Before(g.Start) || days >= g.Weeks*7 {
+		return 0, 0, false
+	}
+	return days / 7, days % 7, true
+}
+
+// Date returns the date of a cell.
+func (g Grid) Date(week, wd int) time.Time {
+	return g.Start.AddDate(0, 0, week*7+wd)
+}
+
+// Level maps a count to a shade from 0 (no activity) to 4 (the busiest
+// days), in quarters of the grid's maximum.
+func (g Grid) Level(count int) int {
+	if count <= 0 || g.Max == 0 {
+		return 0
+	}
+	level := (4*count + g.Max - 1) / g.Max // rounded up, so any activity shows
+	if level > 4 {
+		level = 4
+	}
+	return level
+}
+
+// shades are the characters used by String for levels 0 to 4.
+var shades = []string{"·", "░", "▒", "▓", "█"}
+
+// String renders the grid as text, with weekday labels and cells after end
+// left blank.
+func (g Grid) String() string {
+	today := midnight(time.Now())
+	labels := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	var b strings.Builder
+	b.WriteString("    " + g.MonthLabels(1) + "\n")
+	for wd := 0; wd < 7; wd++ {
+		b.WriteString(labels[wd] + strings.Repeat(" ", 4-len(labels[wd])))
+		for w := 0; w < g.Weeks; w++ {
+			if g.Date(w, wd).After(today) {
+				b.WriteString(" ")
+				continue
+			}
+			b.WriteString(shades[g.Level(g.Counts[w][wd])])
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// MonthLabels returns a line with the short month name above the first week
+// of each month, for cells of the given width.
+func (g Grid) MonthLabels(cellWidth int) string {
+	line := []rune(strings.Repeat(" ", g.Weeks*cellWidth+3))
+	last := -4
+	for w := 0; w < g.Weeks; w++ {
+		d := g.Date(w, 0)
+		if w > 0 && d.Day() > 7 {
+			continue
+		}
+		col := w * cellWidth
+		if col < last+4 {
+			continue
+		}
+		copy(line[col:], []rune(d.Format("Jan")))
+		last = col
+	}
+	return strings.TrimRight(string(line), " ")
+}
+
+// weekday returns t's day of the week with Monday as 0.
+func weekday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
@@ -0,0 +1,185 @@
+// Package timeline buckets session activity by calendar day. Days come from
+// the timestamps of the messages in each transcript, so a session that ran
+// over several days shows up on each of them.
+package timeline
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+// dateFormat is the layout of Day keys.
+const dateFormat = "2006-01-02"
+
+// Activity is one session's messages on one day.
+type Activity struct {
+	Session  session.Session
+	Messages int       // messages that day, including the session's subagents
+	First    time.Time // first message that day
+	Last     time.Time // last message that day
+}
+
+// Day is the activity on one calendar day in local time.
+type Day struct {
+	Date     time.Time  // local midnight
+	Messages int        // messages across all sessions
+	Sessions []Activity // ordered by first message
+}
+
+// Key returns the day's date as YYYY-MM-DD.
+func (d Day) Key() string {
+	return d.Date.Format(dateFormat)
+}
+
+// Build scans the transcripts of the given sessions and returns the days
+// with at least one message at or after since, oldest first. A zero since
+// includes everything.
+func Build(sessions []session.Session, since time.Time) []Day {
+	days := make(map[string]*Day)
+	for _, s := range sessions {
+		perDay := make(map[string]*Activity)
+		paths := []string{s.FullPath}
+		for _, a := range s.Subagents {
+			paths = append(paths, a.FullPath)
+		}
+		for _, path := range paths {
+			// A file can't hold messages newer than its last write.
+			if info, err := claude.RootOf(path).Stat(path); err != nil || info.ModTime().Before(since) {
+				continue
+			}
+			for _, t := range messageTimes(path) {
+				if t.Before(since) {
+					continue
+				}
+				t = t.Local()
+				key := t.Format(dateFormat)
+				a, ok := perDay[key]
+				if !ok {
+					a = &Activity{Session: s, First: t, Last: t}
+					perDay[key] = a
+				}
+				a.Messages++
+				if t.Before(a.First) {
+					a.First = t
+				}
+				if t.After(a.Last) {
+					a.Last = t
+				}
+			}
+		}
+		for key, a := range perDay {
+			d, ok := days[key]
+			if !ok {
+				d = &Day{Date: midnight(a.First)}
+				days[key] = d
+			}
+			d.Messages += a.Messages
+			d.Sessions = append(d.Sessions, *a)
+		}
+	}
+
+	out := make([]Day, 0, len(days))
+	for _, d := range days {
+		sort.Slice(d.Sessions, func(i, j int) bool {
+			return d.Sessions[i].First.Before(d.Sessions[j].First)
+		})
+		out = append(out, *d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out
+}
+
+// Filter returns the days restricted to sessions for which keep returns
+// true. Days left without sessions are dropped.
+func Filter(days []Day, keep func(session.Session) bool) []Day {
+	var out []Day
+	for _, d := range days {
+		nd := Day{Date: d.Date}
+		for _, a := range d.Sessions {
+			if keep(a.Session) {
+				nd.Sessions = append(nd.Sessions, a)
+				nd.Messages += a.Messages
+			}
+		}
+		if len(nd.Sessions) > 0 {
+			out = append(out, nd)
+		}
+	}
+	return out
+}
+
+// messageTimes returns the timestamps of the user and assistant messages in
+// a transcript.
+func messageTimes(path string) []time.Time {
+	f, err := claude.RootOf(path).Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var times []time.Time
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		// Cheap check before decoding, as in session.scanSession.
+		if !bytes.Contains(line, []byte(`"timestamp"`)) {
+			continue
+		}
+		var entry struct {
+			Type      string `json:"type"`
+			Timestamp string `json:"timestamp"`
+		}
+		if json.Unmarshal(line, &entry) != nil || (entry.Type != "user" && entry.Type != "assistant") {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339Nano, entry.Timestamp); err == nil {
+			times = append(times, t)
+		}
+	}
+	return times
+}
+
+// midnight returns the start of t's day in t's location.
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// ParseSince parses a --since value relative to now. It accepts a number of
+// days or weeks ("7d", "2w"), which count whole calendar days including
+// today, a Go duration ("36h"), "today", "yesterday" or a date
+// (YYYY-MM-DD).
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	today := midnight(now)
+	switch s {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if t, err := time.ParseInLocation(dateFormat, s, now.Location()); err == nil {
+		return t, nil
+	}
+	if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+		count, err := strconv.Atoi(s[:n-1])
+		if err == nil && count > 0 {
+			if s[n-1] == 'w' {
+				count *= 7
+			}
+			return today.AddDate(0, 0, 1-count), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (use e.g. 7d, 2w, 36h, yesterday or 2006-01-02)", s)
+}
@@ -0,0 +1,126 @@
+package timeline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/session"
+)
+
+func TestParseSince(t *testing.T) {
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, zone)
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, zone) }
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "today", want: day(3, 10)},
+		{in: "yesterday", want: day(3, 9)},
+		{in: "1d", want: day(3, 10)},
+		{in: "7d", want: day(3, 4)},
+		{in: "1w", want: day(3, 4)},
+		{in: "2w", want: day(2, 25)},
+		{in: "36h", want: now.Add(-36 * time.Hour)},
+		{in: "90m", want: now.Add(-90 * time.Minute)},
+		{in: "2026-01-05", want: day(1, 5)},
+		{in: "0d", wantErr: true},
+		{in: "-3d", wantErr: true},
+		{in: "d", wantErr: true},
+		{in: "-1h", wantErr: true},
+		{in: "2026-13-01", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "last week", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.in, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseSince(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	claude.Use(claude.New(dir))
+	t.Cleanup(func() { claude.UseAll(nil) })
+
+	at := func(d, h, m int) time.Time { return time.Date(2026, 3, d, h, m, 0, 0, time.Local) }
+	line := func(typ string, t time.Time) string {
+		return fmt.Sprintf(`{"type":%q,"timestamp":%q}`, typ, t.UTC().Format(time.RFC3339Nano))
+	}
+	write := func(name string, lines ...string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// a runs past midnight into the next day, where its subagent also
+	// works; b starts on that day before a resumes.
+	a := session.Session{
+		SessionID: "a",
+		FullPath:  write("a.jsonl", line("user", at(20, 23, 0)), line("assistant", at(21, 1, 0)), line("user", at(21, 2, 0))),
+		Subagents: []session.Subagent{{FullPath: write("agent.jsonl", line("assistant", at(21, 3, 0)))}},
+	}
+	b := session.Session{
+		SessionID: "b",
+		FullPath:  write("b.jsonl", line("summary", at(21, 0, 10)), `{"type":"user"}`, line("user", at(21, 0, 30))),
+	}
+
+	tests := []struct {
+		name  string
+		since time.Time
+		want  []string // "date total: session messages first-last, ..."
+	}{
+		{
+			name: "everything",
+			want: []string{
+				"2026-03-20 1: a 1 23:00-23:00",
+				"2026-03-21 4: b 1 00:30-00:30, a 3 01:00-03:00",
+			},
+		},
+		{
+			name:  "since the second day",
+			since: at(21, 0, 0),
+			want:  []string{"2026-03-21 4: b 1 00:30-00:30, a 3 01:00-03:00"},
+		},
+		{
+			name:  "since part way through it",
+			since: at(21, 1, 30),
+			want:  []string{"2026-03-21 2: a 2 02:00-03:00"},
+		},
+		{
+			name:  "nothing since",
+			since: time.Now().Add(time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range Build([]session.Session{a, b}, tt.since) {
+				var acts []string
+				for _, act := range d.Sessions {
+					acts = append(acts, fmt.Sprintf("%s %d %s-%s", act.Session.SessionID, act.Messages, act.First.Format("15:04"), act.Last.Format("15:04")))
+				}
+				got = append(got, fmt.Sprintf("%s %d: %s", d.Key(), d.Messages, strings.Join(acts, ", ")))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
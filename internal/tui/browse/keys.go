@@ -18,6 +18,18 @@ type keyMap struct {
 	SelAll   key.Binding
 	DeselAll key.Binding
 	Delete   key.Binding
+	Sort     key.Binding
+	Reverse  key.Binding
+	Branch   key.Binding
+	Group    key.Binding
+	Repos    key.Binding
+	Tag      key.Binding
+	Untag    key.Binding
+	Pin      key.Binding
+	Note     key.Binding
+	Follow   key.Binding
+	Title    key.Binding
+	Edit     key.Binding
 	Yes      key.Binding
 	No       key.Binding
 }
@@ -84,6 +96,54 @@ func newKeyMap() keyMap {
 			key.WithKeys("d"),
 			key.WithHelp("d", "delete selected"),
 		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "cycle sort"),
+		),
+		Reverse: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "reverse sort"),
+		),
+		Branch: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "filter by branch"),
+		),
+		Group: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "group by branch"),
+		),
+		Repos: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "group by repository"),
+		),
+		Tag: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "add tags"),
+		),
+		Untag: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "remove tags"),
+		),
+		Pin: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "toggle pin"),
+		),
+		Note: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "edit note"),
+		),
+		Follow: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "follow transcript"),
+		),
+		Title: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "auto-title"),
+		),
+		Edit: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "edit"),
+		),
 		Yes: key.NewBinding(
 			key.WithKeys("y"),
 			key.WithHelp("y", "yes"),
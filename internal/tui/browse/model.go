@@ -11,6 +11,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 
+	"github.com/baz-sh/clsm/internal/claude"
 	"github.com/baz-sh/clsm/internal/session"
 	"github.com/baz-sh/clsm/internal/tui/theme"
 )
@@ -36,6 +37,10 @@ const (
 	phaseSearching
 	phaseSessions
 	phaseRename
+	phaseTitling
+	phaseTitlePreview
+	phaseBranchPicker
+	phaseTagInput
 	phaseConfirmDelete
 	phaseDeleting
 	phaseDeleteResults
@@ -43,11 +48,75 @@ const (
 	phasePrunePreview
 	phasePruning
 	phasePruneResults
+	phaseFollow
 )
 
-// projectItem wraps a Project for display.
+// projectItem wraps a Project for display. When projects are grouped by
+// repository, project is the merged repository and repo lists its
+// worktrees and clones.
 type projectItem struct {
 	project session.Project
+	repo    session.Repository
+}
+
+// merged reports whether the item stands for more than one project.
+func (p projectItem) merged() bool {
+	return len(p.repo.Projects) > 1
+}
+
+// branchKind identifies what a branch filter matches.
+type branchKind int
+
+const (
+	branchAll   branchKind = iota // no branch filter
+	branchGone                    // sessions whose branch no longer exists
+	branchNamed                   // sessions on one branch
+)
+
+// branchChoice is one entry in the branch picker, and the active branch
+// filter. The zero value matches every session.
+type branchChoice struct {
+	kind  branchKind
+	name  string
+	count int
+	gone  bool
+}
+
+// matches reports whether a session passes the branch filter.
+func (c branchChoice) matches(s session.Session) bool {
+	switch c.kind {
+	case branchGone:
+		return s.BranchGone
+	case branchNamed:
+		return s.GitBranch == c.name
+	}
+	return true
+}
+
+func (c branchChoice) label() string {
+	switch c.kind {
+	case branchGone:
+		return "branches that no longer exist"
+	case branchNamed:
+		return branchName(c.name)
+	}
+	return "all branches"
+}
+
+// branchName returns the display name for a session's branch.
+func branchName(b string) string {
+	if b == "" {
+		return "(no branch)"
+	}
+	return b
+}
+
+// titleRow is a derived title awaiting review before it is applied.
+type titleRow struct {
+	idx    int // index into sessions
+	title  string
+	source string // see session.TitleFrom*, "template" or "edited"
+	skip   bool
 }
 
 // sessionItem wraps a Session for display.
@@ -70,23 +139,56 @@ type Model struct {
 	resultCh     <-chan projectsResultMsg
 	filter       textinput.Model
 	filtering    bool
+	order        SortState
 
 	// Projects
 	projects      []projectItem
 	filteredProjs []int // indices into projects
 	projCursor    int
+	byRepo        bool              // merge worktrees and clones of a repository
+	rawProjects   []session.Project // the projects as loaded, before merging
 
 	// Sessions (shared across project/all/search sources)
 	selectedProject session.Project
-	sessionSource   string // "project", "all", "search"
+	selectedRepo    session.Repository // worktrees of a merged repository, if one was opened
+	sessionSource   string             // "project", "all", "search"
 	sessions        []sessionItem
 	filteredSess    []int // indices into sessions
 	sessCursor      int
 	selected        map[int]bool // multi-select: keys are indices into sessions
 
+	// Branches
+	groupByBranch bool
+	branchFilter  branchChoice
+	branchChoices []branchChoice
+	branchCursor  int
+
+	// Subagents
+	expanded string // key of the session whose subagents are listed
+
+	// Live updates
+	watchPrints map[string]uint64 // by Model.watched key: fingerprint of the files the list was loaded from
+
+	// Follow
+	followSession session.Session
+	tailer        *session.Tailer
+	followEntries []session.Entry
+	followScroll  int // lines scrolled up from the bottom; 0 follows new output
+
+	// Tags
+	tagInput    textinput.Model
+	tagRemoving bool
+	tagTargets  []int // indices into sessions
+
 	// Rename
 	renameInput textinput.Model
-	renameIdx   int // index into sessions being renamed
+	renameIdx   int  // index into sessions being renamed
+	templating  bool // renameInput holds a title template for the selection
+
+	// Auto-title
+	titleRows    []titleRow
+	titleCursor  int
+	titleEditing bool // renameInput is editing the row under the cursor
 
 	// Search
 	searchInput      textinput.Model
@@ -98,10 +200,14 @@ type Model struct {
 	allSessResultCh <-chan allSessionsResultMsg
 
 	// Delete
+	deleteTargets []session.Session
+	deleteKept    []session.Session // tagged or pinned, skipped by bulk delete
+	deleteAlso    map[string]string // session key -> artifacts deleted with it
 	deleteResults []session.DeleteResult
 
 	// Prune
-	pruneSessions []session.Session
+	pruneSessions  []session.Session
+	pruneProtected int // empty sessions kept because they are tagged or pinned
 
 	status     string
 	BackToHome bool
@@ -109,6 +215,21 @@ type Model struct {
 	height     int
 }
 
+// SortState is the sort order of the project and session lists. It is kept
+// across navigation within a run and can be carried between runs.
+type SortState struct {
+	Sessions        session.SortKey
+	SessionsReverse bool
+	Projects        session.ProjectSortKey
+	ProjectsReverse bool
+}
+
+// DefaultSort is the initial sort order: most recently modified first.
+var DefaultSort = SortState{
+	Sessions: session.SortModified,
+	Projects: session.ProjectSortRecent,
+}
+
 // New creates a new browse Model with the given start mode.
 func New(mode StartMode) Model {
 	sp := spinner.New()
@@ -124,6 +245,11 @@ func New(mode StartMode) Model {
 	ri.CharLimit = 256
 	ri.SetWidth(50)
 
+	ti := textinput.New()
+	ti.Placeholder = "tags, e.g. incident keep"
+	ti.CharLimit = 256
+	ti.SetWidth(50)
+
 	si := textinput.New()
 	si.Placeholder = "Enter search term..."
 	si.CharLimit = 256
@@ -156,15 +282,18 @@ func New(mode StartMode) Model {
 		progress:    prog,
 		filter:      fi,
 		renameInput: ri,
+		tagInput:    ti,
 		searchInput: si,
 		selected:    make(map[int]bool),
+		watchPrints: make(map[string]uint64),
+		order:       DefaultSort,
 		width:       80,
 		height:      24,
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	bgCmd := tea.RequestBackgroundColor
+	bgCmd := tea.Batch(tea.RequestBackgroundColor, m.watchCmd())
 	switch m.startMode {
 	case ModeProjects:
 		return tea.Batch(bgCmd, func() tea.Msg { return startLoadMsg{} })
@@ -195,8 +324,16 @@ func (m Model) View() tea.View {
 		content = m.viewLoading("Searching...")
 	case phaseSessions:
 		content = m.viewSessions()
+	case phaseBranchPicker:
+		content = m.viewBranchPicker()
+	case phaseTagInput:
+		content = m.viewTagInput()
 	case phaseRename:
 		content = m.viewRename()
+	case phaseTitling:
+		content = fmt.Sprintf("%s Deriving titles...\n", m.spinner.View())
+	case phaseTitlePreview:
+		content = m.viewTitlePreview()
 	case phaseConfirmDelete:
 		content = m.viewConfirmDelete()
 	case phaseDeleting:
@@ -211,6 +348,8 @@ func (m Model) View() tea.View {
 		content = fmt.Sprintf("%s Pruning sessions...\n", m.spinner.View())
 	case phasePruneResults:
 		content = m.viewPruneResults()
+	case phaseFollow:
+		content = m.viewFollow()
 	}
 	v := tea.NewView(content)
 	v.AltScreen = true
@@ -253,13 +392,22 @@ func (m Model) projPageSize() int {
 }
 
 // sessPageSize returns the number of session items that fit on screen.
-// Each session takes up to 3 lines (title + date + optional prompt).
+// Each session takes up to 3 lines (title + date + optional prompt), plus
+// one for a branch header when grouped by branch. The lines of an expanded
+// subagent list come out of the page.
 func (m Model) sessPageSize() int {
-	overhead := 5
+	overhead := 5 + m.subagentLines()
 	if m.filtering {
 		overhead += 2
 	}
-	ps := (m.height - overhead) / 3
+	perItem := 3
+	if m.groupByBranch {
+		perItem++
+	}
+	if len(m.selectedRepo.Projects) > 0 {
+		perItem++ // worktree headers
+	}
+	ps := (m.height - overhead) / perItem
 	if ps < 1 {
 		ps = 1
 	}
@@ -272,6 +420,10 @@ func (m Model) sessPageSize() int {
 func (m Model) viewProjects() string {
 	var b strings.Builder
 	b.WriteString(m.theme.Title.Render("clsm — Browse Projects"))
+	if m.byRepo {
+		b.WriteString("  ")
+		b.WriteString(m.theme.Breadcrumb.Render("by repository"))
+	}
 	b.WriteString("\n\n")
 
 	if m.filtering {
@@ -290,9 +442,14 @@ func (m Model) viewProjects() string {
 		end = len(items)
 	}
 
+	showProfile := len(claude.Roots()) > 1
 	for vi := start; vi < end; vi++ {
-		p := m.projects[items[vi]].project
+		item := m.projects[items[vi]]
+		p := item.project
 		path := shortenPath(p.Path)
+		if item.merged() {
+			path = item.repo.Name
+		}
 
 		prefix := "  "
 		style := lipgloss.NewStyle()
@@ -302,11 +459,26 @@ func (m Model) viewProjects() string {
 		}
 
 		count := m.theme.Count.Render(fmt.Sprintf("[%d]", p.SessionCount))
-		b.WriteString(fmt.Sprintf("%s%s %s\n", prefix, style.Render(path), count))
+		line := fmt.Sprintf("%s%s %s", prefix, style.Render(path), count)
+		if item.merged() {
+			line += " " + m.theme.Breadcrumb.Render(fmt.Sprintf("%d worktrees", len(item.repo.Projects)))
+		} else if showProfile {
+			line += " " + m.theme.Breadcrumb.Render("@"+p.Profile)
+		}
+		b.WriteString(line + "\n")
 
 		mod := formatTime(p.LastModified)
 		detail := "last modified: " + mod
-		if p.LastPrompt != "" {
+		if p.TotalSize > 0 {
+			detail += " • " + formatSize(p.TotalSize)
+		}
+		if item.merged() {
+			paths := make([]string, len(item.repo.Projects))
+			for i, wt := range item.repo.Projects {
+				paths[i] = shortenPath(wt.Path)
+			}
+			detail += " • " + truncate(strings.Join(paths, ", "), m.width-len(detail)-6)
+		} else if p.LastPrompt != "" {
 			detail += " • " + truncate(p.LastPrompt, m.width-len(mod)-22)
 		}
 		b.WriteString(fmt.Sprintf("    %s\n", m.theme.Dim.Render(detail)))
@@ -326,14 +498,66 @@ func (m Model) viewProjects() string {
 	if totalPages < 1 {
 		totalPages = 1
 	}
-	b.WriteString(fmt.Sprintf(" %d projects • Page %d/%d", len(items), page+1, totalPages))
+	b.WriteString(fmt.Sprintf(" %d projects • Page %d/%d • %s", len(items), page+1, totalPages,
+		sortLabel(string(m.order.Projects), m.order.ProjectsReverse)))
 	b.WriteString("\n")
 	if m.filtering {
 		b.WriteString(m.theme.Help.Render("enter: apply filter • esc: clear filter"))
 	} else {
-		b.WriteString(m.theme.Help.Render("j/k: navigate • enter/l: open • /: filter • q/esc: back"))
+		b.WriteString(m.theme.Help.Render("j/k: navigate • enter/l: open • /: filter • s/S: sort/reverse • R: group by repository • q/esc: back"))
+	}
+
+	return b.String()
+}
+
+// maxSubagentRows is the number of subagents listed under an expanded
+// session before the rest are summarised.
+const maxSubagentRows = 8
+
+// expandedSession returns the session whose subagents are listed, if it is
+// in the current view.
+func (m Model) expandedSession() (session.Session, bool) {
+	if m.expanded == "" {
+		return session.Session{}, false
+	}
+	for _, idx := range m.filteredSess {
+		if s := m.sessions[idx].session; s.Key() == m.expanded {
+			return s, true
+		}
+	}
+	return session.Session{}, false
+}
+
+// subagentLines returns the number of lines the expanded subagent list
+// takes.
+func (m Model) subagentLines() int {
+	s, ok := m.expandedSession()
+	if !ok {
+		return 0
 	}
+	n := len(s.Subagents)
+	if n > maxSubagentRows {
+		n = maxSubagentRows + 1
+	}
+	return n
+}
 
+// viewSubagents renders the child list of an expanded session.
+func (m Model) viewSubagents(s session.Session) string {
+	var b strings.Builder
+	for i, a := range s.Subagents {
+		if i == maxSubagentRows {
+			more := fmt.Sprintf("        … %d more (clsm show %s)", len(s.Subagents)-i, s.SessionID[:min(8, len(s.SessionID))])
+			b.WriteString(m.theme.Dim.Render(more) + "\n")
+			break
+		}
+		desc := firstLine(a.Description)
+		if desc == "" {
+			desc = a.AgentID
+		}
+		info := m.theme.Count.Render(fmt.Sprintf("[%d msgs]", a.MsgCount)) + " " + m.theme.Dim.Render(formatSize(a.Size))
+		b.WriteString("      ↳ " + truncate(desc, m.width-30) + " " + info + "\n")
+	}
 	return b.String()
 }
 
@@ -345,7 +569,11 @@ func (m Model) viewSessions() string {
 	case "project":
 		b.WriteString(m.theme.Title.Render("clsm — Sessions"))
 		b.WriteString("  ")
-		b.WriteString(m.theme.Breadcrumb.Render(shortenPath(m.selectedProject.Path)))
+		if n := len(m.selectedRepo.Projects); n > 0 {
+			b.WriteString(m.theme.Breadcrumb.Render(fmt.Sprintf("%s (%d worktrees)", m.selectedRepo.Name, n)))
+		} else {
+			b.WriteString(m.theme.Breadcrumb.Render(shortenPath(m.selectedProject.Path)))
+		}
 	case "all":
 		b.WriteString(m.theme.Title.Render("clsm — All Sessions"))
 	case "search":
@@ -367,6 +595,7 @@ func (m Model) viewSessions() string {
 	items := m.filteredSess
 	cursor := m.sessCursor
 	showProject := m.sessionSource == "all" || m.sessionSource == "search"
+	showProfile := len(claude.Roots()) > 1
 
 	ps := m.sessPageSize()
 	page := cursor / ps
@@ -381,6 +610,24 @@ func (m Model) viewSessions() string {
 		s := m.sessions[sessIdx].session
 		title := displayTitle(s)
 
+		// Worktree header when a repository is open.
+		newWorktree := len(m.selectedRepo.Projects) > 0 &&
+			(vi == start || m.sessions[items[vi-1]].session.Project != s.Project)
+		if newWorktree {
+			b.WriteString(m.theme.Bold.Render("▌ " + shortenPath(s.ProjectPath)))
+			b.WriteString("\n")
+		}
+
+		// Branch header at the start of each group and each page.
+		if m.groupByBranch && (vi == start || newWorktree || m.sessions[items[vi-1]].session.GitBranch != s.GitBranch) {
+			header := "── " + branchName(s.GitBranch)
+			if s.BranchGone {
+				header += " " + m.theme.Error.Render("(gone)")
+			}
+			b.WriteString(m.theme.Breadcrumb.Render(header))
+			b.WriteString("\n")
+		}
+
 		// Checkbox.
 		check := m.theme.Uncheck.String()
 		if m.selected[sessIdx] {
@@ -397,8 +644,21 @@ func (m Model) viewSessions() string {
 			style = m.theme.Selected
 		}
 
+		if s.Pinned {
+			title = "★ " + title
+		}
 		msgs := m.theme.Count.Render(fmt.Sprintf("[%d msgs]", s.MsgCount))
-		b.WriteString(fmt.Sprintf("%s%s %s %s\n", prefix, check, style.Render(title), msgs))
+		line := fmt.Sprintf("%s%s %s %s", prefix, check, style.Render(title), msgs)
+		if showProfile {
+			line += " " + m.theme.Breadcrumb.Render("@"+s.Profile)
+		}
+		if s.Live != "" {
+			line += " " + m.theme.Error.Render("● live")
+		}
+		if len(s.Tags) > 0 {
+			line += " " + m.theme.Breadcrumb.Render("#"+strings.Join(s.Tags, " #"))
+		}
+		b.WriteString(line + "\n")
 
 		// Detail line.
 		mod := formatTime(s.Modified)
@@ -408,16 +668,34 @@ func (m Model) viewSessions() string {
 		} else {
 			detail = mod
 		}
-		if s.GitBranch != "" {
+		if s.GitBranch != "" && !m.groupByBranch {
 			detail += " • " + s.GitBranch
 		}
-		b.WriteString(fmt.Sprintf("      %s\n", m.theme.Dim.Render(detail)))
+		if s.Size > 0 {
+			detail += " • " + formatSize(s.Size)
+		}
+		if n := len(s.Subagents); n == 1 {
+			detail += " • 1 subagent"
+		} else if n > 1 {
+			detail += fmt.Sprintf(" • %d subagents", n)
+		}
+		detail = m.theme.Dim.Render(detail)
+		if s.BranchGone && !m.groupByBranch {
+			detail += " " + m.theme.Error.Render("(branch gone)")
+		}
+		b.WriteString(fmt.Sprintf("      %s\n", detail))
 
-		// Optional prompt line.
-		prompt := truncate(firstLine(s.FirstPrompt), m.width-8)
-		if prompt != "" {
+		// Optional note or prompt line.
+		if s.Note != "" {
+			note := truncate("✎ "+firstLine(s.Note), m.width-8)
+			b.WriteString(fmt.Sprintf("      %s\n", m.theme.Breadcrumb.Render(note)))
+		} else if prompt := truncate(firstLine(s.FirstPrompt), m.width-8); prompt != "" {
 			b.WriteString(fmt.Sprintf("      %s\n", m.theme.Dim.Render(prompt)))
 		}
+
+		if s.Key() == m.expanded {
+			b.WriteString(m.viewSubagents(s))
+		}
 	}
 
 	if len(items) == 0 {
@@ -427,29 +705,158 @@ func (m Model) viewSessions() string {
 
 	// Footer.
 	b.WriteString("\n")
+	if m.status != "" {
+		b.WriteString(m.theme.Dim.Render(m.status))
+		b.WriteString("\n")
+	}
 	selectedCount := m.countSelected()
 	totalPages := (len(items) + ps - 1) / ps
 	if totalPages < 1 {
 		totalPages = 1
 	}
+	sortInfo := sortLabel(string(m.order.Sessions), m.order.SessionsReverse)
+	if m.groupByBranch {
+		sortInfo = "grouped by branch • " + sortInfo
+	}
+	if m.branchFilter.kind != branchAll {
+		sortInfo = m.branchFilter.label() + " • " + sortInfo
+	}
 	if selectedCount > 0 {
-		b.WriteString(fmt.Sprintf(" %d sessions • %d selected • Page %d/%d", len(items), selectedCount, page+1, totalPages))
+		b.WriteString(fmt.Sprintf(" %d sessions • %d selected • Page %d/%d • %s", len(items), selectedCount, page+1, totalPages, sortInfo))
 	} else {
-		b.WriteString(fmt.Sprintf(" %d sessions • Page %d/%d", len(items), page+1, totalPages))
+		b.WriteString(fmt.Sprintf(" %d sessions • Page %d/%d • %s", len(items), page+1, totalPages, sortInfo))
 	}
 	b.WriteString("\n")
 
 	if m.filtering {
 		b.WriteString(m.theme.Help.Render("enter: apply filter • esc: clear filter"))
 	} else if selectedCount > 0 {
-		b.WriteString(m.theme.Help.Render("j/k: navigate • space: select • a/A: all/none • d: delete • /: filter • q/esc: back"))
+		b.WriteString(m.theme.Help.Render("j/k: navigate • space: select • a/A: all/none • d: delete • r: rename • ctrl+r: auto-title • t/T/p: tag/untag/pin • /: filter • q/esc: back"))
 	} else {
-		b.WriteString(m.theme.Help.Render("j/k: navigate • space: select • r: rename • /: filter • q/esc: back"))
+		b.WriteString(m.theme.Help.Render("j/k: navigate • space: select • enter: subagents • f: follow • r: rename • ctrl+r: auto-title • t/T/p: tag/untag/pin • N: note • /: filter • s/S: sort • b/B: branch/group • q/esc: back"))
 	}
 
 	return b.String()
 }
 
+const (
+	// followInterval is how often the followed transcript is polled.
+	followInterval = 500 * time.Millisecond
+	// followBacklog is the number of earlier entries shown when following
+	// starts.
+	followBacklog = 200
+)
+
+// followPageSize returns the number of transcript lines that fit on screen.
+func (m Model) followPageSize() int {
+	return max(1, m.height-6)
+}
+
+// renderEntries formats transcript entries as screen lines.
+func (m Model) renderEntries(entries []session.Entry) []string {
+	width := max(20, m.width-4)
+	var lines []string
+	for _, e := range entries {
+		ts := "     "
+		if !e.Time.IsZero() {
+			ts = e.Time.Local().Format("15:04")
+		}
+		label, style := e.Type, m.theme.Dim
+		switch e.Type {
+		case "user":
+			label, style = "you", m.theme.Cursor
+		case "assistant":
+			label, style = "claude", m.theme.Selected
+		}
+		head := m.theme.Dim.Render(ts) + " " + style.Render(fmt.Sprintf("%-7s", label))
+		indent := strings.Repeat(" ", 14)
+		first := true
+		emit := func(text string) {
+			if first {
+				lines = append(lines, head+" "+text)
+				first = false
+			} else {
+				lines = append(lines, indent+text)
+			}
+		}
+		if e.Results > 0 && e.Text == "" {
+			emit(m.theme.Dim.Render(fmt.Sprintf("← %d tool result(s)", e.Results)))
+		}
+		for _, line := range strings.Split(e.Text, "\n") {
+			if line = strings.TrimRight(line, " "); line == "" {
+				continue
+			}
+			emit(truncate(line, width-14))
+		}
+		for _, t := range e.Tools {
+			emit(m.theme.Breadcrumb.Render("→ " + truncate(t, width-16)))
+		}
+	}
+	return lines
+}
+
+func (m Model) viewFollow() string {
+	var b strings.Builder
+	b.WriteString(m.theme.Title.Render("clsm — Follow"))
+	b.WriteString("  ")
+	b.WriteString(m.theme.Breadcrumb.Render(truncate(displayTitle(m.followSession), m.width-20)))
+	b.WriteString("\n\n")
+
+	lines := m.renderEntries(m.followEntries)
+	ps := m.followPageSize()
+	scroll := min(m.followScroll, max(0, len(lines)-ps))
+	end := len(lines) - scroll
+	start := max(0, end-ps)
+	for _, line := range lines[start:end] {
+		b.WriteString(line + "\n")
+	}
+	if len(lines) == 0 {
+		b.WriteString(m.theme.Dim.Render("  Waiting for messages...") + "\n")
+	}
+
+	b.WriteString("\n")
+	if m.status != "" {
+		b.WriteString(m.theme.Error.Render(m.status) + "\n")
+	}
+	state := "following"
+	if scroll > 0 {
+		state = fmt.Sprintf("paused, %d lines below", scroll)
+	}
+	b.WriteString(m.theme.Help.Render(state + " • j/k: scroll • G: follow • g: top • esc: back"))
+	return b.String()
+}
+
+func (m Model) viewBranchPicker() string {
+	var b strings.Builder
+	b.WriteString(m.theme.Title.Render("clsm — Filter by Branch"))
+	b.WriteString("\n\n")
+
+	for i, c := range m.branchChoices {
+		prefix := "  "
+		style := lipgloss.NewStyle()
+		if i == m.branchCursor {
+			prefix = m.theme.Cursor.Render("> ")
+			style = m.theme.Cursor
+		}
+		line := prefix + style.Render(c.label())
+		if c.kind != branchAll {
+			line += " " + m.theme.Count.Render(fmt.Sprintf("[%d]", c.count))
+		}
+		if c.kind == branchNamed && c.gone {
+			line += " " + m.theme.Error.Render("(gone)")
+		}
+		if c.kind == m.branchFilter.kind && c.name == m.branchFilter.name {
+			line += " " + m.theme.Dim.Render("(current)")
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.theme.Help.Render("j/k: navigate • enter: apply • esc: cancel"))
+	return b.String()
+}
+
 func (m Model) viewSearchInput() string {
 	var b strings.Builder
 	b.WriteString(m.theme.Title.Render("clsm — Search Sessions"))
@@ -466,6 +873,9 @@ func (m Model) viewSearchInput() string {
 }
 
 func (m Model) viewRename() string {
+	if m.templating {
+		return m.viewRenameTemplate()
+	}
 	var b strings.Builder
 	b.WriteString(m.theme.Title.Render("clsm — Rename Session"))
 	b.WriteString("\n\n")
@@ -484,20 +894,168 @@ func (m Model) viewRename() string {
 	return b.String()
 }
 
+// titlePageSize returns the number of title rows that fit on screen.
+func (m Model) titlePageSize() int {
+	return max(1, (m.height-8)/2)
+}
+
+func (m Model) viewTitlePreview() string {
+	var b strings.Builder
+	if m.templating {
+		b.WriteString(m.theme.Title.Render("clsm — Rename Sessions"))
+	} else {
+		b.WriteString(m.theme.Title.Render("clsm — Auto-title"))
+	}
+	b.WriteString("\n\n")
+
+	apply := 0
+	for _, r := range m.titleRows {
+		if !r.skip {
+			apply++
+		}
+	}
+	if len(m.titleRows) == 0 {
+		b.WriteString(m.theme.Dim.Render("  No titles could be derived."))
+		b.WriteString("\n")
+	}
+
+	ps := m.titlePageSize()
+	start := m.titleCursor / ps * ps
+	end := min(start+ps, len(m.titleRows))
+	for i := start; i < end; i++ {
+		r := m.titleRows[i]
+		s := m.sessions[r.idx].session
+
+		check := m.theme.Check.String()
+		style := lipgloss.NewStyle()
+		if r.skip {
+			check = m.theme.Uncheck.String()
+			style = m.theme.Dim
+		}
+		prefix := "  "
+		if i == m.titleCursor {
+			prefix = m.theme.Cursor.Render("> ")
+			if !r.skip {
+				style = m.theme.Cursor
+			}
+		}
+		title := style.Render(r.title)
+		if i == m.titleCursor && m.titleEditing {
+			title = m.renameInput.View()
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s %s\n", prefix, check, title, m.theme.Count.Render("["+r.source+"]")))
+		b.WriteString(fmt.Sprintf("      %s\n", m.theme.Dim.Render("was: "+truncate(displayTitle(s), m.width-14))))
+	}
+
+	b.WriteString("\n")
+	if m.status != "" {
+		b.WriteString(m.theme.Dim.Render(m.status))
+		b.WriteString("\n")
+	}
+	b.WriteString(fmt.Sprintf(" %d titles • %d to apply\n", len(m.titleRows), apply))
+	if m.titleEditing {
+		b.WriteString(m.theme.Help.Render("enter: save • esc: cancel"))
+	} else {
+		b.WriteString(m.theme.Help.Render("j/k: navigate • space: include/skip • e: edit • y: apply • n/esc: cancel"))
+	}
+	return b.String()
+}
+
+func (m Model) viewRenameTemplate() string {
+	var b strings.Builder
+	b.WriteString(m.theme.Title.Render("clsm — Rename Sessions"))
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("%d selected sessions\n\n", len(m.selected)))
+	b.WriteString("Title template:\n\n")
+	b.WriteString(m.renameInput.View())
+	b.WriteString("\n\n")
+	b.WriteString(m.theme.Dim.Render("Placeholders: " + session.TemplateFields))
+	b.WriteString("\n")
+
+	// Show the template applied to the first selected session.
+	idx := m.targetIndices()
+	example, err := session.ExpandTitle(m.renameInput.Value(), m.sessions[idx[0]].session)
+	if err != nil {
+		b.WriteString(m.theme.Error.Render(err.Error()))
+	} else {
+		b.WriteString(m.theme.Dim.Render("Example: ") + example)
+	}
+	b.WriteString("\n\n")
+	if m.status != "" {
+		b.WriteString(m.theme.Dim.Render(m.status))
+		b.WriteString("\n\n")
+	}
+	b.WriteString(m.theme.Help.Render("enter: preview • esc: cancel"))
+	return b.String()
+}
+
 func (m Model) viewConfirmDelete() string {
-	selected := m.selectedSessions()
 	var b strings.Builder
 	b.WriteString(m.theme.Title.Render("Confirm Deletion"))
 	b.WriteString("\n\n")
-	b.WriteString(fmt.Sprintf("Delete %d session(s)?\n\n", len(selected)))
+	if len(m.deleteTargets) > 0 {
+		b.WriteString(fmt.Sprintf("Delete %d session(s)?\n\n", len(m.deleteTargets)))
+	} else {
+		b.WriteString("Nothing to delete.\n\n")
+	}
 
-	for _, s := range selected {
+	for _, s := range m.deleteTargets {
 		title := displayTitle(s)
 		b.WriteString(fmt.Sprintf("  • %s\n", title))
+		if also := m.deleteAlso[s.Key()]; also != "" {
+			b.WriteString(m.theme.Dim.Render("    also "+also) + "\n")
+		}
+	}
+
+	if len(m.deleteKept) > 0 {
+		b.WriteString("\n")
+		b.WriteString(m.theme.Dim.Render(fmt.Sprintf("Keeping %d tagged or pinned session(s):", len(m.deleteKept))))
+		b.WriteString("\n")
+		for _, s := range m.deleteKept {
+			b.WriteString(m.theme.Dim.Render(fmt.Sprintf("  • %s", displayTitle(s))))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if len(m.deleteTargets) > 0 {
+		b.WriteString(m.theme.Help.Render("y: confirm • n/esc: cancel"))
+	} else {
+		b.WriteString(m.theme.Help.Render("n/esc: back"))
 	}
+	return b.String()
+}
+
+func (m Model) viewTagInput() string {
+	var b strings.Builder
+	if m.tagRemoving {
+		b.WriteString(m.theme.Title.Render("clsm — Remove Tags"))
+	} else {
+		b.WriteString(m.theme.Title.Render("clsm — Add Tags"))
+	}
+	b.WriteString("\n\n")
 
+	if len(m.tagTargets) == 1 {
+		s := m.sessions[m.tagTargets[0]].session
+		b.WriteString(fmt.Sprintf("Session: %s\n", displayTitle(s)))
+		if len(s.Tags) > 0 {
+			b.WriteString(fmt.Sprintf("Tags:    %s\n", m.theme.Breadcrumb.Render("#"+strings.Join(s.Tags, " #"))))
+		}
+	} else {
+		b.WriteString(fmt.Sprintf("%d selected sessions\n", len(m.tagTargets)))
+	}
 	b.WriteString("\n")
-	b.WriteString(m.theme.Help.Render("y: confirm • n/esc: cancel"))
+	b.WriteString(m.tagInput.View())
+	b.WriteString("\n\n")
+	if m.status != "" {
+		b.WriteString(m.theme.Dim.Render(m.status))
+		b.WriteString("\n\n")
+	}
+	if m.tagRemoving {
+		b.WriteString(m.theme.Help.Render("enter: remove (empty removes all) • esc: cancel"))
+	} else {
+		b.WriteString(m.theme.Help.Render("enter: add • esc: cancel"))
+	}
 	return b.String()
 }
 
@@ -537,16 +1095,29 @@ func (m Model) viewPrunePreview() string {
 	b.WriteString("\n\n")
 
 	if len(m.pruneSessions) == 0 {
-		b.WriteString(m.theme.Dim.Render("No empty sessions found."))
+		if m.status != "" {
+			b.WriteString(m.theme.Error.Render(m.status))
+		} else if m.pruneProtected > 0 {
+			b.WriteString(m.theme.Dim.Render(fmt.Sprintf("Only tagged or pinned empty sessions found (%d); nothing to prune.", m.pruneProtected)))
+		} else {
+			b.WriteString(m.theme.Dim.Render("No empty sessions found."))
+		}
 		b.WriteString("\n\n")
 		b.WriteString(m.theme.Help.Render("enter/esc: back to menu"))
 		return b.String()
 	}
 
 	b.WriteString(fmt.Sprintf("Prune %d session(s) with 0 messages?\n\n", len(m.pruneSessions)))
+	if m.pruneProtected > 0 {
+		b.WriteString(m.theme.Dim.Render(fmt.Sprintf("Keeping %d tagged or pinned empty session(s).", m.pruneProtected)))
+		b.WriteString("\n\n")
+	}
 
 	// title(1) + blank(1) + header(1) + blank(1) + items... + blank(1) + help(1) = 6 overhead
 	maxVisible := m.height - 6
+	if m.pruneProtected > 0 {
+		maxVisible -= 2
+	}
 	if maxVisible < 1 {
 		maxVisible = 1
 	}
@@ -635,11 +1206,38 @@ func (m Model) selectedSessions() []session.Session {
 	return sessions
 }
 
+// WithSort returns a copy of the model using the given sort order.
+func (m Model) WithSort(order SortState) Model {
+	if order.Sessions == "" {
+		order.Sessions = DefaultSort.Sessions
+	}
+	if order.Projects == "" {
+		order.Projects = DefaultSort.Projects
+	}
+	m.order = order
+	return m
+}
+
+// Sort returns the model's current sort order.
+func (m Model) Sort() SortState {
+	return m.order
+}
+
 // WantsBackToHome returns true if the user quit to return to the home menu.
 func (m Model) WantsBackToHome() bool {
 	return m.BackToHome
 }
 
+// sortLabel describes a sort key for the list footer. The arrow shows
+// whether the key's natural order is reversed.
+func sortLabel(key string, reverse bool) string {
+	arrow := "↓"
+	if reverse {
+		arrow = "↑"
+	}
+	return "sorted by " + key + " " + arrow
+}
+
 func firstLine(s string) string {
 	for _, line := range strings.Split(s, "\n") {
 		line = strings.TrimSpace(line)
@@ -686,6 +1284,17 @@ func formatTime(ts string) string {
 	return t.Local().Format("2006-01-02 15:04")
 }
 
+func formatSize(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%dB", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.0fKB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1024*1024))
+	}
+}
+
 func displayTitle(s session.Session) string {
 	if s.CustomTitle != "" {
 		return s.CustomTitle
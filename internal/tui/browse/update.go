@@ -2,15 +2,22 @@ package browse
 
 import (
 	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"charm.land/bubbles/v2/key"
 	"charm.land/bubbles/v2/progress"
 	"charm.land/bubbles/v2/spinner"
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/meta"
 	"github.com/baz-sh/clsm/internal/session"
 	"github.com/baz-sh/clsm/internal/tui/theme"
+	"github.com/baz-sh/clsm/internal/watch"
 )
 
 // Messages for async operations.
@@ -24,9 +31,21 @@ type projectsResultMsg struct {
 
 type loadProgressMsg session.LoadProgress
 type sessionsLoadedMsg []session.Session
+
+// branchStatusMsg holds the keys of sessions whose branch no longer exists.
+type branchStatusMsg map[string]bool
 type loadErrorMsg struct{ err error }
 type renameResultMsg struct{ err error }
 
+// titlesSuggestedMsg carries derived titles for review; titlesAppliedMsg
+// reports which of them were written.
+type titlesSuggestedMsg []titleRow
+
+type titlesAppliedMsg struct {
+	titles map[string]string // session key -> title written
+	errs   []string
+}
+
 type allSessionsResultMsg struct {
 	sessions []session.Session
 	err      error
@@ -40,8 +59,132 @@ type searchResultMsg struct {
 type searchProgressMsg session.SearchProgress
 type deleteResultMsg []session.DeleteResult
 
+// noteEditedMsg reports that the editor for a session note has exited.
+type noteEditedMsg struct {
+	sessionID string
+	path      string
+	err       error
+}
+
+// metaUpdatedMsg carries the tags and pins of sessions after a change.
+type metaUpdatedMsg struct {
+	meta map[string]meta.SessionMeta
+	err  error
+}
+
+// watchMsg carries the fingerprint of the files a list shows; see
+// watchCmd.
+type watchMsg struct {
+	scope string // see Model.watched
+	print uint64
+}
+
+// projectsReloadedMsg and sessionsReloadedMsg carry lists reloaded after a
+// change on disk. They are applied in place.
+type projectsReloadedMsg struct {
+	projects []session.Project
+	err      error
+}
+
+type sessionsReloadedMsg struct {
+	source     string // sessionSource the reload was for
+	profile    string // profile of the project of a "project" reload
+	projectDir string // project of a "project" reload
+	sessions   []session.Session
+	err        error
+}
+
+// followTickMsg asks for the entries written to the followed transcript
+// since the last poll.
+type followTickMsg struct{}
+
+type followEntriesMsg struct {
+	entries []session.Entry
+	err     error
+}
+
 // --- Async command launchers ---
 
+// watched returns the projects whose files the current list shows, and a
+// key naming them. It returns "" and nil when the list covers every
+// project.
+func (m Model) watched() (string, []session.Project) {
+	if m.sessionSource != "project" || m.phase == phaseProjects || m.phase == phaseLoadingProjects {
+		return "", nil
+	}
+	if len(m.selectedRepo.Projects) > 0 {
+		return "repo\x00" + m.selectedRepo.Key, m.selectedRepo.Projects
+	}
+	p := m.selectedProject
+	return p.Profile + "\x00" + p.DirName, []session.Project{p}
+}
+
+// fingerprint hashes the session files of projects, or of every project
+// if there are none, and clsm's metadata.
+func fingerprint(projects []session.Project) uint64 {
+	var fp watch.Fingerprint
+	add := func(r *claude.Root, dir string) {
+		fp.Add(r,
+			filepath.Join(dir, "*.jsonl"),
+			filepath.Join(dir, session.IndexFileName),
+			filepath.Join(dir, "*", "subagents", "*.jsonl"))
+	}
+	if len(projects) == 0 {
+		for _, r := range claude.Roots() {
+			add(r, filepath.Join(r.ProjectsDir(), "*"))
+		}
+	}
+	for _, p := range projects {
+		r := claude.For(p.Profile)
+		add(r, filepath.Join(r.ProjectsDir(), p.DirName))
+	}
+	if dir, err := meta.Dir(); err == nil {
+		fp.AddFile(filepath.Join(dir, "meta.json"))
+	}
+	return fp.Sum()
+}
+
+// watchCmd fingerprints the files the current list shows after
+// watch.Interval.
+func (m Model) watchCmd() tea.Cmd {
+	scope, projects := m.watched()
+	return tea.Tick(watch.Interval, func(time.Time) tea.Msg {
+		return watchMsg{scope, fingerprint(projects)}
+	})
+}
+
+// reloadCmd reloads the list shown in the current phase, or returns nil if
+// it can't be refreshed in place.
+func (m Model) reloadCmd() tea.Cmd {
+	switch {
+	case m.phase == phaseProjects:
+		return func() tea.Msg {
+			projects, err := session.ListProjects()
+			return projectsReloadedMsg{projects, err}
+		}
+	case m.phase == phaseSessions && m.sessionSource == "project":
+		proj, repo := m.selectedProject, m.selectedRepo
+		return func() tea.Msg {
+			sessions, err := listProject(proj, repo)
+			return sessionsReloadedMsg{source: "project", profile: proj.Profile, projectDir: proj.DirName, sessions: sessions, err: err}
+		}
+	case m.phase == phaseSessions && m.sessionSource == "all":
+		return func() tea.Msg {
+			sessions, err := session.ListAllSessions()
+			return sessionsReloadedMsg{source: "all", sessions: sessions, err: err}
+		}
+	}
+	return nil
+}
+
+// followCmd polls the followed transcript after a short delay.
+func followCmd(t *session.Tailer) tea.Cmd {
+	return tea.Tick(followInterval, func(time.Time) tea.Msg {
+		entries, err := t.Poll()
+		return followEntriesMsg{entries, err}
+	})
+}
+
 func startLoadWithProgress(m *Model) tea.Cmd {
 	progressCh := make(chan session.LoadProgress, 10)
 	resultCh := make(chan projectsResultMsg, 1)
@@ -129,9 +272,12 @@ func listenForSearchUpdates(progressCh <-chan session.SearchProgress, resultCh <
 	}
 }
 
-func loadSessionsCmd(projectDir string) tea.Cmd {
+// loadProjectCmd loads the sessions of the selected project, or of every
+// worktree of the selected repository.
+func (m Model) loadProjectCmd() tea.Cmd {
+	proj, repo := m.selectedProject, m.selectedRepo
 	return func() tea.Msg {
-		sessions, err := session.ListSessions(projectDir)
+		sessions, err := listProject(proj, repo)
 		if err != nil {
 			return loadErrorMsg{err}
 		}
@@ -139,8 +285,55 @@ func loadSessionsCmd(projectDir string) tea.Cmd {
 	}
 }
 
+func listProject(proj session.Project, repo session.Repository) ([]session.Session, error) {
+	if len(repo.Projects) > 0 {
+		return repo.ListSessions()
+	}
+	return session.ListSessions(proj.Profile, proj.DirName)
+}
+
+// checkBranchesCmd asks git which session branches still exist. It runs
+// after a list is shown so a slow repository never delays the list.
+func checkBranchesCmd(sessions []session.Session) tea.Cmd {
+	return func() tea.Msg {
+		return branchStatusMsg(session.GoneBranchKeys(sessions))
+	}
+}
+
+// updateMetaCmd applies change to each session's metadata in clsm's
+// sidecar store and reports the result.
+func updateMetaCmd(ids []string, change func(st *meta.Store, id string)) tea.Cmd {
+	return func() tea.Msg {
+		updated := make(map[string]meta.SessionMeta, len(ids))
+		err := meta.Update(func(st *meta.Store) error {
+			for _, id := range ids {
+				change(st, id)
+				updated[id] = st.Get(id)
+			}
+			return nil
+		})
+		return metaUpdatedMsg{meta: updated, err: err}
+	}
+}
+
+// editNoteCmd opens the note for a session in $EDITOR.
+func editNoteCmd(sessionID string) tea.Cmd {
+	path, err := meta.PrepareNote(meta.NoteSession, sessionID)
+	if err != nil {
+		return func() tea.Msg { return noteEditedMsg{sessionID: sessionID, err: err} }
+	}
+	editor := claude.Editor()
+	c := exec.Command(editor, path)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return noteEditedMsg{sessionID: sessionID, path: path, err: err}
+	})
+}
+
 func renameCmd(s session.Session, newTitle string) tea.Cmd {
 	return func() tea.Msg {
+		if err := session.CheckLive(s); err != nil {
+			return renameResultMsg{err: err}
+		}
 		err := session.Rename(s, newTitle)
 		return renameResultMsg{err: err}
 	}
@@ -148,11 +341,52 @@ func renameCmd(s session.Session, newTitle string) tea.Cmd {
 
 func deleteSessCmd(sessions []session.Session) tea.Cmd {
 	return func() tea.Msg {
-		results := session.Delete(sessions)
+		results := session.DeleteIdle(sessions, session.Delete)
+		return deleteResultMsg(results)
+	}
+}
+
+func pruneSessCmd(sessions []session.Session) tea.Cmd {
+	return func() tea.Msg {
+		results := session.DeleteIdle(sessions, session.Prune)
 		return deleteResultMsg(results)
 	}
 }
 
+// suggestTitlesCmd derives a title for each session in rows, dropping the
+// ones nothing could be derived for.
+func suggestTitlesCmd(rows []titleRow, sessions []session.Session) tea.Cmd {
+	return func() tea.Msg {
+		var out []titleRow
+		for i, r := range rows {
+			r.title, r.source = session.SuggestTitle(sessions[i])
+			if r.title != "" && r.title != sessions[i].CustomTitle {
+				out = append(out, r)
+			}
+		}
+		return titlesSuggestedMsg(out)
+	}
+}
+
+// applyTitlesCmd writes the titles through session.Rename.
+func applyTitlesCmd(sessions []session.Session, titles []string) tea.Cmd {
+	return func() tea.Msg {
+		msg := titlesAppliedMsg{titles: make(map[string]string)}
+		for i, s := range sessions {
+			err := session.CheckLive(s)
+			if err == nil {
+				err = session.Rename(s, titles[i])
+			}
+			if err != nil {
+				msg.errs = append(msg.errs, s.SessionID[:min(8, len(s.SessionID))]+": "+err.Error())
+				continue
+			}
+			msg.titles[s.Key()] = titles[i]
+		}
+		return msg
+	}
+}
+
 // --- Main Update ---
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -181,6 +415,86 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.progress, cmd = m.progress.Update(msg)
 		return m, cmd
+	case noteEditedMsg:
+		if msg.err != nil {
+			m.status = "Note: " + msg.err.Error()
+			return m, nil
+		}
+		if err := meta.TidyNote(msg.path); err != nil {
+			m.status = "Note: " + err.Error()
+			return m, nil
+		}
+		note, err := meta.ReadNote(meta.NoteSession, msg.sessionID)
+		if err != nil {
+			m.status = "Note: " + err.Error()
+			return m, nil
+		}
+		m.status = ""
+		for i := range m.sessions {
+			if m.sessions[i].session.SessionID == msg.sessionID {
+				m.sessions[i].session.Note = note
+			}
+		}
+		return m, nil
+	case metaUpdatedMsg:
+		if m.phase == phaseTagInput {
+			m.phase = phaseSessions
+		}
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.status = ""
+		for i := range m.sessions {
+			if sm, ok := msg.meta[m.sessions[i].session.SessionID]; ok {
+				m.sessions[i].session.Tags = sm.Tags
+				m.sessions[i].session.Pinned = sm.Pinned
+			}
+		}
+		m.applyFilter(false)
+		return m, nil
+	case watchMsg:
+		next := m.watchCmd()
+		if scope, _ := m.watched(); scope != msg.scope {
+			return m, next // the list changed since the files were read
+		}
+		base, ok := m.watchPrints[msg.scope]
+		if !ok {
+			m.watchPrints[msg.scope] = msg.print
+			return m, next
+		}
+		if msg.print == base {
+			return m, next
+		}
+		if reload := m.reloadCmd(); reload != nil {
+			m.watchPrints[msg.scope] = msg.print
+			return m, tea.Batch(next, reload)
+		}
+		return m, next
+	case projectsReloadedMsg:
+		if m.phase != phaseProjects || msg.err != nil {
+			m.watchPrints[""] = 0 // try again on the next tick
+			return m, nil
+		}
+		m.replaceProjects(msg.projects)
+		return m, nil
+	case sessionsReloadedMsg:
+		if m.phase != phaseSessions || m.sessionSource != msg.source || msg.err != nil ||
+			(msg.source == "project" && (m.selectedProject.Profile != msg.profile || m.selectedProject.DirName != msg.projectDir)) {
+			scope, _ := m.watched()
+			m.watchPrints[scope] = 0
+			return m, nil
+		}
+		m.replaceSessions(msg.sessions)
+		return m, checkBranchesCmd(msg.sessions)
+	case branchStatusMsg:
+		for i := range m.sessions {
+			m.sessions[i].session.BranchGone = msg[m.sessions[i].session.Key()]
+		}
+		if m.branchFilter.kind == branchGone {
+			m.applyFilter(false)
+		}
+		return m, nil
 	}
 
 	switch m.phase {
@@ -198,8 +512,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateSearching(msg)
 	case phaseSessions:
 		return m.updateSessions(msg)
+	case phaseBranchPicker:
+		return m.updateBranchPicker(msg)
+	case phaseTagInput:
+		return m.updateTagInput(msg)
 	case phaseRename:
 		return m.updateRename(msg)
+	case phaseTitling:
+		return m.updateTitling(msg)
+	case phaseTitlePreview:
+		return m.updateTitlePreview(msg)
 	case phaseConfirmDelete:
 		return m.updateConfirmDelete(msg)
 	case phaseDeleting:
@@ -214,6 +536,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updatePruning(msg)
 	case phasePruneResults:
 		return m.updatePruneResults(msg)
+	case phaseFollow:
+		return m.updateFollow(msg)
 	}
 
 	return m, nil
@@ -240,11 +564,9 @@ func (m Model) updateLoadingProjects(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.phase = phaseProjects
 			return m, nil
 		}
-		m.projects = make([]projectItem, len(msg.projects))
-		for i, p := range msg.projects {
-			m.projects[i] = projectItem{project: p}
-		}
+		m.setProjects(msg.projects)
 		m.filteredProjs = allIndices(len(m.projects))
+		m.sortProjects()
 		m.projCursor = 0
 		m.phase = phaseProjects
 		return m, nil
@@ -301,16 +623,33 @@ func (m Model) updateProjects(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(m.filteredProjs) == 0 {
 				return m, nil
 			}
-			idx := m.filteredProjs[m.projCursor]
-			m.selectedProject = m.projects[idx].project
+			item := m.projects[m.filteredProjs[m.projCursor]]
+			m.selectedProject = item.project
+			m.selectedRepo = session.Repository{}
+			if item.merged() {
+				m.selectedRepo = item.repo
+			}
 			m.phase = phaseLoadingSessions
 			m.filtering = false
 			m.filter.SetValue("")
-			return m, tea.Batch(m.spinner.Tick, loadSessionsCmd(m.selectedProject.DirName))
+			return m, tea.Batch(m.spinner.Tick, m.loadProjectCmd())
 		case key.Matches(msg, m.keys.Search):
 			m.filtering = true
 			m.filter.SetValue("")
 			return m, m.filter.Focus()
+		case key.Matches(msg, m.keys.Sort):
+			m.order.Projects = m.order.Projects.Next()
+			m.sortProjects()
+			m.projCursor = 0
+		case key.Matches(msg, m.keys.Reverse):
+			m.order.ProjectsReverse = !m.order.ProjectsReverse
+			m.sortProjects()
+			m.projCursor = 0
+		case key.Matches(msg, m.keys.Repos):
+			m.byRepo = !m.byRepo
+			m.setProjects(m.rawProjects)
+			m.projCursor = 0
+			m.applyFilter(true)
 		}
 	}
 
@@ -325,11 +664,13 @@ func (m Model) updateLoadingSessions(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.sessions[i] = sessionItem{session: s}
 		}
 		m.filteredSess = allIndices(len(m.sessions))
+		m.sortSessions()
 		m.sessCursor = 0
 		m.selected = make(map[int]bool)
+		m.branchFilter = branchChoice{}
 		m.sessionSource = "project"
 		m.phase = phaseSessions
-		return m, nil
+		return m, checkBranchesCmd(msg)
 
 	case loadErrorMsg:
 		m.status = "Error: " + msg.err.Error()
@@ -369,11 +710,13 @@ func (m Model) updateLoadingAllSessions(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.sessions[i] = sessionItem{session: s}
 		}
 		m.filteredSess = allIndices(len(m.sessions))
+		m.sortSessions()
 		m.sessCursor = 0
 		m.selected = make(map[int]bool)
+		m.branchFilter = branchChoice{}
 		m.sessionSource = "all"
 		m.phase = phaseSessions
-		return m, nil
+		return m, checkBranchesCmd(msg.sessions)
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -445,11 +788,13 @@ func (m Model) updateSearching(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.sessions[i] = sessionItem{session: s}
 		}
 		m.filteredSess = allIndices(len(m.sessions))
+		m.sortSessions()
 		m.sessCursor = 0
 		m.selected = make(map[int]bool)
+		m.branchFilter = branchChoice{}
 		m.sessionSource = "search"
 		m.phase = phaseSessions
-		return m, nil
+		return m, checkBranchesCmd(msg.sessions)
 	}
 
 	return m, nil
@@ -542,20 +887,137 @@ func (m Model) updateSessions(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(m.selected) == 0 {
 				return m, nil
 			}
+			// Deleting several sessions at once skips tagged and pinned
+			// ones; a single selected session is deleted as asked.
+			m.deleteTargets = m.selectedSessions()
+			m.deleteKept = nil
+			if len(m.deleteTargets) > 1 {
+				deletable, kept, err := session.SplitProtected(m.deleteTargets)
+				if err != nil {
+					m.status = "Error: " + err.Error()
+					return m, nil
+				}
+				m.deleteTargets, m.deleteKept = deletable, kept
+			}
+			m.deleteAlso = session.DescribeSessionArtifacts(m.deleteTargets, formatSize)
 			m.phase = phaseConfirmDelete
 			return m, nil
+		case key.Matches(msg, m.keys.Tag), key.Matches(msg, m.keys.Untag):
+			m.tagTargets = m.targetIndices()
+			if len(m.tagTargets) == 0 {
+				return m, nil
+			}
+			m.tagRemoving = key.Matches(msg, m.keys.Untag)
+			m.tagInput.SetValue("")
+			m.status = ""
+			m.phase = phaseTagInput
+			return m, m.tagInput.Focus()
+		case key.Matches(msg, m.keys.Open):
+			if len(m.filteredSess) == 0 {
+				return m, nil
+			}
+			// Show or hide the subagents of the session under the cursor.
+			s := m.sessions[m.filteredSess[m.sessCursor]].session
+			if m.expanded == s.Key() || len(s.Subagents) == 0 {
+				m.expanded = ""
+			} else {
+				m.expanded = s.Key()
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Follow):
+			if len(m.filteredSess) == 0 {
+				return m, nil
+			}
+			s := m.sessions[m.filteredSess[m.sessCursor]].session
+			t, entries, err := session.NewTailer(s.FullPath, followBacklog)
+			if err != nil {
+				m.status = "Error: " + err.Error()
+				return m, nil
+			}
+			m.followSession = s
+			m.tailer = t
+			m.followEntries = entries
+			m.followScroll = 0
+			m.status = ""
+			m.phase = phaseFollow
+			return m, followCmd(t)
+		case key.Matches(msg, m.keys.Note):
+			if len(m.filteredSess) == 0 {
+				return m, nil
+			}
+			return m, editNoteCmd(m.sessions[m.filteredSess[m.sessCursor]].session.SessionID)
+		case key.Matches(msg, m.keys.Pin):
+			targets := m.targetIndices()
+			if len(targets) == 0 {
+				return m, nil
+			}
+			// Pin all targets unless the one under the cursor is pinned.
+			pin := !m.sessions[m.filteredSess[m.sessCursor]].session.Pinned
+			return m, updateMetaCmd(m.sessionIDs(targets), func(st *meta.Store, id string) {
+				st.SetPinned(id, pin)
+			})
 		case key.Matches(msg, m.keys.Search):
 			m.filtering = true
 			m.filter.SetValue("")
 			return m, m.filter.Focus()
+		case key.Matches(msg, m.keys.Sort):
+			m.order.Sessions = m.order.Sessions.Next()
+			m.sortSessions()
+			m.sessCursor = 0
+		case key.Matches(msg, m.keys.Reverse):
+			m.order.SessionsReverse = !m.order.SessionsReverse
+			m.sortSessions()
+			m.sessCursor = 0
+		case key.Matches(msg, m.keys.Branch):
+			m.branchChoices = m.buildBranchChoices()
+			m.branchCursor = 0
+			for i, c := range m.branchChoices {
+				if c.kind == m.branchFilter.kind && c.name == m.branchFilter.name {
+					m.branchCursor = i
+				}
+			}
+			m.phase = phaseBranchPicker
+			return m, nil
+		case key.Matches(msg, m.keys.Group):
+			m.groupByBranch = !m.groupByBranch
+			m.sortSessions()
+			m.sessCursor = 0
+		case key.Matches(msg, m.keys.Title):
+			// A selection only gets titles for its untitled sessions; the
+			// session under the cursor is titled even if it has one.
+			targets := m.targetIndices()
+			var rows []titleRow
+			var sessions []session.Session
+			for _, idx := range targets {
+				s := m.sessions[idx].session
+				if len(m.selected) > 0 && s.CustomTitle != "" {
+					continue
+				}
+				rows = append(rows, titleRow{idx: idx})
+				sessions = append(sessions, s)
+			}
+			if len(rows) == 0 {
+				m.status = "The selected sessions already have titles."
+				return m, nil
+			}
+			m.status = ""
+			m.templating = false
+			m.phase = phaseTitling
+			return m, tea.Batch(m.spinner.Tick, suggestTitlesCmd(rows, sessions))
 		case key.Matches(msg, m.keys.Rename):
-			// Rename only works when nothing is selected.
-			if len(m.filteredSess) == 0 || len(m.selected) > 0 {
+			// With a selection, rename takes a title template for all of
+			// the selected sessions.
+			if len(m.filteredSess) == 0 {
 				return m, nil
 			}
-			idx := m.filteredSess[m.sessCursor]
-			m.renameIdx = idx
+			m.templating = len(m.selected) > 0
 			m.renameInput.SetValue("")
+			if m.templating {
+				m.renameInput.SetValue("{title}")
+				m.renameInput.CursorEnd()
+			} else {
+				m.renameIdx = m.filteredSess[m.sessCursor]
+			}
 			cmd := m.renameInput.Focus()
 			m.status = ""
 			m.phase = phaseRename
@@ -566,11 +1028,240 @@ func (m Model) updateSessions(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) updateFollow(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case followEntriesMsg:
+		if m.tailer == nil {
+			return m, nil // left follow mode while polling
+		}
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+		} else {
+			m.followEntries = append(m.followEntries, msg.entries...)
+			if m.followScroll > 0 {
+				m.followScroll += len(m.renderEntries(msg.entries))
+			}
+		}
+		return m, followCmd(m.tailer)
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Back):
+			m.tailer = nil
+			m.followEntries = nil
+			m.phase = phaseSessions
+		case key.Matches(msg, m.keys.Up):
+			m.followScroll++
+		case key.Matches(msg, m.keys.Down):
+			if m.followScroll > 0 {
+				m.followScroll--
+			}
+		case key.Matches(msg, m.keys.HalfUp):
+			m.followScroll += m.followPageSize() / 2
+		case key.Matches(msg, m.keys.HalfDn):
+			m.followScroll = max(0, m.followScroll-m.followPageSize()/2)
+		case key.Matches(msg, m.keys.Top):
+			m.followScroll = len(m.renderEntries(m.followEntries))
+		case key.Matches(msg, m.keys.Bottom):
+			m.followScroll = 0
+		}
+	}
+	return m, nil
+}
+
+// setProjects fills the project list from loaded projects, merging the
+// worktrees and clones of each repository when grouped by repository.
+func (m *Model) setProjects(projects []session.Project) {
+	m.rawProjects = projects
+	if !m.byRepo {
+		m.projects = make([]projectItem, len(projects))
+		for i, p := range projects {
+			m.projects[i] = projectItem{project: p}
+		}
+		return
+	}
+	repos := session.GroupByRepository(projects)
+	m.projects = make([]projectItem, len(repos))
+	for i, r := range repos {
+		m.projects[i] = projectItem{project: r.Project(), repo: r}
+	}
+}
+
+// replaceProjects swaps in a reloaded project list, keeping the filter and
+// the project under the cursor.
+func (m *Model) replaceProjects(projects []session.Project) {
+	var cursorDir string
+	if len(m.filteredProjs) > 0 {
+		cursorDir = m.projects[m.filteredProjs[m.projCursor]].project.DirName
+	}
+	m.setProjects(projects)
+	m.applyFilter(true)
+	for vi, idx := range m.filteredProjs {
+		if m.projects[idx].project.DirName == cursorDir {
+			m.projCursor = vi
+		}
+	}
+}
+
+// replaceSessions swaps in a reloaded session list, keeping the filter,
+// the selection, the branch status and the session under the cursor.
+func (m *Model) replaceSessions(sessions []session.Session) {
+	var cursorID string
+	if len(m.filteredSess) > 0 {
+		cursorID = m.sessions[m.filteredSess[m.sessCursor]].session.Key()
+	}
+	selected := make(map[string]bool, len(m.selected))
+	gone := make(map[string]bool)
+	for i, item := range m.sessions {
+		if m.selected[i] {
+			selected[item.session.Key()] = true
+		}
+		if item.session.BranchGone {
+			gone[item.session.Key()] = true
+		}
+	}
+
+	m.sessions = make([]sessionItem, len(sessions))
+	m.selected = make(map[int]bool, len(selected))
+	for i, s := range sessions {
+		s.BranchGone = gone[s.Key()]
+		m.sessions[i] = sessionItem{session: s}
+		if selected[s.Key()] {
+			m.selected[i] = true
+		}
+	}
+	m.applyFilter(false)
+	for vi, idx := range m.filteredSess {
+		if m.sessions[idx].session.Key() == cursorID {
+			m.sessCursor = vi
+		}
+	}
+}
+
+func (m Model) updateBranchPicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			if m.branchCursor > 0 {
+				m.branchCursor--
+			}
+		case key.Matches(msg, m.keys.Down):
+			if m.branchCursor < len(m.branchChoices)-1 {
+				m.branchCursor++
+			}
+		case key.Matches(msg, m.keys.Top):
+			m.branchCursor = 0
+		case key.Matches(msg, m.keys.Bottom):
+			m.branchCursor = len(m.branchChoices) - 1
+		case key.Matches(msg, m.keys.Open):
+			m.branchFilter = m.branchChoices[m.branchCursor]
+			m.sessCursor = 0
+			m.applyFilter(false)
+			m.phase = phaseSessions
+		case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Quit):
+			m.phase = phaseSessions
+		}
+	}
+	return m, nil
+}
+
+// buildBranchChoices lists the picker entries for the loaded sessions: all
+// branches, branches that no longer exist (if any), then each branch by
+// session count.
+func (m Model) buildBranchChoices() []branchChoice {
+	counts := make(map[string]int)
+	gone := make(map[string]bool)
+	goneCount := 0
+	for _, item := range m.sessions {
+		s := item.session
+		counts[s.GitBranch]++
+		if s.BranchGone {
+			gone[s.GitBranch] = true
+			goneCount++
+		}
+	}
+
+	choices := []branchChoice{{kind: branchAll, count: len(m.sessions)}}
+	if goneCount > 0 {
+		choices = append(choices, branchChoice{kind: branchGone, count: goneCount})
+	}
+	var named []branchChoice
+	for name, n := range counts {
+		named = append(named, branchChoice{kind: branchNamed, name: name, count: n, gone: gone[name]})
+	}
+	sort.Slice(named, func(i, j int) bool {
+		if named[i].count != named[j].count {
+			return named[i].count > named[j].count
+		}
+		return named[i].name < named[j].name
+	})
+	return append(choices, named...)
+}
+
+func (m Model) updateTagInput(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "enter":
+			tags := meta.ParseTags(m.tagInput.Value())
+			if len(tags) == 0 && !m.tagRemoving {
+				m.status = "Enter at least one tag."
+				return m, nil
+			}
+			m.tagInput.Blur()
+			ids := m.sessionIDs(m.tagTargets)
+			if m.tagRemoving {
+				return m, updateMetaCmd(ids, func(st *meta.Store, id string) { st.Untag(id, tags...) })
+			}
+			return m, updateMetaCmd(ids, func(st *meta.Store, id string) { st.Tag(id, tags...) })
+		case "esc":
+			m.tagInput.Blur()
+			m.status = ""
+			m.phase = phaseSessions
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.tagInput, cmd = m.tagInput.Update(msg)
+	return m, cmd
+}
+
+// targetIndices returns the sessions an action applies to: the selection
+// if there is one, otherwise the session under the cursor.
+func (m Model) targetIndices() []int {
+	if len(m.selected) > 0 {
+		idx := make([]int, 0, len(m.selected))
+		for i := range m.selected {
+			idx = append(idx, i)
+		}
+		sort.Ints(idx)
+		return idx
+	}
+	if len(m.filteredSess) == 0 {
+		return nil
+	}
+	return []int{m.filteredSess[m.sessCursor]}
+}
+
+func (m Model) sessionIDs(indices []int) []string {
+	ids := make([]string, len(indices))
+	for i, idx := range indices {
+		ids[i] = m.sessions[idx].session.SessionID
+	}
+	return ids
+}
+
 func (m Model) updateRename(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyPressMsg:
 		switch msg.String() {
 		case "enter":
+			if m.templating {
+				return m.previewTemplate()
+			}
 			newTitle := strings.TrimSpace(m.renameInput.Value())
 			if newTitle == "" {
 				m.status = "Title cannot be empty."
@@ -604,13 +1295,141 @@ func (m Model) updateRename(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// previewTemplate expands the title template for every selected session
+// and shows the results for review.
+func (m Model) previewTemplate() (tea.Model, tea.Cmd) {
+	template := strings.TrimSpace(m.renameInput.Value())
+	var rows []titleRow
+	for _, idx := range m.targetIndices() {
+		title, err := session.ExpandTitle(template, m.sessions[idx].session)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+		if title == "" {
+			continue
+		}
+		rows = append(rows, titleRow{idx: idx, title: title, source: "template"})
+	}
+	m.renameInput.Blur()
+	m.status = ""
+	m.titleRows = rows
+	m.titleCursor = 0
+	m.titleEditing = false
+	m.phase = phaseTitlePreview
+	return m, nil
+}
+
+func (m Model) updateTitling(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case titlesSuggestedMsg:
+		m.titleRows = []titleRow(msg)
+		m.titleCursor = 0
+		m.titleEditing = false
+		m.phase = phaseTitlePreview
+		return m, nil
+	case titlesAppliedMsg:
+		for i := range m.sessions {
+			if title, ok := msg.titles[m.sessions[i].session.Key()]; ok {
+				m.sessions[i].session.CustomTitle = title
+			}
+		}
+		m.status = fmt.Sprintf("Renamed %d session(s).", len(msg.titles))
+		if len(msg.errs) > 0 {
+			m.status += " Failed: " + strings.Join(msg.errs, "; ")
+		}
+		m.titleRows = nil
+		m.phase = phaseSessions
+		return m, nil
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m Model) updateTitlePreview(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.titleEditing {
+		if msg, ok := msg.(tea.KeyPressMsg); ok {
+			switch msg.String() {
+			case "enter":
+				if title := strings.TrimSpace(m.renameInput.Value()); title != "" {
+					m.titleRows[m.titleCursor].title = title
+					m.titleRows[m.titleCursor].source = "edited"
+					m.titleRows[m.titleCursor].skip = false
+				}
+				m.renameInput.Blur()
+				m.titleEditing = false
+				return m, nil
+			case "esc":
+				m.renameInput.Blur()
+				m.titleEditing = false
+				return m, nil
+			}
+		}
+		var cmd tea.Cmd
+		m.renameInput, cmd = m.renameInput.Update(msg)
+		return m, cmd
+	}
+
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(keyMsg, m.keys.Up):
+		if m.titleCursor > 0 {
+			m.titleCursor--
+		}
+	case key.Matches(keyMsg, m.keys.Down):
+		if m.titleCursor < len(m.titleRows)-1 {
+			m.titleCursor++
+		}
+	case key.Matches(keyMsg, m.keys.Toggle):
+		if len(m.titleRows) > 0 {
+			m.titleRows[m.titleCursor].skip = !m.titleRows[m.titleCursor].skip
+		}
+	case key.Matches(keyMsg, m.keys.Edit):
+		if len(m.titleRows) > 0 {
+			m.renameInput.SetValue(m.titleRows[m.titleCursor].title)
+			m.renameInput.CursorEnd()
+			m.titleEditing = true
+			return m, m.renameInput.Focus()
+		}
+	case key.Matches(keyMsg, m.keys.Yes):
+		var sessions []session.Session
+		var titles []string
+		for _, r := range m.titleRows {
+			if !r.skip {
+				sessions = append(sessions, m.sessions[r.idx].session)
+				titles = append(titles, r.title)
+			}
+		}
+		if len(sessions) == 0 {
+			return m, nil
+		}
+		m.phase = phaseTitling
+		return m, tea.Batch(m.spinner.Tick, applyTitlesCmd(sessions, titles))
+	case key.Matches(keyMsg, m.keys.No), key.Matches(keyMsg, m.keys.Back):
+		m.titleRows = nil
+		m.phase = phaseSessions
+	case key.Matches(keyMsg, m.keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
 func (m Model) updateConfirmDelete(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyPressMsg:
 		switch {
 		case key.Matches(msg, m.keys.Yes):
+			if len(m.deleteTargets) == 0 {
+				return m, nil
+			}
 			m.phase = phaseDeleting
-			return m, tea.Batch(m.spinner.Tick, deleteSessCmd(m.selectedSessions()))
+			return m, tea.Batch(m.spinner.Tick, deleteSessCmd(m.deleteTargets))
 		case key.Matches(msg, m.keys.No), key.Matches(msg, m.keys.Back):
 			m.phase = phaseSessions
 			return m, nil
@@ -643,17 +1462,17 @@ func (m Model) updateDeleteResults(msg tea.Msg) (tea.Model, tea.Cmd) {
 			deletedIDs := make(map[string]bool)
 			for _, r := range m.deleteResults {
 				if r.Success {
-					deletedIDs[r.SessionID] = true
+					deletedIDs[r.Key()] = true
 				}
 			}
 			var remaining []sessionItem
 			for _, item := range m.sessions {
-				if !deletedIDs[item.session.SessionID] {
+				if !deletedIDs[item.session.Key()] {
 					remaining = append(remaining, item)
 				}
 			}
 			m.sessions = remaining
-			m.filteredSess = allIndices(len(m.sessions))
+			m.applyFilter(false)
 			m.selected = make(map[int]bool)
 			if m.sessCursor >= len(m.filteredSess) {
 				m.sessCursor = len(m.filteredSess) - 1
@@ -713,14 +1532,19 @@ func (m Model) updatePruneLoading(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.BackToHome = true
 			return m, tea.Quit
 		}
-		// Filter to 0-message sessions.
+		// Filter to 0-message sessions, keeping tagged and pinned ones.
 		var empty []session.Session
 		for _, s := range msg.sessions {
 			if s.MsgCount == 0 {
 				empty = append(empty, s)
 			}
 		}
-		m.pruneSessions = empty
+		deletable, protected, err := session.SplitProtected(empty)
+		if err != nil {
+			m.status = "Error: " + err.Error()
+		}
+		m.pruneSessions = deletable
+		m.pruneProtected = len(protected)
 		m.phase = phasePrunePreview
 		return m, nil
 
@@ -744,7 +1568,7 @@ func (m Model) updatePrunePreview(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch {
 		case key.Matches(msg, m.keys.Yes):
 			m.phase = phasePruning
-			return m, tea.Batch(m.spinner.Tick, deleteSessCmd(m.pruneSessions))
+			return m, tea.Batch(m.spinner.Tick, pruneSessCmd(m.pruneSessions))
 		case key.Matches(msg, m.keys.No), key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Quit):
 			m.BackToHome = true
 			return m, tea.Quit
@@ -792,9 +1616,10 @@ func (m Model) updateFilterInput(msg tea.Msg, isProjects bool) (tea.Model, tea.C
 			// Reset filter — show all items.
 			if isProjects {
 				m.filteredProjs = allIndices(len(m.projects))
+				m.sortProjects()
 				m.projCursor = 0
 			} else {
-				m.filteredSess = allIndices(len(m.sessions))
+				m.applyFilter(false)
 				m.sessCursor = 0
 			}
 			return m, nil
@@ -819,11 +1644,12 @@ func (m *Model) applyFilter(isProjects bool) {
 		} else {
 			m.filteredProjs = m.filteredProjs[:0]
 			for i, p := range m.projects {
-				if strings.Contains(strings.ToLower(p.project.Path), term) {
+				if p.project.Matches(term) || (p.merged() && p.repo.Matches(term)) {
 					m.filteredProjs = append(m.filteredProjs, i)
 				}
 			}
 		}
+		m.sortProjects()
 		if m.projCursor >= len(m.filteredProjs) {
 			m.projCursor = len(m.filteredProjs) - 1
 		}
@@ -831,19 +1657,13 @@ func (m *Model) applyFilter(isProjects bool) {
 			m.projCursor = 0
 		}
 	} else {
-		if term == "" {
-			m.filteredSess = allIndices(len(m.sessions))
-		} else {
-			m.filteredSess = m.filteredSess[:0]
-			for i, s := range m.sessions {
-				searchable := strings.ToLower(
-					s.session.Summary + " " + s.session.CustomTitle + " " + s.session.FirstPrompt,
-				)
-				if strings.Contains(searchable, term) {
-					m.filteredSess = append(m.filteredSess, i)
-				}
+		m.filteredSess = m.filteredSess[:0]
+		for i, s := range m.sessions {
+			if s.session.Matches(term) && m.branchFilter.matches(s.session) {
+				m.filteredSess = append(m.filteredSess, i)
 			}
 		}
+		m.sortSessions()
 		if m.sessCursor >= len(m.filteredSess) {
 			m.sessCursor = len(m.filteredSess) - 1
 		}
@@ -853,6 +1673,57 @@ func (m *Model) applyFilter(isProjects bool) {
 	}
 }
 
+// sortProjects orders the visible project indices by the current sort key.
+// Only the index slice is reordered, so indices into projects stay valid.
+func (m *Model) sortProjects() {
+	sort.SliceStable(m.filteredProjs, func(i, j int) bool {
+		a := m.projects[m.filteredProjs[i]].project
+		b := m.projects[m.filteredProjs[j]].project
+		return session.LessProjects(a, b, m.order.Projects, m.order.ProjectsReverse)
+	})
+}
+
+// sortSessions orders the visible session indices by the current sort key,
+// with pinned sessions first, grouped by worktree when a repository is
+// open. Only the index slice is reordered, so the selection map stays
+// valid.
+func (m *Model) sortSessions() {
+	sort.SliceStable(m.filteredSess, func(i, j int) bool {
+		a := m.sessions[m.filteredSess[i]].session
+		b := m.sessions[m.filteredSess[j]].session
+		if a.Project != b.Project && len(m.selectedRepo.Projects) > 0 {
+			return m.worktreeRank(a.Project) < m.worktreeRank(b.Project)
+		}
+		if m.groupByBranch && a.GitBranch != b.GitBranch {
+			return lessBranch(a.GitBranch, b.GitBranch)
+		}
+		if a.Pinned != b.Pinned {
+			return a.Pinned
+		}
+		return session.LessSessions(a, b, m.order.Sessions, m.order.SessionsReverse)
+	})
+}
+
+// worktreeRank returns the position of a project directory among the
+// worktrees of the opened repository.
+func (m Model) worktreeRank(dirName string) int {
+	for i, p := range m.selectedRepo.Projects {
+		if p.DirName == dirName {
+			return i
+		}
+	}
+	return len(m.selectedRepo.Projects)
+}
+
+// lessBranch orders branch groups alphabetically with sessions that have no
+// branch last.
+func lessBranch(a, b string) bool {
+	if a == "" || b == "" {
+		return b == ""
+	}
+	return a < b
+}
+
 func allIndices(n int) []int {
 	idx := make([]int, n)
 	for i := range idx {
@@ -0,0 +1,61 @@
+package dupes
+
+import "charm.land/bubbles/v2/key"
+
+type keyMap struct {
+	Up        key.Binding
+	Down      key.Binding
+	NextGroup key.Binding
+	PrevGroup key.Binding
+	Keep      key.Binding
+	Resolve   key.Binding
+	Yes       key.Binding
+	No        key.Binding
+	Back      key.Binding
+	Quit      key.Binding
+}
+
+func newKeyMap() keyMap {
+	return keyMap{
+		Up: key.NewBinding(
+			key.WithKeys("k", "up"),
+			key.WithHelp("k/↑", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("j", "down"),
+			key.WithHelp("j/↓", "down"),
+		),
+		NextGroup: key.NewBinding(
+			key.WithKeys("tab", "l", "right"),
+			key.WithHelp("tab", "next group"),
+		),
+		PrevGroup: key.NewBinding(
+			key.WithKeys("shift+tab", "h", "left"),
+			key.WithHelp("shift+tab", "previous group"),
+		),
+		Keep: key.NewBinding(
+			key.WithKeys("space"),
+			key.WithHelp("space", "keep this one"),
+		),
+		Resolve: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "keep ★, delete the rest"),
+		),
+		Yes: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "confirm"),
+		),
+		No: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "cancel"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "backspace"),
+			key.WithHelp("esc", "back"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
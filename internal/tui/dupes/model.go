@@ -0,0 +1,307 @@
+package dupes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/spinner"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/baz-sh/clsm/internal/dupes"
+	"github.com/baz-sh/clsm/internal/session"
+	"github.com/baz-sh/clsm/internal/tui/theme"
+)
+
+type phase int
+
+const (
+	phaseLoading phase = iota
+	phaseGroups
+	phaseConfirm
+	phaseDeleting
+)
+
+// Model is the Bubble Tea model for reviewing duplicate sessions.
+type Model struct {
+	phase   phase
+	keys    keyMap
+	isDark  bool
+	theme   theme.Theme
+	spinner spinner.Model
+
+	project   string
+	threshold float64
+	groups    []dupes.Group
+	keep      []int // per group, index of the session to keep
+	group     int   // group under the cursor
+	row       int   // session under the cursor within the group
+
+	// Confirm
+	deleteTargets []session.Session
+	deleteKept    []session.Session // protected sessions left alone
+	deleteAlso    map[string]string // session key -> artifacts deleted with it
+
+	status     string
+	BackToHome bool
+	width      int
+	height     int
+}
+
+// New creates a duplicates Model. A non-empty project limits it to projects
+// whose path contains the term.
+func New(project string, threshold float64) Model {
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+
+	return Model{
+		phase:     phaseLoading,
+		keys:      newKeyMap(),
+		theme:     theme.New(true),
+		spinner:   sp,
+		project:   project,
+		threshold: threshold,
+		width:     80,
+		height:    24,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(
+		tea.RequestBackgroundColor,
+		func() tea.Msg { return startLoadMsg{} },
+	)
+}
+
+func (m Model) View() tea.View {
+	var content string
+	switch m.phase {
+	case phaseLoading:
+		content = fmt.Sprintf("%s Comparing sessions...\n", m.spinner.View())
+	case phaseGroups:
+		content = m.viewGroups()
+	case phaseConfirm:
+		content = m.viewConfirm()
+	case phaseDeleting:
+		content = fmt.Sprintf("%s Deleting sessions...\n", m.spinner.View())
+	}
+	v := tea.NewView(content)
+	v.AltScreen = true
+	return v
+}
+
+// WantsBackToHome returns true if the user quit to return to the home menu.
+func (m Model) WantsBackToHome() bool {
+	return m.BackToHome
+}
+
+// --- View helpers ---
+
+func (m Model) header(b *strings.Builder) {
+	b.WriteString(m.theme.Title.Render("clsm — Duplicates"))
+	b.WriteString("  ")
+	if m.project == "" {
+		b.WriteString(m.theme.Breadcrumb.Render("all projects"))
+	} else {
+		b.WriteString(m.theme.Breadcrumb.Render(shortenPath(m.project)))
+	}
+	if len(m.groups) > 0 {
+		b.WriteString("  ")
+		b.WriteString(m.theme.Count.Render(fmt.Sprintf("[group %d/%d]", m.group+1, len(m.groups))))
+	}
+	b.WriteString("\n\n")
+}
+
+// groupLines returns the number of lines group g takes on screen.
+func (m Model) groupLines(g dupes.Group) int {
+	return len(g.Sessions) + 2 // header and blank line
+}
+
+// visibleGroups returns the range of groups to draw so that the cursor's
+// group is on screen.
+func (m Model) visibleGroups() (start, end int) {
+	room := m.height - 7
+	start = m.group
+	used := m.groupLines(m.groups[start])
+	// Show earlier groups while they fit, then fill with later ones.
+	for start > 0 && used+m.groupLines(m.groups[start-1]) <= room/2 {
+		start--
+		used += m.groupLines(m.groups[start])
+	}
+	end = m.group + 1
+	for end < len(m.groups) && used+m.groupLines(m.groups[end]) <= room {
+		used += m.groupLines(m.groups[end])
+		end++
+	}
+	return start, end
+}
+
+func (m Model) viewGroups() string {
+	var b strings.Builder
+	m.header(&b)
+
+	if len(m.groups) == 0 {
+		b.WriteString(m.theme.Dim.Render("  No duplicate sessions found.") + "\n\n")
+		if m.status != "" {
+			b.WriteString(m.theme.Dim.Render(m.status) + "\n")
+		}
+		b.WriteString(m.theme.Help.Render("q/esc: back"))
+		return b.String()
+	}
+
+	start, end := m.visibleGroups()
+	for gi := start; gi < end; gi++ {
+		g := m.groups[gi]
+		head := fmt.Sprintf("%d sessions • %s", len(g.Sessions), strings.Join(g.Reasons, " and "))
+		if gi == m.group {
+			b.WriteString(m.theme.Bold.Render(head))
+		} else {
+			b.WriteString(m.theme.Dim.Render(head))
+		}
+		b.WriteString("\n")
+		for i, s := range g.Sessions {
+			b.WriteString(m.sessionLine(g, gi, i, s) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if m.status != "" {
+		b.WriteString(m.theme.Dim.Render(m.status) + "\n")
+	}
+	b.WriteString(m.theme.Help.Render("j/k: navigate • tab: next group • space: keep this one • d: keep ★, delete the rest • q/esc: back"))
+	return b.String()
+}
+
+// sessionLine renders session i of group gi.
+func (m Model) sessionLine(g dupes.Group, gi, i int, s session.Session) string {
+	selected := gi == m.group && i == m.row
+	prefix := "  "
+	if selected {
+		prefix = m.theme.Cursor.Render("> ")
+	}
+	mark := "  "
+	if i == m.keep[gi] {
+		mark = m.theme.Success.Render("★ ")
+	}
+
+	var labels []string
+	if i == g.Longest {
+		labels = append(labels, "longest")
+	}
+	if i == g.Newest {
+		labels = append(labels, "newest")
+	}
+	if s.Protected() {
+		labels = append(labels, "protected")
+	}
+
+	info := fmt.Sprintf("%s  %d msgs  %s", formatTime(s.Modified), s.MsgCount, formatSize(s.Size))
+	label := ""
+	if len(labels) > 0 {
+		label = "[" + strings.Join(labels, ", ") + "]"
+	}
+	title := truncate(firstLine(s.Title()), m.width-len(info)-len(label)-8)
+	if selected {
+		title = m.theme.Cursor.Render(title)
+	}
+	line := fmt.Sprintf("%s%s%s  %s", prefix, mark, m.theme.Dim.Render(info), title)
+	if label != "" {
+		line += " " + m.theme.Count.Render(label)
+	}
+	return line
+}
+
+func (m Model) viewConfirm() string {
+	var b strings.Builder
+	b.WriteString(m.theme.Title.Render("Confirm Deletion"))
+	b.WriteString("\n\n")
+	keeper := m.groups[m.group].Sessions[m.keep[m.group]]
+	b.WriteString(fmt.Sprintf("Keep:  %s\n\n", m.theme.Success.Render(firstLine(keeper.Title()))))
+	if len(m.deleteTargets) > 0 {
+		b.WriteString(fmt.Sprintf("Delete %d session(s)?\n\n", len(m.deleteTargets)))
+	} else {
+		b.WriteString("Nothing to delete.\n\n")
+	}
+	for _, s := range m.deleteTargets {
+		b.WriteString(fmt.Sprintf("  • %s\n", firstLine(s.Title())))
+		if also := m.deleteAlso[s.Key()]; also != "" {
+			b.WriteString(m.theme.Dim.Render("    also "+also) + "\n")
+		}
+	}
+
+	if len(m.deleteKept) > 0 {
+		b.WriteString("\n")
+		b.WriteString(m.theme.Dim.Render(fmt.Sprintf("Keeping %d tagged or pinned session(s):", len(m.deleteKept))))
+		b.WriteString("\n")
+		for _, s := range m.deleteKept {
+			b.WriteString(m.theme.Dim.Render(fmt.Sprintf("  • %s", firstLine(s.Title()))))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if len(m.deleteTargets) > 0 {
+		b.WriteString(m.theme.Help.Render("y: confirm • n/esc: cancel"))
+	} else {
+		b.WriteString(m.theme.Help.Render("n/esc: back"))
+	}
+	return b.String()
+}
+
+// --- Utilities ---
+
+func formatTime(ts string) string {
+	if ts == "" {
+		return ""
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339, ts)
+	}
+	if err != nil {
+		return ts
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func formatSize(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%dB", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.0fKB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1024*1024))
+	}
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+func truncate(s string, max int) string {
+	if max < 4 {
+		max = 4
+	}
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-1] + "…"
+}
+
+func shortenPath(path string) string {
+	home, _ := strings.CutPrefix(path, "/Users/")
+	if home != path {
+		parts := strings.SplitN(home, "/", 2)
+		if len(parts) == 2 {
+			return "~/" + parts[1]
+		}
+		return "~"
+	}
+	return path
+}
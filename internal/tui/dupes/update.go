@@ -0,0 +1,245 @@
+package dupes
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/spinner"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/baz-sh/clsm/internal/dupes"
+	"github.com/baz-sh/clsm/internal/session"
+	"github.com/baz-sh/clsm/internal/tui/theme"
+)
+
+// --- Messages ---
+
+type startLoadMsg struct{}
+
+type groupsResultMsg struct {
+	groups []dupes.Group
+	err    error
+}
+
+type deleteResultMsg []session.DeleteResult
+
+// --- Commands ---
+
+func loadGroupsCmd(project string, threshold float64) tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := session.ListAllSessions()
+		if err != nil {
+			return groupsResultMsg{err: err}
+		}
+		if project != "" {
+			term := strings.ToLower(project)
+			var kept []session.Session
+			for _, s := range sessions {
+				if strings.Contains(strings.ToLower(s.ProjectPath), term) {
+					kept = append(kept, s)
+				}
+			}
+			sessions = kept
+		}
+		return groupsResultMsg{groups: dupes.Find(sessions, threshold)}
+	}
+}
+
+func deleteSessCmd(sessions []session.Session) tea.Cmd {
+	return func() tea.Msg {
+		return deleteResultMsg(session.DeleteIdle(sessions, session.Delete))
+	}
+}
+
+// --- Update ---
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.BackgroundColorMsg:
+		m.isDark = msg.IsDark()
+		m.theme = theme.New(m.isDark)
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case tea.KeyPressMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	}
+
+	switch m.phase {
+	case phaseLoading:
+		return m.updateLoading(msg)
+	case phaseGroups:
+		return m.updateGroups(msg)
+	case phaseConfirm:
+		return m.updateConfirm(msg)
+	case phaseDeleting:
+		return m.updateDeleting(msg)
+	}
+
+	return m, nil
+}
+
+// --- Phase handlers ---
+
+func (m Model) updateLoading(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case startLoadMsg:
+		return m, tea.Batch(m.spinner.Tick, loadGroupsCmd(m.project, m.threshold))
+
+	case groupsResultMsg:
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+		}
+		m.setGroups(msg.groups)
+		m.phase = phaseGroups
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m Model) updateGroups(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	if key.Matches(keyMsg, m.keys.Quit) || key.Matches(keyMsg, m.keys.Back) {
+		m.BackToHome = true
+		return m, tea.Quit
+	}
+	if len(m.groups) == 0 {
+		return m, nil
+	}
+
+	m.status = ""
+	switch {
+	case key.Matches(keyMsg, m.keys.Up):
+		if m.row > 0 {
+			m.row--
+		} else if m.group > 0 {
+			m.group--
+			m.row = len(m.groups[m.group].Sessions) - 1
+		}
+	case key.Matches(keyMsg, m.keys.Down):
+		if m.row < len(m.groups[m.group].Sessions)-1 {
+			m.row++
+		} else if m.group < len(m.groups)-1 {
+			m.group++
+			m.row = 0
+		}
+	case key.Matches(keyMsg, m.keys.NextGroup):
+		if m.group < len(m.groups)-1 {
+			m.group++
+			m.row = 0
+		}
+	case key.Matches(keyMsg, m.keys.PrevGroup):
+		if m.group > 0 {
+			m.group--
+			m.row = 0
+		}
+	case key.Matches(keyMsg, m.keys.Keep):
+		m.keep[m.group] = m.row
+	case key.Matches(keyMsg, m.keys.Resolve):
+		var others []session.Session
+		for i, s := range m.groups[m.group].Sessions {
+			if i != m.keep[m.group] {
+				others = append(others, s)
+			}
+		}
+		// Tags and pins are read afresh, so ones added since the
+		// duplicates were found still protect their sessions.
+		deletable, kept, err := session.SplitProtected(others)
+		if err != nil {
+			m.status = "Error: " + err.Error()
+			return m, nil
+		}
+		m.deleteTargets, m.deleteKept = deletable, kept
+		m.deleteAlso = session.DescribeSessionArtifacts(m.deleteTargets, formatSize)
+		m.phase = phaseConfirm
+	}
+	return m, nil
+}
+
+func (m Model) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(keyMsg, m.keys.Yes):
+		if len(m.deleteTargets) == 0 {
+			return m, nil
+		}
+		m.phase = phaseDeleting
+		return m, tea.Batch(m.spinner.Tick, deleteSessCmd(m.deleteTargets))
+	case key.Matches(keyMsg, m.keys.No), key.Matches(keyMsg, m.keys.Back):
+		m.phase = phaseGroups
+	case key.Matches(keyMsg, m.keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m Model) updateDeleting(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case deleteResultMsg:
+		deleted := make(map[string]bool)
+		var failed []string
+		for _, r := range msg {
+			if r.Success {
+				deleted[r.Key()] = true
+			} else {
+				failed = append(failed, r.SessionID+": "+r.Error)
+			}
+		}
+		// Keep the choices made in the other groups.
+		kept := make(map[string]bool)
+		for i, g := range m.groups {
+			kept[g.Sessions[m.keep[i]].Key()] = true
+		}
+		group := m.group
+		m.setGroups(dupes.Remove(m.groups, deleted))
+		for i, g := range m.groups {
+			for j, s := range g.Sessions {
+				if kept[s.Key()] {
+					m.keep[i] = j
+				}
+			}
+		}
+		m.group = min(group, max(len(m.groups)-1, 0))
+		m.status = fmt.Sprintf("Deleted %d session(s).", len(deleted))
+		if len(failed) > 0 {
+			m.status += " Failed: " + strings.Join(failed, "; ")
+		}
+		m.phase = phaseGroups
+		return m, nil
+	default:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+}
+
+// --- Helpers ---
+
+// setGroups replaces the groups, suggesting each group's default keeper
+// and moving the cursor to the top of its group.
+func (m *Model) setGroups(groups []dupes.Group) {
+	m.groups = groups
+	m.keep = make([]int, len(groups))
+	for i, g := range groups {
+		m.keep[i] = g.Keep()
+	}
+	m.group = 0
+	m.row = 0
+}
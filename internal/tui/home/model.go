@@ -18,6 +18,9 @@ const (
 	ChoiceSearch   Choice = "search"
 	ChoiceMemories Choice = "memories"
 	ChoicePlans    Choice = "plans"
+	ChoiceTimeline Choice = "timeline"
+	ChoiceDupes    Choice = "dupes"
+	ChoiceSecrets  Choice = "secrets"
 	ChoicePrune    Choice = "prune"
 	ChoiceNone     Choice = ""
 )
@@ -34,6 +37,9 @@ var options = []option{
 	{ChoiceSearch, "Search", "Search across all sessions"},
 	{ChoiceMemories, "Memories", "Browse and manage Claude memories"},
 	{ChoicePlans, "Plans", "Browse and clean up Claude plans"},
+	{ChoiceTimeline, "Timeline", "See which sessions were active each day"},
+	{ChoiceDupes, "Duplicates", "Find repeated sessions and keep one of each"},
+	{ChoiceSecrets, "Secrets", "Find tokens and keys in transcripts, memories and plans"},
 	{ChoicePrune, "Prune", "Delete sessions with no messages"},
 }
 
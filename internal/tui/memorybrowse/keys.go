@@ -17,6 +17,7 @@ type keyMap struct {
 	SelAll   key.Binding
 	DeselAll key.Binding
 	Delete   key.Binding
+	Note     key.Binding
 	Yes      key.Binding
 	No       key.Binding
 }
@@ -79,6 +80,10 @@ func newKeyMap() keyMap {
 			key.WithKeys("d"),
 			key.WithHelp("d", "delete selected"),
 		),
+		Note: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "edit note"),
+		),
 		Yes: key.NewBinding(
 			key.WithKeys("y"),
 			key.WithHelp("y", "yes"),
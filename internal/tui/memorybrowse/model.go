@@ -71,6 +71,10 @@ type Model struct {
 	// Delete
 	deleteResults []memory.DeleteResult
 
+	// Live updates
+	watching   bool   // watchPrint holds a baseline
+	watchPrint uint64 // fingerprint of the files the lists were loaded from
+
 	status     string
 	BackToHome bool
 	width      int
@@ -109,6 +113,7 @@ func New(mode StartMode) Model {
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		tea.RequestBackgroundColor,
+		watchCmd(),
 		func() tea.Msg { return startLoadMsg{} },
 	)
 }
@@ -318,6 +323,9 @@ func (m Model) viewMemories() string {
 		if mod != "" {
 			detail += " • " + mod
 		}
+		if mem.Note != "" {
+			detail += " • has note"
+		}
 		b.WriteString(fmt.Sprintf("      %s\n", m.theme.Dim.Render(detail)))
 	}
 
@@ -396,7 +404,11 @@ func (m Model) viewMemoryContent() string {
 		b.WriteString(m.theme.Dim.Render(fmt.Sprintf("Line %d/%d (%.0f%%)", m.scrollOffset+1, len(lines), pct)))
 		b.WriteString("  ")
 	}
-	b.WriteString(m.theme.Help.Render("j/k: scroll • q/esc: back"))
+	if m.status != "" {
+		b.WriteString(m.theme.Error.Render(m.status))
+		b.WriteString("  ")
+	}
+	b.WriteString(m.theme.Help.Render("j/k: scroll • N: edit note • q/esc: back"))
 
 	return b.String()
 }
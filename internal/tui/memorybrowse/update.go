@@ -2,7 +2,10 @@ package memorybrowse
 
 import (
 	"fmt"
+	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"charm.land/bubbles/v2/key"
 	"charm.land/bubbles/v2/progress"
@@ -10,8 +13,11 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/glamour/v2"
 
+	"github.com/baz-sh/clsm/internal/claude"
 	"github.com/baz-sh/clsm/internal/memory"
+	"github.com/baz-sh/clsm/internal/meta"
 	"github.com/baz-sh/clsm/internal/tui/theme"
+	"github.com/baz-sh/clsm/internal/watch"
 )
 
 // Messages for async operations.
@@ -26,9 +32,72 @@ type loadProgressMsg memory.LoadProgress
 type memoriesLoadedMsg []memory.Memory
 type loadErrorMsg struct{ err error }
 type deleteResultMsg []memory.DeleteResult
+type noteEditedMsg struct {
+	path string
+	err  error
+}
+
+// watchMsg carries the fingerprint of the memory files; see watchCmd.
+type watchMsg uint64
+
+// projectsReloadedMsg and memoriesReloadedMsg carry lists reloaded after a
+// change on disk. They are applied in place.
+type projectsReloadedMsg struct {
+	projects []memory.MemoryProject
+	err      error
+}
+
+type memoriesReloadedMsg struct {
+	project  memory.MemoryProject
+	memories []memory.Memory
+	err      error
+}
 
 // --- Async command launchers ---
 
+// editNoteCmd opens the note for a memory in $EDITOR.
+func editNoteCmd(mem memory.Memory) tea.Cmd {
+	path, err := meta.PrepareNote(meta.NoteMemory, meta.MemoryNoteKey(mem.ProjectDir, mem.FileName))
+	if err != nil {
+		return func() tea.Msg { return noteEditedMsg{err: err} }
+	}
+	editor := claude.Editor()
+	c := exec.Command(editor, path)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return noteEditedMsg{path: path, err: err}
+	})
+}
+
+// watchCmd fingerprints the memory files after watch.Interval.
+func watchCmd() tea.Cmd {
+	return tea.Tick(watch.Interval, func(time.Time) tea.Msg {
+		var fp watch.Fingerprint
+		for _, r := range claude.Roots() {
+			fp.Add(r, filepath.Join(r.ProjectsDir(), "*", "memory", "*.md"))
+		}
+		return watchMsg(fp.Sum())
+	})
+}
+
+// reloadCmd reloads the list shown in the current phase, or returns nil if
+// there is none.
+func (m Model) reloadCmd() tea.Cmd {
+	switch m.phase {
+	case phaseProjects:
+		return func() tea.Msg {
+			projects, err := memory.ListProjects()
+			return projectsReloadedMsg{projects, err}
+		}
+	case phaseMemories:
+		proj := m.selectedProject
+		return func() tea.Msg {
+			memories, err := memory.ListMemories(proj.Profile, proj.DirName)
+			return memoriesReloadedMsg{proj, memories, err}
+		}
+	}
+	return nil
+}
+
 func startLoadWithProgress(m *Model) tea.Cmd {
 	progressCh := make(chan memory.LoadProgress, 10)
 	resultCh := make(chan projectsResultMsg, 1)
@@ -58,9 +127,9 @@ func listenForLoadUpdates(progressCh <-chan memory.LoadProgress, resultCh <-chan
 	}
 }
 
-func loadMemoriesCmd(projectDir string) tea.Cmd {
+func loadMemoriesCmd(proj memory.MemoryProject) tea.Cmd {
 	return func() tea.Msg {
-		memories, err := memory.ListMemories(projectDir)
+		memories, err := memory.ListMemories(proj.Profile, proj.DirName)
 		if err != nil {
 			return loadErrorMsg{err}
 		}
@@ -103,6 +172,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.progress, cmd = m.progress.Update(msg)
 		return m, cmd
+	case watchMsg:
+		next := watchCmd()
+		if !m.watching {
+			m.watching = true
+			m.watchPrint = uint64(msg)
+			return m, next
+		}
+		if uint64(msg) == m.watchPrint {
+			return m, next
+		}
+		if reload := m.reloadCmd(); reload != nil {
+			m.watchPrint = uint64(msg)
+			return m, tea.Batch(next, reload)
+		}
+		return m, next
+	case projectsReloadedMsg:
+		if m.phase != phaseProjects || msg.err != nil {
+			m.watchPrint = 0 // try again on the next tick
+			return m, nil
+		}
+		m.replaceProjects(msg.projects)
+		return m, nil
+	case memoriesReloadedMsg:
+		if m.phase != phaseMemories || (m.selectedProject.Profile != msg.project.Profile || m.selectedProject.DirName != msg.project.DirName) || msg.err != nil {
+			m.watchPrint = 0
+			return m, nil
+		}
+		m.replaceMemories(msg.memories)
+		return m, nil
 	}
 
 	switch m.phase {
@@ -210,7 +308,7 @@ func (m Model) updateProjects(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.phase = phaseLoadingMemories
 			m.filtering = false
 			m.filter.SetValue("")
-			return m, tea.Batch(m.spinner.Tick, loadMemoriesCmd(m.selectedProject.DirName))
+			return m, tea.Batch(m.spinner.Tick, loadMemoriesCmd(m.selectedProject))
 		case key.Matches(msg, m.keys.Search):
 			m.filtering = true
 			m.filter.SetValue("")
@@ -297,7 +395,7 @@ func (m Model) updateMemories(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			idx := m.filteredMems[m.memCursor]
 			m.viewingMemory = m.memories[idx]
-			m.renderedContent = renderMarkdown(m.viewingMemory.Content, m.isDark, m.width)
+			m.renderedContent = renderMarkdown(withNote(m.viewingMemory.Content, m.viewingMemory.Note), m.isDark, m.width)
 			m.scrollOffset = 0
 			m.phase = phaseViewMemory
 			return m, nil
@@ -341,6 +439,28 @@ func (m Model) updateMemories(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m Model) updateViewMemory(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case noteEditedMsg:
+		if msg.err != nil {
+			m.status = "Note: " + msg.err.Error()
+			return m, nil
+		}
+		if err := meta.TidyNote(msg.path); err != nil {
+			m.status = "Note: " + err.Error()
+			return m, nil
+		}
+		note, err := meta.ReadNote(meta.NoteMemory, meta.MemoryNoteKey(m.viewingMemory.ProjectDir, m.viewingMemory.FileName))
+		if err != nil {
+			m.status = "Note: " + err.Error()
+			return m, nil
+		}
+		m.viewingMemory.Note = note
+		for i := range m.memories {
+			if m.memories[i].FullPath == m.viewingMemory.FullPath {
+				m.memories[i].Note = note
+			}
+		}
+		m.renderedContent = renderMarkdown(withNote(m.viewingMemory.Content, note), m.isDark, m.width)
+		return m, nil
 	case tea.KeyPressMsg:
 		lines := strings.Split(m.renderedContent, "\n")
 		viewHeight := m.height - 6
@@ -356,6 +476,9 @@ func (m Model) updateViewMemory(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Quit), key.Matches(msg, m.keys.Back):
 			m.phase = phaseMemories
 			return m, nil
+		case key.Matches(msg, m.keys.Note):
+			m.status = ""
+			return m, editNoteCmd(m.viewingMemory)
 		case key.Matches(msg, m.keys.Down):
 			if m.scrollOffset < maxScroll {
 				m.scrollOffset++
@@ -491,6 +614,48 @@ func (m Model) updateFilterInput(msg tea.Msg, isProjects bool) (tea.Model, tea.C
 	return m, cmd
 }
 
+// replaceProjects swaps in a reloaded project list, keeping the filter and
+// the project under the cursor.
+func (m *Model) replaceProjects(projects []memory.MemoryProject) {
+	var cursorDir string
+	if len(m.filteredProjs) > 0 {
+		cursorDir = m.projects[m.filteredProjs[m.projCursor]].DirName
+	}
+	m.projects = projects
+	m.applyFilter(true)
+	for vi, idx := range m.filteredProjs {
+		if m.projects[idx].DirName == cursorDir {
+			m.projCursor = vi
+		}
+	}
+}
+
+// replaceMemories swaps in a reloaded memory list, keeping the filter, the
+// selection and the memory under the cursor.
+func (m *Model) replaceMemories(memories []memory.Memory) {
+	var cursorPath string
+	if len(m.filteredMems) > 0 {
+		cursorPath = m.memories[m.filteredMems[m.memCursor]].FullPath
+	}
+	selected := make(map[string]bool, len(m.selected))
+	for i := range m.selected {
+		selected[m.memories[i].FullPath] = true
+	}
+	m.memories = memories
+	m.selected = make(map[int]bool, len(selected))
+	for i, mem := range m.memories {
+		if selected[mem.FullPath] {
+			m.selected[i] = true
+		}
+	}
+	m.applyFilter(false)
+	for vi, idx := range m.filteredMems {
+		if m.memories[idx].FullPath == cursorPath {
+			m.memCursor = vi
+		}
+	}
+}
+
 func (m *Model) applyFilter(isProjects bool) {
 	term := strings.ToLower(m.filter.Value())
 
@@ -500,7 +665,7 @@ func (m *Model) applyFilter(isProjects bool) {
 		} else {
 			m.filteredProjs = m.filteredProjs[:0]
 			for i, p := range m.projects {
-				if strings.Contains(strings.ToLower(p.Path), term) {
+				if p.Matches(term) {
 					m.filteredProjs = append(m.filteredProjs, i)
 				}
 			}
@@ -517,10 +682,7 @@ func (m *Model) applyFilter(isProjects bool) {
 		} else {
 			m.filteredMems = m.filteredMems[:0]
 			for i, mem := range m.memories {
-				searchable := strings.ToLower(
-					mem.Name + " " + mem.Description + " " + mem.Type + " " + mem.FileName,
-				)
-				if strings.Contains(searchable, term) {
+				if mem.Matches(term) {
 					m.filteredMems = append(m.filteredMems, i)
 				}
 			}
@@ -534,6 +696,15 @@ func (m *Model) applyFilter(isProjects bool) {
 	}
 }
 
+// withNote puts a clsm note above markdown content so both render
+// together.
+func withNote(content, note string) string {
+	if note == "" {
+		return content
+	}
+	return "**Note:**\n\n" + note + "\n\n---\n\n" + content
+}
+
 // renderMarkdown renders markdown content using glamour.
 // Falls back to raw content on error.
 func renderMarkdown(content string, isDark bool, width int) string {
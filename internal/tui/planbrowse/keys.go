@@ -18,6 +18,7 @@ type keyMap struct {
 	DeselAll key.Binding
 	Delete   key.Binding
 	Edit     key.Binding
+	Note     key.Binding
 	Yes      key.Binding
 	No       key.Binding
 }
@@ -84,6 +85,10 @@ func newKeyMap() keyMap {
 			key.WithKeys("e"),
 			key.WithHelp("e", "open in $EDITOR"),
 		),
+		Note: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "edit note"),
+		),
 		Yes: key.NewBinding(
 			key.WithKeys("y"),
 			key.WithHelp("y", "yes"),
@@ -50,6 +50,11 @@ type Model struct {
 	// Delete
 	deleteResults []plan.DeleteResult
 
+	// Live updates
+	watching   bool   // watchPrint holds a baseline
+	watchPrint uint64 // fingerprint of the files the list was loaded from
+
+	status     string
 	BackToHome bool
 	width      int
 	height     int
@@ -80,6 +85,7 @@ func New() Model {
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		tea.RequestBackgroundColor,
+		watchCmd(),
 		func() tea.Msg { return startLoadMsg{} },
 	)
 }
@@ -182,6 +188,9 @@ func (m Model) viewPlans() string {
 			details = append(details, mod)
 		}
 		details = append(details, formatSize(p.Size))
+		if p.Note != "" {
+			details = append(details, "has note")
+		}
 		b.WriteString(fmt.Sprintf("      %s\n", m.theme.Dim.Render(strings.Join(details, " • "))))
 
 		// Context line (if available).
@@ -264,7 +273,11 @@ func (m Model) viewPlanContent() string {
 		b.WriteString(m.theme.Dim.Render(fmt.Sprintf("Line %d/%d (%.0f%%)", m.scrollOffset+1, len(lines), pct)))
 		b.WriteString("  ")
 	}
-	b.WriteString(m.theme.Help.Render("j/k: scroll • e: open in $EDITOR • q/esc: back"))
+	if m.status != "" {
+		b.WriteString(m.theme.Error.Render(m.status))
+		b.WriteString("  ")
+	}
+	b.WriteString(m.theme.Help.Render("j/k: scroll • e: open in $EDITOR • N: edit note • q/esc: back"))
 
 	return b.String()
 }
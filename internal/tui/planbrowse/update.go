@@ -1,17 +1,22 @@
 package planbrowse
 
 import (
-	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"charm.land/bubbles/v2/key"
 	"charm.land/bubbles/v2/spinner"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/glamour/v2"
 
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/meta"
 	"github.com/baz-sh/clsm/internal/plan"
 	"github.com/baz-sh/clsm/internal/tui/theme"
+	"github.com/baz-sh/clsm/internal/watch"
 )
 
 // Messages for async operations.
@@ -23,8 +28,21 @@ type plansResultMsg struct {
 }
 
 type editorFinishedMsg struct{ err error }
+type noteEditedMsg struct {
+	path string
+	err  error
+}
 type deleteResultMsg []plan.DeleteResult
 
+// watchMsg carries the fingerprint of the plan files; see watchCmd.
+type watchMsg uint64
+
+// plansReloadedMsg carries the plan list reloaded after a change on disk.
+type plansReloadedMsg struct {
+	plans []plan.Plan
+	err   error
+}
+
 // --- Async command launchers ---
 
 func loadPlansCmd() tea.Cmd {
@@ -34,6 +52,24 @@ func loadPlansCmd() tea.Cmd {
 	}
 }
 
+// watchCmd fingerprints the plan files after watch.Interval.
+func watchCmd() tea.Cmd {
+	return tea.Tick(watch.Interval, func(time.Time) tea.Msg {
+		var fp watch.Fingerprint
+		for _, r := range claude.Roots() {
+			fp.Add(r, filepath.Join(r.Plans, "*.md"))
+		}
+		return watchMsg(fp.Sum())
+	})
+}
+
+func reloadPlansCmd() tea.Cmd {
+	return func() tea.Msg {
+		plans, err := plan.ListPlans()
+		return plansReloadedMsg{plans, err}
+	}
+}
+
 func deletePlansCmd(plans []plan.Plan) tea.Cmd {
 	return func() tea.Msg {
 		results := plan.Delete(plans)
@@ -57,6 +93,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.String() == "ctrl+c" {
 			return m, tea.Quit
 		}
+	case watchMsg:
+		next := watchCmd()
+		if !m.watching {
+			m.watching = true
+			m.watchPrint = uint64(msg)
+			return m, next
+		}
+		if uint64(msg) == m.watchPrint || m.phase != phasePlans {
+			return m, next
+		}
+		m.watchPrint = uint64(msg)
+		return m, tea.Batch(next, reloadPlansCmd())
+	case plansReloadedMsg:
+		if m.phase != phasePlans || msg.err != nil {
+			m.watchPrint = 0 // try again on the next tick
+			return m, nil
+		}
+		m.replacePlans(msg.plans)
+		return m, nil
 	}
 
 	switch m.phase {
@@ -149,13 +204,13 @@ func (m Model) updatePlans(msg tea.Msg) (tea.Model, tea.Cmd) {
 			idx := m.filteredPlans[m.cursor]
 			m.viewingPlan = m.plans[idx]
 			// Read full file content for viewing.
-			data, err := os.ReadFile(m.viewingPlan.FullPath)
+			data, err := claude.For(m.viewingPlan.Profile).ReadFile(m.viewingPlan.FullPath)
 			if err != nil {
 				m.viewingContent = "Error reading file: " + err.Error()
 			} else {
 				m.viewingContent = string(data)
 			}
-			m.renderedContent = renderMarkdown(m.viewingContent, m.isDark, m.width)
+			m.renderedContent = renderMarkdown(withNote(m.viewingContent, m.viewingPlan.Note), m.isDark, m.width)
 			m.scrollOffset = 0
 			m.phase = phaseViewPlan
 			return m, nil
@@ -199,12 +254,34 @@ func (m Model) updatePlans(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m Model) updateViewPlan(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case noteEditedMsg:
+		if msg.err != nil {
+			m.status = "Note: " + msg.err.Error()
+			return m, nil
+		}
+		if err := meta.TidyNote(msg.path); err != nil {
+			m.status = "Note: " + err.Error()
+			return m, nil
+		}
+		note, err := meta.ReadNote(meta.NotePlan, m.viewingPlan.FileName)
+		if err != nil {
+			m.status = "Note: " + err.Error()
+			return m, nil
+		}
+		m.viewingPlan.Note = note
+		for i := range m.plans {
+			if m.plans[i].FullPath == m.viewingPlan.FullPath {
+				m.plans[i].Note = note
+			}
+		}
+		m.renderedContent = renderMarkdown(withNote(m.viewingContent, note), m.isDark, m.width)
+		return m, nil
 	case editorFinishedMsg:
 		// Re-read file in case it was edited.
-		data, err := os.ReadFile(m.viewingPlan.FullPath)
+		data, err := claude.For(m.viewingPlan.Profile).ReadFile(m.viewingPlan.FullPath)
 		if err == nil {
 			m.viewingContent = string(data)
-			m.renderedContent = renderMarkdown(m.viewingContent, m.isDark, m.width)
+			m.renderedContent = renderMarkdown(withNote(m.viewingContent, m.viewingPlan.Note), m.isDark, m.width)
 		}
 		return m, nil
 	case tea.KeyPressMsg:
@@ -223,14 +300,23 @@ func (m Model) updateViewPlan(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.phase = phasePlans
 			return m, nil
 		case key.Matches(msg, m.keys.Edit):
-			editor := os.Getenv("EDITOR")
-			if editor == "" {
-				editor = "vi"
-			}
-			c := exec.Command(editor, m.viewingPlan.FullPath)
+			done := audit.TrackEdit(audit.KindPlan, m.viewingPlan.FileName, m.viewingPlan.FullPath)
+			c := exec.Command(claude.Editor(), m.viewingPlan.FullPath)
 			return m, tea.ExecProcess(c, func(err error) tea.Msg {
+				done()
 				return editorFinishedMsg{err: err}
 			})
+		case key.Matches(msg, m.keys.Note):
+			m.status = ""
+			path, err := meta.PrepareNote(meta.NotePlan, m.viewingPlan.FileName)
+			if err != nil {
+				m.status = "Note: " + err.Error()
+				return m, nil
+			}
+			c := exec.Command(claude.Editor(), path)
+			return m, tea.ExecProcess(c, func(err error) tea.Msg {
+				return noteEditedMsg{path: path, err: err}
+			})
 		case key.Matches(msg, m.keys.Down):
 			if m.scrollOffset < maxScroll {
 				m.scrollOffset++
@@ -352,6 +438,32 @@ func (m Model) updateFilterInput(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// replacePlans swaps in a reloaded plan list, keeping the filter, the
+// selection and the plan under the cursor.
+func (m *Model) replacePlans(plans []plan.Plan) {
+	var cursorPath string
+	if len(m.filteredPlans) > 0 {
+		cursorPath = m.plans[m.filteredPlans[m.cursor]].FullPath
+	}
+	selected := make(map[string]bool, len(m.selected))
+	for i := range m.selected {
+		selected[m.plans[i].FullPath] = true
+	}
+	m.plans = plans
+	m.selected = make(map[int]bool, len(selected))
+	for i, p := range m.plans {
+		if selected[p.FullPath] {
+			m.selected[i] = true
+		}
+	}
+	m.applyFilter()
+	for vi, idx := range m.filteredPlans {
+		if m.plans[idx].FullPath == cursorPath {
+			m.cursor = vi
+		}
+	}
+}
+
 func (m *Model) applyFilter() {
 	term := strings.ToLower(m.filter.Value())
 
@@ -360,10 +472,7 @@ func (m *Model) applyFilter() {
 	} else {
 		m.filteredPlans = m.filteredPlans[:0]
 		for i, p := range m.plans {
-			searchable := strings.ToLower(
-				p.Title + " " + p.Context + " " + p.ProjectHint + " " + p.FileName,
-			)
-			if strings.Contains(searchable, term) {
+			if p.Matches(term) {
 				m.filteredPlans = append(m.filteredPlans, i)
 			}
 		}
@@ -378,6 +487,15 @@ func (m *Model) applyFilter() {
 
 // --- Helpers ---
 
+// withNote puts a clsm note above markdown content so both render
+// together.
+func withNote(content, note string) string {
+	if note == "" {
+		return content
+	}
+	return "**Note:**\n\n" + note + "\n\n---\n\n" + content
+}
+
 func renderMarkdown(content string, isDark bool, width int) string {
 	style := "dark"
 	if !isDark {
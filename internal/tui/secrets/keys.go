@@ -0,0 +1,51 @@
+package secrets
+
+import "charm.land/bubbles/v2/key"
+
+type keyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Top    key.Binding
+	Bottom key.Binding
+	Select key.Binding
+	Edit   key.Binding
+	Back   key.Binding
+	Quit   key.Binding
+}
+
+func newKeyMap() keyMap {
+	return keyMap{
+		Up: key.NewBinding(
+			key.WithKeys("k", "up"),
+			key.WithHelp("k/↑", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("j", "down"),
+			key.WithHelp("j/↓", "down"),
+		),
+		Top: key.NewBinding(
+			key.WithKeys("g", "home"),
+			key.WithHelp("g", "first"),
+		),
+		Bottom: key.NewBinding(
+			key.WithKeys("G", "end"),
+			key.WithHelp("G", "last"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter", "l"),
+			key.WithHelp("enter", "show hit"),
+		),
+		Edit: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "open in $EDITOR"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "backspace", "h"),
+			key.WithHelp("esc", "back"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
@@ -0,0 +1,243 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/spinner"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/baz-sh/clsm/internal/secrets"
+	"github.com/baz-sh/clsm/internal/tui/theme"
+)
+
+type phase int
+
+const (
+	phaseScanning phase = iota
+	phaseList
+	phaseDetail
+)
+
+// Model is the Bubble Tea model for reviewing secret scan findings.
+type Model struct {
+	phase   phase
+	keys    keyMap
+	isDark  bool
+	theme   theme.Theme
+	spinner spinner.Model
+
+	opts     secrets.Options
+	findings []secrets.Finding
+	cursor   int
+	offset   int
+
+	status     string
+	BackToHome bool
+	width      int
+	height     int
+}
+
+// New creates a secrets Model that scans what opts selects.
+func New(opts secrets.Options) Model {
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+
+	return Model{
+		phase:   phaseScanning,
+		keys:    newKeyMap(),
+		theme:   theme.New(true),
+		spinner: sp,
+		opts:    opts,
+		width:   80,
+		height:  24,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(
+		tea.RequestBackgroundColor,
+		func() tea.Msg { return startScanMsg{} },
+	)
+}
+
+func (m Model) View() tea.View {
+	var content string
+	switch m.phase {
+	case phaseScanning:
+		content = fmt.Sprintf("%s Scanning for secrets...\n", m.spinner.View())
+	case phaseList:
+		content = m.viewList()
+	case phaseDetail:
+		content = m.viewDetail()
+	}
+	v := tea.NewView(content)
+	v.AltScreen = true
+	return v
+}
+
+// WantsBackToHome returns true if the user quit to return to the home menu.
+func (m Model) WantsBackToHome() bool {
+	return m.BackToHome
+}
+
+// --- View helpers ---
+
+func (m Model) header(b *strings.Builder) {
+	b.WriteString(m.theme.Title.Render("clsm — Secrets"))
+	b.WriteString("  ")
+	if m.opts.Project == "" {
+		b.WriteString(m.theme.Breadcrumb.Render("all projects"))
+	} else {
+		b.WriteString(m.theme.Breadcrumb.Render(shortenPath(m.opts.Project)))
+	}
+	if len(m.findings) > 0 {
+		b.WriteString("  ")
+		b.WriteString(m.theme.Count.Render(fmt.Sprintf("[%d/%d, %d distinct]",
+			m.cursor+1, len(m.findings), secrets.Distinct(m.findings))))
+	}
+	b.WriteString("\n\n")
+}
+
+// listHeight returns the number of findings that fit on screen.
+func (m Model) listHeight() int {
+	return max(m.height-6, 1)
+}
+
+func (m Model) viewList() string {
+	var b strings.Builder
+	m.header(&b)
+
+	if len(m.findings) == 0 {
+		b.WriteString(m.theme.Dim.Render("  No secrets found.") + "\n\n")
+		if m.status != "" {
+			b.WriteString(m.theme.Dim.Render(m.status) + "\n")
+		}
+		b.WriteString(m.theme.Help.Render("q/esc: back"))
+		return b.String()
+	}
+
+	end := min(m.offset+m.listHeight(), len(m.findings))
+	for i := m.offset; i < end; i++ {
+		f := m.findings[i]
+		prefix := "  "
+		if i == m.cursor {
+			prefix = m.theme.Cursor.Render("> ")
+		}
+		value := fmt.Sprintf("%-20s", f.Masked)
+		desc := fmt.Sprintf("%-22s", truncate(f.Description, 22))
+		where := truncate(location(f), m.width-len(value)-len(desc)-8)
+		if i == m.cursor {
+			where = m.theme.Cursor.Render(where)
+		}
+		b.WriteString(fmt.Sprintf("%s%s  %s  %s\n", prefix, m.theme.Error.Render(value), m.theme.Dim.Render(desc), where))
+	}
+
+	b.WriteString("\n")
+	if m.status != "" {
+		b.WriteString(m.theme.Dim.Render(m.status) + "\n")
+	}
+	b.WriteString(m.theme.Help.Render("j/k: navigate • enter: show hit • e: open in $EDITOR • q/esc: back"))
+	return b.String()
+}
+
+func (m Model) viewDetail() string {
+	var b strings.Builder
+	m.header(&b)
+	f := m.findings[m.cursor]
+
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		b.WriteString(m.theme.Dim.Render(fmt.Sprintf("%-12s", label)))
+		b.WriteString(value)
+		b.WriteString("\n")
+	}
+	field("Secret", m.theme.Error.Render(f.Masked)+"  "+m.theme.Dim.Render(f.Description))
+	field("Fingerprint", f.Fingerprint)
+	switch f.Source {
+	case secrets.SourceSession, secrets.SourceSubagent:
+		field("Session", f.SessionID+"  "+firstLine(f.SessionTitle))
+		if f.Source == secrets.SourceSubagent {
+			field("", "in a subagent transcript")
+		}
+	default:
+		field("Source", f.Source)
+	}
+	field("Project", shortenPath(f.ProjectPath))
+	field("File", fmt.Sprintf("%s:%d", shortenPath(f.Path), f.Line))
+	if !f.Time.IsZero() {
+		field("Time", f.Time.Local().Format("2006-01-02 15:04:05"))
+	}
+
+	b.WriteString("\n")
+	for _, line := range strings.Split(f.Context, "\n") {
+		line = truncate(line, m.width-4)
+		if strings.Contains(line, f.Masked) {
+			b.WriteString(m.theme.Bold.Render("│ " + line))
+		} else {
+			b.WriteString(m.theme.Dim.Render("│ " + line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if m.status != "" {
+		b.WriteString(m.theme.Dim.Render(m.status) + "\n")
+	}
+	b.WriteString(m.theme.Help.Render("j/k: previous/next hit • e: open in $EDITOR • esc: back to list • q: quit"))
+	return b.String()
+}
+
+// location names where a finding is: the session title or the file name,
+// and the line.
+func location(f secrets.Finding) string {
+	switch f.Source {
+	case secrets.SourceSession, secrets.SourceSubagent:
+		title := firstLine(f.SessionTitle)
+		if f.Source == secrets.SourceSubagent {
+			title += " (subagent)"
+		}
+		return fmt.Sprintf("%s:%d", title, f.Line)
+	}
+	name := f.Path
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	return fmt.Sprintf("%s %s:%d", f.Source, name, f.Line)
+}
+
+// --- Utilities ---
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+func truncate(s string, max int) string {
+	if max < 4 {
+		max = 4
+	}
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-1]) + "…"
+}
+
+func shortenPath(path string) string {
+	home, _ := strings.CutPrefix(path, "/Users/")
+	if home != path {
+		parts := strings.SplitN(home, "/", 2)
+		if len(parts) == 2 {
+			return "~/" + parts[1]
+		}
+		return "~"
+	}
+	return path
+}
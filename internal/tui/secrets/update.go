@@ -0,0 +1,190 @@
+package secrets
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/spinner"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/baz-sh/clsm/internal/audit"
+	"github.com/baz-sh/clsm/internal/claude"
+	"github.com/baz-sh/clsm/internal/secrets"
+	"github.com/baz-sh/clsm/internal/tui/theme"
+)
+
+// --- Messages ---
+
+type startScanMsg struct{}
+
+type scanResultMsg struct {
+	findings []secrets.Finding
+	err      error
+}
+
+type editorFinishedMsg struct{ err error }
+
+// --- Commands ---
+
+func scanCmd(opts secrets.Options) tea.Cmd {
+	return func() tea.Msg {
+		findings, err := secrets.Scan(opts)
+		return scanResultMsg{findings, err}
+	}
+}
+
+// editCmd opens the finding's file in $EDITOR at the line of the hit.
+func editCmd(f secrets.Finding) tea.Cmd {
+	kind, id := f.Source, f.SessionID
+	switch f.Source {
+	case secrets.SourceSubagent:
+		kind = audit.KindSession
+	case secrets.SourceMemory, secrets.SourcePlan:
+		id = filepath.Base(f.Path)
+	}
+	done := audit.TrackEdit(kind, id, f.Path)
+	c := exec.Command(claude.Editor(), fmt.Sprintf("+%d", f.Line), f.Path)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		done()
+		return editorFinishedMsg{err: err}
+	})
+}
+
+// --- Update ---
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.BackgroundColorMsg:
+		m.isDark = msg.IsDark()
+		m.theme = theme.New(m.isDark)
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.clampOffset()
+		return m, nil
+	case editorFinishedMsg:
+		if msg.err != nil {
+			m.status = "Editor: " + msg.err.Error()
+		}
+		return m, nil
+	case tea.KeyPressMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	}
+
+	switch m.phase {
+	case phaseScanning:
+		return m.updateScanning(msg)
+	case phaseList:
+		return m.updateList(msg)
+	case phaseDetail:
+		return m.updateDetail(msg)
+	}
+
+	return m, nil
+}
+
+// --- Phase handlers ---
+
+func (m Model) updateScanning(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case startScanMsg:
+		return m, tea.Batch(m.spinner.Tick, scanCmd(m.opts))
+
+	case scanResultMsg:
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+		}
+		m.findings = msg.findings
+		m.phase = phaseList
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m Model) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	if key.Matches(keyMsg, m.keys.Quit) || key.Matches(keyMsg, m.keys.Back) {
+		m.BackToHome = true
+		return m, tea.Quit
+	}
+	if len(m.findings) == 0 {
+		return m, nil
+	}
+
+	m.status = ""
+	switch {
+	case key.Matches(keyMsg, m.keys.Select):
+		m.phase = phaseDetail
+	case key.Matches(keyMsg, m.keys.Edit):
+		return m, editCmd(m.findings[m.cursor])
+	default:
+		m.move(keyMsg)
+	}
+	return m, nil
+}
+
+func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+
+	m.status = ""
+	switch {
+	case key.Matches(keyMsg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(keyMsg, m.keys.Back):
+		m.phase = phaseList
+	case key.Matches(keyMsg, m.keys.Edit):
+		return m, editCmd(m.findings[m.cursor])
+	default:
+		m.move(keyMsg)
+	}
+	return m, nil
+}
+
+// --- Helpers ---
+
+// move handles the cursor keys shared by the list and the detail view.
+func (m *Model) move(msg tea.KeyPressMsg) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(m.findings)-1 {
+			m.cursor++
+		}
+	case key.Matches(msg, m.keys.Top):
+		m.cursor = 0
+	case key.Matches(msg, m.keys.Bottom):
+		m.cursor = len(m.findings) - 1
+	}
+	m.clampOffset()
+}
+
+// clampOffset scrolls the list so the cursor is visible.
+func (m *Model) clampOffset() {
+	h := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+h {
+		m.offset = m.cursor - h + 1
+	}
+}
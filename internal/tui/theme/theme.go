@@ -21,6 +21,7 @@ type Theme struct {
 	Bold       lipgloss.Style
 	Check      lipgloss.Style
 	Uncheck    lipgloss.Style
+	Heat       [5]lipgloss.Style // heatmap shades, from no activity to most
 }
 
 // New creates a Theme resolved for the given dark-mode flag.
@@ -43,5 +44,9 @@ func New(isDark bool) Theme {
 	t.Bold = lipgloss.NewStyle().Bold(true)
 	t.Check = lipgloss.NewStyle().Foreground(resolve("128", "170")).SetString("[x]")
 	t.Uncheck = lipgloss.NewStyle().Foreground(resolve("247", "241")).SetString("[ ]")
+	heat := [5][2]string{{"252", "237"}, {"151", "22"}, {"114", "28"}, {"71", "34"}, {"28", "40"}}
+	for i, c := range heat {
+		t.Heat[i] = lipgloss.NewStyle().Foreground(resolve(c[0], c[1]))
+	}
 	return t
 }
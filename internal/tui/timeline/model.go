@@ -0,0 +1,279 @@
+package timeline
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/spinner"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/baz-sh/clsm/internal/timeline"
+	"github.com/baz-sh/clsm/internal/tui/theme"
+)
+
+type phase int
+
+const (
+	phaseLoading phase = iota
+	phaseHeatmap
+	phaseDay
+)
+
+// maxWeeks is the longest period the heatmap shows.
+const maxWeeks = 52
+
+// Model is the Bubble Tea model for the activity timeline TUI.
+type Model struct {
+	phase   phase
+	keys    keyMap
+	isDark  bool
+	theme   theme.Theme
+	spinner spinner.Model
+
+	all      []timeline.Day // every project
+	days     []timeline.Day // the current project only
+	byDate   map[string]int // date key -> index into days
+	projects []string       // project paths with activity
+	project  string         // project path term; empty for all projects
+	cursor   time.Time      // selected day, local midnight
+
+	// Day view
+	dayCursor int
+
+	status     string
+	BackToHome bool
+	width      int
+	height     int
+}
+
+// New creates a timeline Model. A non-empty project limits it to projects
+// whose path contains the term.
+func New(project string) Model {
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+
+	return Model{
+		phase:   phaseLoading,
+		keys:    newKeyMap(),
+		theme:   theme.New(true),
+		spinner: sp,
+		project: project,
+		cursor:  today(),
+		width:   80,
+		height:  24,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(
+		tea.RequestBackgroundColor,
+		func() tea.Msg { return startLoadMsg{} },
+	)
+}
+
+func (m Model) View() tea.View {
+	var content string
+	switch m.phase {
+	case phaseLoading:
+		content = fmt.Sprintf("%s Reading session activity...\n", m.spinner.View())
+	case phaseHeatmap:
+		content = m.viewHeatmap()
+	case phaseDay:
+		content = m.viewDay()
+	}
+	v := tea.NewView(content)
+	v.AltScreen = true
+	return v
+}
+
+// WantsBackToHome returns true if the user quit to return to the home menu.
+func (m Model) WantsBackToHome() bool {
+	return m.BackToHome
+}
+
+// --- View helpers ---
+
+// weeks returns the number of weeks that fit across the screen.
+func (m Model) weeks() int {
+	w := (m.width - 6) / 2
+	if w > maxWeeks {
+		w = maxWeeks
+	}
+	if w < 4 {
+		w = 4
+	}
+	return w
+}
+
+func (m Model) grid() timeline.Grid {
+	return timeline.NewGrid(m.days, today(), m.weeks())
+}
+
+// selectedDay returns the activity on the selected day, if any.
+func (m Model) selectedDay() (timeline.Day, bool) {
+	i, ok := m.byDate[m.cursor.Format("2006-01-02")]
+	if !ok {
+		return timeline.Day{Date: m.cursor}, false
+	}
+	return m.days[i], true
+}
+
+func (m Model) header(b *strings.Builder) {
+	b.WriteString(m.theme.Title.Render("clsm — Timeline"))
+	b.WriteString("  ")
+	if m.project == "" {
+		b.WriteString(m.theme.Breadcrumb.Render("all projects"))
+	} else {
+		b.WriteString(m.theme.Breadcrumb.Render(shortenPath(m.project)))
+	}
+	b.WriteString("\n\n")
+}
+
+func (m Model) viewHeatmap() string {
+	var b strings.Builder
+	m.header(&b)
+
+	g := m.grid()
+	now := today()
+	labels := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	b.WriteString("     " + m.theme.Dim.Render(g.MonthLabels(2)) + "\n")
+	for wd := 0; wd < 7; wd++ {
+		b.WriteString(" " + m.theme.Dim.Render(fmt.Sprintf("%-4s", labels[wd])))
+		for w := 0; w < g.Weeks; w++ {
+			date := g.Date(w, wd)
+			switch {
+			case date.After(now):
+				b.WriteString("  ")
+			case date.Equal(m.cursor):
+				b.WriteString(m.theme.Cursor.Render("◆ "))
+			default:
+				b.WriteString(m.theme.Heat[g.Level(g.Counts[w][wd])].Render("■ "))
+			}
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("     " + m.theme.Dim.Render("less "))
+	for _, s := range m.theme.Heat {
+		b.WriteString(s.Render("■ "))
+	}
+	b.WriteString(m.theme.Dim.Render("more") + "\n\n")
+
+	// Preview of the selected day.
+	d, ok := m.selectedDay()
+	b.WriteString(m.theme.Bold.Render(d.Date.Format("Monday 2 January 2006")))
+	if !ok {
+		b.WriteString(m.theme.Dim.Render("  no activity") + "\n")
+	} else {
+		b.WriteString(m.theme.Dim.Render(fmt.Sprintf("  %d session(s), %d messages", len(d.Sessions), d.Messages)) + "\n")
+		room := m.height - 17
+		for i, a := range d.Sessions {
+			if i == room && i < len(d.Sessions) {
+				b.WriteString(m.theme.Dim.Render(fmt.Sprintf("  … %d more (enter to open)", len(d.Sessions)-i)) + "\n")
+				break
+			}
+			b.WriteString(m.activityLine(a, false) + "\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if m.status != "" {
+		b.WriteString(m.theme.Dim.Render(m.status) + "\n")
+	}
+	b.WriteString(m.theme.Help.Render("h/l: week • j/k: day • t: today • enter: open day • p: next project • q/esc: back"))
+	return b.String()
+}
+
+// activityLine renders one session's activity on a day.
+func (m Model) activityLine(a timeline.Activity, selected bool) string {
+	span := a.First.Format("15:04") + "–" + a.Last.Format("15:04")
+	prefix := "  "
+	title := firstLine(a.Session.Title())
+	if selected {
+		prefix = m.theme.Cursor.Render("> ")
+		title = m.theme.Cursor.Render(truncate(title, m.width-30))
+	} else {
+		title = truncate(title, m.width-30)
+	}
+	msgs := m.theme.Count.Render(fmt.Sprintf("[%d msgs]", a.Messages))
+	return fmt.Sprintf("%s%s  %s %s", prefix, m.theme.Dim.Render(span), title, msgs)
+}
+
+// dayPageSize returns the number of sessions that fit in the day view.
+func (m Model) dayPageSize() int {
+	ps := (m.height - 6) / 2
+	if ps < 1 {
+		ps = 1
+	}
+	return ps
+}
+
+func (m Model) viewDay() string {
+	var b strings.Builder
+	m.header(&b)
+
+	d, _ := m.selectedDay()
+	b.WriteString(m.theme.Bold.Render(d.Date.Format("Monday 2 January 2006")))
+	b.WriteString(m.theme.Dim.Render(fmt.Sprintf("  %d session(s), %d messages", len(d.Sessions), d.Messages)))
+	b.WriteString("\n\n")
+
+	ps := m.dayPageSize()
+	start := m.dayCursor / ps * ps
+	end := min(start+ps, len(d.Sessions))
+	for i := start; i < end; i++ {
+		a := d.Sessions[i]
+		b.WriteString(m.activityLine(a, i == m.dayCursor) + "\n")
+		s := a.Session
+		detail := shortenPath(s.ProjectPath)
+		if s.GitBranch != "" {
+			detail += " • " + s.GitBranch
+		}
+		detail += " • " + s.SessionID[:min(8, len(s.SessionID))]
+		b.WriteString("               " + m.theme.Dim.Render(detail) + "\n")
+	}
+	if len(d.Sessions) == 0 {
+		b.WriteString(m.theme.Dim.Render("  No sessions were active on this day.") + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.theme.Help.Render("j/k: navigate • h/l: previous/next active day • q/esc: back"))
+	return b.String()
+}
+
+// --- Utilities ---
+
+func today() time.Time {
+	y, mo, d := time.Now().Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, time.Local)
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+func truncate(s string, max int) string {
+	if max < 4 {
+		max = 4
+	}
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-1] + "…"
+}
+
+func shortenPath(path string) string {
+	home, _ := strings.CutPrefix(path, "/Users/")
+	if home != path {
+		parts := strings.SplitN(home, "/", 2)
+		if len(parts) == 2 {
+			return "~/" + parts[1]
+		}
+		return "~"
+	}
+	return path
+}
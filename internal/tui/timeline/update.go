@@ -0,0 +1,224 @@
+package timeline
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/spinner"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/baz-sh/clsm/internal/session"
+	"github.com/baz-sh/clsm/internal/timeline"
+	"github.com/baz-sh/clsm/internal/tui/theme"
+)
+
+// --- Messages ---
+
+type startLoadMsg struct{}
+
+type daysResultMsg struct {
+	days []timeline.Day
+	err  error
+}
+
+// --- Commands ---
+
+func loadDaysCmd() tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := session.ListAllSessions()
+		if err != nil {
+			return daysResultMsg{err: err}
+		}
+		since := today().AddDate(0, 0, -7*maxWeeks)
+		return daysResultMsg{days: timeline.Build(sessions, since)}
+	}
+}
+
+// --- Update ---
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.BackgroundColorMsg:
+		m.isDark = msg.IsDark()
+		m.theme = theme.New(m.isDark)
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.clampCursor()
+		return m, nil
+	case tea.KeyPressMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	}
+
+	switch m.phase {
+	case phaseLoading:
+		return m.updateLoading(msg)
+	case phaseHeatmap:
+		return m.updateHeatmap(msg)
+	case phaseDay:
+		return m.updateDay(msg)
+	}
+
+	return m, nil
+}
+
+// --- Phase handlers ---
+
+func (m Model) updateLoading(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case startLoadMsg:
+		return m, tea.Batch(m.spinner.Tick, loadDaysCmd())
+
+	case daysResultMsg:
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+		}
+		m.all = msg.days
+		m.projects = projectPaths(m.all)
+		m.applyProject()
+		m.phase = phaseHeatmap
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m Model) updateHeatmap(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(keyMsg, m.keys.Quit), key.Matches(keyMsg, m.keys.Back):
+		m.BackToHome = true
+		return m, tea.Quit
+	case key.Matches(keyMsg, m.keys.Up):
+		m.cursor = m.cursor.AddDate(0, 0, -1)
+	case key.Matches(keyMsg, m.keys.Down):
+		m.cursor = m.cursor.AddDate(0, 0, 1)
+	case key.Matches(keyMsg, m.keys.Left):
+		m.cursor = m.cursor.AddDate(0, 0, -7)
+	case key.Matches(keyMsg, m.keys.Right):
+		m.cursor = m.cursor.AddDate(0, 0, 7)
+	case key.Matches(keyMsg, m.keys.Today):
+		m.cursor = today()
+	case key.Matches(keyMsg, m.keys.Project):
+		m.project = nextProject(m.projects, m.project)
+		m.applyProject()
+	case key.Matches(keyMsg, m.keys.Open):
+		m.dayCursor = 0
+		m.phase = phaseDay
+	}
+	m.clampCursor()
+	return m, nil
+}
+
+func (m Model) updateDay(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	d, _ := m.selectedDay()
+	switch {
+	case key.Matches(keyMsg, m.keys.Quit), key.Matches(keyMsg, m.keys.Back):
+		m.phase = phaseHeatmap
+	case key.Matches(keyMsg, m.keys.Up):
+		if m.dayCursor > 0 {
+			m.dayCursor--
+		}
+	case key.Matches(keyMsg, m.keys.Down):
+		if m.dayCursor < len(d.Sessions)-1 {
+			m.dayCursor++
+		}
+	case key.Matches(keyMsg, m.keys.Left), key.Matches(keyMsg, m.keys.Right):
+		// Step to the previous or next day that had activity.
+		step := 1
+		if key.Matches(keyMsg, m.keys.Left) {
+			step = -1
+		}
+		i := sort.Search(len(m.days), func(i int) bool { return !m.days[i].Date.Before(m.cursor) })
+		if _, ok := m.byDate[m.cursor.Format("2006-01-02")]; ok || step < 0 {
+			i += step
+		}
+		if i >= 0 && i < len(m.days) {
+			m.cursor = m.days[i].Date
+			m.dayCursor = 0
+		}
+	}
+	return m, nil
+}
+
+// --- Helpers ---
+
+// applyProject restricts the days to the current project.
+func (m *Model) applyProject() {
+	m.days = m.all
+	if m.project != "" {
+		term := strings.ToLower(m.project)
+		m.days = timeline.Filter(m.all, func(s session.Session) bool {
+			return strings.Contains(strings.ToLower(s.ProjectPath), term)
+		})
+	}
+	m.byDate = make(map[string]int, len(m.days))
+	for i, d := range m.days {
+		m.byDate[d.Key()] = i
+	}
+}
+
+// clampCursor keeps the selected day within the heatmap.
+func (m *Model) clampCursor() {
+	g := m.grid()
+	now := today()
+	if m.cursor.After(now) {
+		m.cursor = now
+	}
+	if m.cursor.Before(g.Start) {
+		m.cursor = g.Start
+	}
+	// Normalise across DST changes.
+	y, mo, d := m.cursor.Date()
+	m.cursor = time.Date(y, mo, d, 0, 0, 0, 0, time.Local)
+}
+
+// projectPaths returns the distinct project paths in days, sorted.
+func projectPaths(days []timeline.Day) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, d := range days {
+		for _, a := range d.Sessions {
+			if p := a.Session.ProjectPath; p != "" && !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// nextProject returns the project after current in paths, cycling through
+// all projects ("") at the end.
+func nextProject(paths []string, current string) string {
+	if current == "" {
+		if len(paths) == 0 {
+			return ""
+		}
+		return paths[0]
+	}
+	for i, p := range paths {
+		if p == current && i+1 < len(paths) {
+			return paths[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+// Package watch notices changes to Claude Code's files by polling. Polling
+// needs no platform support, and stat-ing the few hundred files clsm lists
+// every couple of seconds is cheap.
+package watch
+
+import (
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+// Interval is how often the TUIs check their lists for changes.
+const Interval = 2 * time.Second
+
+// Fingerprint collects the names, sizes and modification times of files.
+// Its Sum changes whenever one of them is created, written or removed.
+type Fingerprint struct {
+	files []string
+}
+
+// Add adds the files in r matching the glob patterns.
+func (f *Fingerprint) Add(r *claude.Root, patterns ...string) {
+	for _, p := range patterns {
+		matches, _ := r.Glob(p)
+		for _, m := range matches {
+			if info, err := r.Stat(m); err == nil {
+				f.add(m, info)
+			}
+		}
+	}
+}
+
+// AddFile adds one of clsm's own files, which are kept outside any Root.
+func (f *Fingerprint) AddFile(path string) {
+	if info, err := os.Stat(path); err == nil {
+		f.add(path, info)
+	}
+}
+
+func (f *Fingerprint) add(path string, info fs.FileInfo) {
+	f.files = append(f.files, path+"\x00"+strconv.FormatInt(info.Size(), 10)+"\x00"+strconv.FormatInt(info.ModTime().UnixNano(), 10))
+}
+
+// Sum returns a hash of the files added so far.
+func (f *Fingerprint) Sum() uint64 {
+	sort.Strings(f.files)
+	h := fnv.New64a()
+	for _, s := range f.files {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
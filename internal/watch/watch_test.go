@@ -0,0 +1,52 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/baz-sh/clsm/internal/claude"
+)
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	r := claude.New(dir)
+	a, b := filepath.Join(dir, "a.md"), filepath.Join(dir, "b.md")
+	sum := func() uint64 {
+		var fp Fingerprint
+		fp.Add(r, filepath.Join(dir, "*.md"))
+		return fp.Sum()
+	}
+
+	if err := os.WriteFile(a, []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	before := sum()
+	if sum() != before {
+		t.Fatal("fingerprint changed without a change")
+	}
+	steps := []struct {
+		name string
+		do   func() error
+	}{
+		{"create", func() error { return os.WriteFile(b, []byte("b"), 0o644) }},
+		{"write", func() error { return os.WriteFile(a, []byte("aa"), 0o644) }},
+		{"touch", func() error {
+			later := time.Now().Add(time.Minute)
+			return os.Chtimes(a, later, later)
+		}},
+		{"remove", func() error { return os.Remove(b) }},
+		{"create unmatched", func() error { return os.WriteFile(filepath.Join(dir, "c.txt"), nil, 0o644) }},
+	}
+	for _, s := range steps {
+		if err := s.do(); err != nil {
+			t.Fatal(err)
+		}
+		after := sum()
+		if changed := after != before; changed != (s.name != "create unmatched") {
+			t.Errorf("%s: changed = %v", s.name, changed)
+		}
+		before = after
+	}
+}